@@ -1,69 +1,486 @@
 // config/config.go
 //
-// Paquete config: carga y expone la configuración de la aplicación.
-// 
+// Paquete config: carga, valida y expone la configuración de la aplicación.
+//
 // Convenciones:
-//   - Se utiliza godotenv para cargar variables desde un archivo `.env` en desarrollo.
-//   - En producción, se espera que las variables de entorno estén definidas en el sistema.
+//   - Los valores se resuelven por capas, de menor a mayor prioridad:
+//     defaults < archivo YAML/TOML (--config o CONFIG_FILE) < variables de entorno < flags.
+//   - Se utiliza godotenv para cargar variables desde un archivo `.env` en desarrollo;
+//     esas variables cuentan como capa de entorno.
+//   - Cada opción se declara una sola vez en el registro `fields` (ver fields.go),
+//     que define su clave en archivo, variable de entorno, flag, default y parser.
+//   - Load reporta todos los problemas juntos (errors.Join), no sólo el primero.
 //   - El objeto Config centraliza los valores necesarios para arrancar el servidor
-//     y la conexión a MongoDB.
+//     y el almacenamiento.
 package config
 
 import (
-	"log"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
-// Config representa la configuración base de la aplicación.
+// Config representa la configuración tipada de la aplicación.
 //
 // Campos:
-//   - Port: puerto HTTP en el que se levanta la API (ej. ":8080").
-//   - MongoURI: URI de conexión a MongoDB (ej. "mongodb://localhost:27017").
-//   - MongoDB: nombre de la base de datos a utilizar.
+//   - Port: puerto HTTP en el que se levanta la API (ej. "4000").
+//   - Storage: driver de almacenamiento y sus opciones.
+//   - Mongo: conexión a MongoDB (driver "mongo").
+//   - HTTP: timeouts del servidor, apagado ordenado y orígenes CORS.
+//   - DB: límites de las consultas (timeout por operación, tamaño de página).
+//   - Cache: políticas Cache-Control de las lecturas de posts.
+//   - Trash: retención y frecuencia de purga de la papelera.
+//   - Revisions: retención del historial de revisiones de posts.
+//   - Scheduler: frecuencia de la publicación/despublicación programada.
+//   - Tags: alias de etiquetas.
+//   - Reactions: tipos de reacción de los lectores.
+//   - Admin: credencial de las rutas de administración.
+//   - Log: nivel de logging.
+//   - Tracing: exporter y muestreo de OpenTelemetry.
+//   - TLS: certificado y clave para servir HTTPS (ambos vacíos = HTTP plano).
+//   - PrintConfig: --print-config; main imprime la configuración efectiva y termina.
+//   - Args: argumentos posicionales restantes (ej. ["migrate", "status"]).
 type Config struct {
-	Port     string
-	MongoURI string
-	MongoDB  string
+	Port      string
+	Storage   StorageConfig
+	Mongo     MongoConfig
+	HTTP      HTTPConfig
+	DB        DBConfig
+	Cache     CacheConfig
+	Trash     TrashConfig
+	Revisions RevisionsConfig
+	Scheduler SchedulerConfig
+	Tags      TagsConfig
+	Reactions ReactionsConfig
+	Admin     AdminConfig
+	Log       LogConfig
+	Tracing   TracingConfig
+	TLS       TLSConfig
+
+	PrintConfig bool
+	Args        []string
+}
+
+// StorageConfig selecciona el driver de almacenamiento.
+//
+// Campos:
+//   - Driver: "mongo" (default), "memory" o "sqlite".
+//   - MemorySnapshot: archivo JSON que el driver "memory" carga al iniciar y
+//     vuelca al apagar (opcional).
+//   - SQLitePath: archivo de base de datos del driver "sqlite" (ej. "./blog.db").
+//   - AutoMigrate: aplica las migraciones Mongo pendientes al arrancar.
+type StorageConfig struct {
+	Driver         string
+	MemorySnapshot string
+	SQLitePath     string
+	AutoMigrate    bool
+}
+
+// MongoConfig agrupa la conexión a MongoDB.
+//
+// Campos:
+//   - URI: URI de conexión (ej. "mongodb://localhost:27017"). Se considera secreto.
+//   - Database: nombre de la base de datos a utilizar.
+type MongoConfig struct {
+	URI      string
+	Database string
+}
+
+// HTTPConfig agrupa los parámetros del http.Server.
+//
+// Campos:
+//   - ReadTimeout / WriteTimeout / IdleTimeout: timeouts del servidor.
+//   - ShutdownTimeout: plazo para drenar conexiones y cerrar recursos al apagar.
+//   - ShutdownDelay: espera previa al cierre del listener con /healthz en "draining".
+//   - CORSOrigins: orígenes permitidos por CORS.
+type HTTPConfig struct {
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+	IdleTimeout     time.Duration
+	ShutdownTimeout time.Duration
+	ShutdownDelay   time.Duration
+	CORSOrigins     []string
+}
+
+// DBConfig agrupa los límites aplicados por repositorios y servicios.
+//
+// Campos:
+//   - Timeout: tiempo máximo por operación contra el almacenamiento.
+//   - MaxPageLimit: tope del parámetro limit en listados.
+type DBConfig struct {
+	Timeout      time.Duration
+	MaxPageLimit int
+}
+
+// CacheConfig agrupa los valores de Cache-Control por tipo de respuesta.
+//
+// Campos:
+//   - PublishedPost / DraftPost: GET /api/posts/:id según el post esté publicado o no.
+//   - PublishedList: GET /api/posts?published=true.
+//   - MixedList: GET /api/posts sin ese filtro (puede incluir borradores).
+//
+// Un valor vacío omite el header.
+type CacheConfig struct {
+	PublishedPost string
+	DraftPost     string
+	PublishedList string
+	MixedList     string
+}
+
+// TrashConfig agrupa las opciones de la papelera de posts.
+//
+// Campos:
+//   - Retention: tiempo que un post eliminado permanece recuperable; 0 desactiva la purga.
+//   - PurgeInterval: frecuencia del worker de purga.
+type TrashConfig struct {
+	Retention     time.Duration
+	PurgeInterval time.Duration
+}
+
+// SchedulerConfig agrupa las opciones del worker de publicación programada.
+//
+// Campos:
+//   - Interval: cada cuánto se aplican los publishAt/unpublishAt vencidos; 0 desactiva
+//     el worker (las programaciones vencidas se aplican igual al editar el post).
+//
+// Con varias instancias sólo trabaja la que tiene el lease "post_scheduler", que vence
+// a los 3 intervalos si su dueño deja de renovarlo.
+type SchedulerConfig struct {
+	Interval time.Duration
+}
+
+// TagsConfig agrupa la normalización de etiquetas.
+//
+// Campos:
+//   - Aliases: etiqueta alias → canónica (ej. golang → go), ya normalizadas. Se declara
+//     como lista "alias=canónica" separada por coma (ej. TAG_ALIASES=golang=go,js=javascript);
+//     en archivo, como string o lista de strings con el mismo formato.
+//
+// Una canónica no puede ser a su vez un alias: los alias no se encadenan.
+type TagsConfig struct {
+	Aliases map[string]string
+}
+
+// ReactionsConfig agrupa las reacciones de los lectores a los posts.
+//
+// Campos:
+//   - Kinds: tipos de reacción aceptados en /api/posts/:id/reactions/:kind, en el orden
+//     en que se muestran (ej. REACTION_KINDS=like,love,party). Cada tipo usa minúsculas,
+//     dígitos, "-" o "_" (hasta 32 caracteres).
+//
+// Quitar un tipo no borra sus reacciones: dejan de mostrarse y de aceptarse.
+type ReactionsConfig struct {
+	Kinds []string
+}
+
+// RevisionsConfig agrupa la retención del historial de revisiones.
+//
+// Campos:
+//   - MaxCount: revisiones conservadas por post; 0 = sin límite.
+//   - MaxAge: antigüedad máxima de una revisión; 0 = sin límite.
+//
+// Ambos límites se combinan: se borra lo que exceda cualquiera de los dos.
+type RevisionsConfig struct {
+	MaxCount int
+	MaxAge   time.Duration
+}
+
+// AdminConfig agrupa las credenciales de administración.
+//
+// Campos:
+//   - Token: token Bearer exigido por las rutas de administración (ej. borrado
+//     definitivo). Vacío las deshabilita. Se considera secreto.
+type AdminConfig struct {
+	Token string
+}
+
+// LogConfig agrupa las opciones de logging.
+//
+// Campos:
+//   - Level: "debug" | "info" | "warn" | "error".
+type LogConfig struct {
+	Level string
+}
+
+// TracingConfig agrupa las opciones de OpenTelemetry.
+//
+// Campos:
+//   - Exporter: "none" | "stdout" | "otlp".
+//   - OTLPEndpoint: host:puerto del colector OTLP/HTTP (exporter "otlp").
+//   - OTLPInsecure: envía por HTTP plano (colector local).
+//   - ServiceName: service.name reportado en los spans.
+//   - SampleRatio: fracción de trazas raíz muestreadas (0..1).
+type TracingConfig struct {
+	Exporter     string
+	OTLPEndpoint string
+	OTLPInsecure bool
+	ServiceName  string
+	SampleRatio  float64
+}
+
+// TLSConfig agrupa el certificado para servir HTTPS.
+//
+// Campos:
+//   - CertFile / KeyFile: rutas PEM; deben definirse ambas o ninguna.
+type TLSConfig struct {
+	CertFile string
+	KeyFile  string
+}
+
+// Enabled indica si se debe servir HTTPS.
+func (t TLSConfig) Enabled() bool {
+	return t.CertFile != "" && t.KeyFile != ""
 }
 
-// Load inicializa la configuración cargando primero el archivo `.env` (si existe)
-// y luego leyendo variables de entorno.
+// Load construye la configuración a partir de todas las capas y la valida.
+//
+// Parámetros:
+//   - args: argumentos de línea de comandos sin el nombre del programa (os.Args[1:]).
 //
 // Retorna:
-//   - *Config con los valores de Port, MongoURI y MongoDB.
+//   - *Config con todos los campos resueltos.
+//   - error con todos los problemas encontrados (flags, archivo, parseo y validación).
 //
 // Comportamiento:
-//   - Si no encuentra un archivo `.env`, muestra un warning pero no falla.
-//   - Las variables deben estar definidas en el entorno, ya sea por `.env` o por el sistema.
-//   - En producción se recomienda definir las variables directamente en el sistema
-//     y no depender de `.env`.
+//   - Si no encuentra un archivo `.env`, continúa sin él.
+//   - --config (o CONFIG_FILE) apunta a un archivo .yaml/.yml/.toml opcional.
+//   - --print-config no valida de forma estricta: permite inspeccionar una configuración inválida.
+//   - --help imprime todas las opciones con su variable de entorno y default.
 //
 // Ejemplo de archivo .env:
-//   PORT=:8080
-//   MONGODB_URI=mongodb://localhost:27017
-//   MONGODB_DB=blog
-func Load() *Config {
+//
+//	PORT=4000
+//	MONGODB_URI=mongodb://localhost:27017
+//	MONGODB_DB=blog
+//	STORAGE_DRIVER=memory
+//	CORS_ORIGINS=http://localhost:3000,https://blog.example.com
+//	DB_TIMEOUT=5s
+func Load(args []string) (*Config, error) {
 	// Cargar archivo .env si existe
 	if err := godotenv.Load(); err != nil {
-		log.Println("⚠️  No se encontró archivo .env")
+		slog.Debug("no se encontró archivo .env")
+	}
+
+	cfg := &Config{}
+	var errs []error
+
+	// Capa 1: defaults.
+	for _, f := range fields {
+		if err := f.set(cfg, f.def); err != nil {
+			errs = append(errs, fmt.Errorf("default de %s: %w", f.key, err))
+		}
+	}
+
+	// Flags: se parsean primero para conocer --config, pero se aplican al final.
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	configFile := fs.String("config", os.Getenv("CONFIG_FILE"), "archivo de configuración .yaml/.yml/.toml (env CONFIG_FILE)")
+	printConfig := fs.Bool("print-config", false, "imprime la configuración efectiva (secretos ocultos) y termina")
+	flagValues := map[string]*string{}
+	for _, f := range fields {
+		flagValues[f.key] = fs.String(f.flag, "", fmt.Sprintf("%s (env %s, default %q)", f.usage, f.env, f.def))
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fs.SetOutput(os.Stderr)
+			fs.PrintDefaults()
+		}
+		return nil, err
+	}
+	cfg.PrintConfig = *printConfig
+	cfg.Args = fs.Args()
+
+	// Capa 2: archivo.
+	if *configFile != "" {
+		values, err := readConfigFile(*configFile)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		for key, raw := range values {
+			f, ok := fieldByKey(key)
+			if !ok {
+				errs = append(errs, fmt.Errorf("%s: clave desconocida %q", *configFile, key))
+				continue
+			}
+			if err := f.set(cfg, raw); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %s: %w", *configFile, key, err))
+			}
+		}
+	}
+
+	// Capa 3: entorno.
+	for _, f := range fields {
+		if raw, ok := os.LookupEnv(f.env); ok && raw != "" {
+			if err := f.set(cfg, raw); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", f.env, err))
+			}
+		}
 	}
 
-	return &Config{
-		Port:     getenv("PORT"),
-		MongoURI: getenv("MONGODB_URI"),
-		MongoDB:  getenv("MONGODB_DB"),
+	// Capa 4: flags explícitamente indicados.
+	fs.Visit(func(fl *flag.Flag) {
+		f, ok := fieldByFlag(fl.Name)
+		if !ok {
+			return
+		}
+		if err := f.set(cfg, *flagValues[f.key]); err != nil {
+			errs = append(errs, fmt.Errorf("--%s: %w", f.flag, err))
+		}
+	})
+
+	if !cfg.PrintConfig {
+		errs = append(errs, cfg.Validate()...)
 	}
+	if len(errs) > 0 {
+		return cfg, errors.Join(errs...)
+	}
+	return cfg, nil
 }
 
-// getenv retorna el valor de una variable de entorno.
-//
-// Parámetros:
-//   - k: nombre de la variable de entorno.
-//
-// Retorna:
-//   - string con el valor de la variable; "" si no existe.
-func getenv(k string) string {
-	return os.Getenv(k)
+// Validate revisa la coherencia de la configuración y retorna todos los problemas.
+func (c *Config) Validate() []error {
+	var errs []error
+	add := func(format string, a ...any) { errs = append(errs, fmt.Errorf(format, a...)) }
+
+	if !isPort(c.Port) {
+		add("port: %q no es un puerto válido (1-65535)", c.Port)
+	}
+
+	switch c.Storage.Driver {
+	case "mongo":
+		if c.Mongo.URI == "" {
+			add("mongo.uri: requerido con storage.driver=mongo (MONGODB_URI)")
+		}
+		if c.Mongo.Database == "" {
+			add("mongo.database: requerido con storage.driver=mongo (MONGODB_DB)")
+		}
+	case "memory":
+	case "sqlite":
+		if c.Storage.SQLitePath == "" {
+			add("storage.sqlitePath: requerido con storage.driver=sqlite (SQLITE_PATH)")
+		}
+	default:
+		add("storage.driver: %q no soportado (mongo, memory, sqlite)", c.Storage.Driver)
+	}
+
+	positive := map[string]time.Duration{
+		"http.readTimeout":     c.HTTP.ReadTimeout,
+		"http.writeTimeout":    c.HTTP.WriteTimeout,
+		"http.idleTimeout":     c.HTTP.IdleTimeout,
+		"http.shutdownTimeout": c.HTTP.ShutdownTimeout,
+		"db.timeout":           c.DB.Timeout,
+	}
+	for _, f := range fields {
+		if d, ok := positive[f.key]; ok && d <= 0 {
+			add("%s: debe ser mayor que 0", f.key)
+		}
+	}
+	if c.HTTP.ShutdownDelay < 0 {
+		add("http.shutdownDelay: no puede ser negativo")
+	}
+	if c.HTTP.ShutdownDelay >= c.HTTP.ShutdownTimeout && c.HTTP.ShutdownTimeout > 0 {
+		add("http.shutdownDelay: debe ser menor que http.shutdownTimeout")
+	}
+	if len(c.HTTP.CORSOrigins) == 0 {
+		add("http.corsOrigins: se requiere al menos un origen")
+	}
+	for _, o := range c.HTTP.CORSOrigins {
+		if o != "*" && !strings.HasPrefix(o, "http://") && !strings.HasPrefix(o, "https://") {
+			add("http.corsOrigins: %q debe comenzar con http:// o https://", o)
+		}
+	}
+
+	if c.DB.MaxPageLimit <= 0 {
+		add("db.maxPageLimit: debe ser mayor que 0")
+	}
+
+	if c.Trash.Retention < 0 {
+		add("trash.retention: no puede ser negativo")
+	}
+	if c.Trash.Retention > 0 && c.Trash.PurgeInterval <= 0 {
+		add("trash.purgeInterval: debe ser mayor que 0 si trash.retention está activo")
+	}
+
+	if c.Revisions.MaxCount < 0 {
+		add("revisions.maxCount: no puede ser negativo")
+	}
+	if c.Revisions.MaxAge < 0 {
+		add("revisions.maxAge: no puede ser negativo")
+	}
+
+	if c.Scheduler.Interval < 0 {
+		add("scheduler.interval: no puede ser negativo")
+	}
+
+	aliases := make([]string, 0, len(c.Tags.Aliases))
+	for alias := range c.Tags.Aliases {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	for _, alias := range aliases {
+		canonical := c.Tags.Aliases[alias]
+		if _, chained := c.Tags.Aliases[canonical]; chained {
+			add("tags.aliases: %q apunta a %q, que también es un alias", alias, canonical)
+		}
+	}
+
+	if len(c.Reactions.Kinds) == 0 {
+		add("reactions.kinds: se requiere al menos un tipo de reacción")
+	}
+	seenKinds := map[string]bool{}
+	for _, kind := range c.Reactions.Kinds {
+		if !isReactionKind(kind) {
+			add("reactions.kinds: %q inválido (minúsculas, dígitos, - o _; hasta 32 caracteres)", kind)
+		}
+		if seenKinds[kind] {
+			add("reactions.kinds: %q repetido", kind)
+		}
+		seenKinds[kind] = true
+	}
+
+	switch c.Log.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		add("log.level: %q inválido (debug, info, warn, error)", c.Log.Level)
+	}
+
+	switch c.Tracing.Exporter {
+	case "none", "stdout":
+	case "otlp":
+		if c.Tracing.OTLPEndpoint == "" {
+			add("tracing.otlpEndpoint: requerido con tracing.exporter=otlp (TRACING_OTLP_ENDPOINT)")
+		}
+	default:
+		add("tracing.exporter: %q no soportado (none, stdout, otlp)", c.Tracing.Exporter)
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		add("tracing.sampleRatio: debe estar entre 0 y 1")
+	}
+	if c.Tracing.ServiceName == "" {
+		add("tracing.serviceName: no puede estar vacío")
+	}
+
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		add("tls: certFile y keyFile deben definirse juntos")
+	}
+	for _, tf := range []struct{ key, path string }{
+		{"tls.certFile", c.TLS.CertFile},
+		{"tls.keyFile", c.TLS.KeyFile},
+	} {
+		if tf.path == "" {
+			continue
+		}
+		if _, err := os.Stat(tf.path); err != nil {
+			add("%s: %v", tf.key, err)
+		}
+	}
+	return errs
 }
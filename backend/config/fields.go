@@ -0,0 +1,377 @@
+// config/fields.go
+//
+// Paquete config: registro de opciones y conversión entre capas.
+//
+// Convenciones:
+//   - Cada opción se declara una vez en `fields` con su clave de archivo (camelCase
+//     con puntos, ej. "http.readTimeout"), variable de entorno, flag y default.
+//   - Todas las capas entregan strings; el parser de cada opción los convierte al
+//     tipo de Config, de modo que archivo, entorno y flags aceptan el mismo formato.
+//   - Las opciones `secret` se ocultan en --print-config.
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// field describe una opción de configuración.
+type field struct {
+	key    string
+	env    string
+	flag   string
+	def    string
+	usage  string
+	secret bool
+	set    func(c *Config, raw string) error
+	get    func(c *Config) any
+}
+
+// fields es el registro completo de opciones, en el orden en que se documentan.
+var fields = []field{
+	stringField("port", "PORT", "port", "4000", "puerto HTTP",
+		func(c *Config) *string { return &c.Port }),
+
+	stringField("storage.driver", "STORAGE_DRIVER", "storage-driver", "mongo", "driver de almacenamiento: mongo, memory, sqlite",
+		func(c *Config) *string { return &c.Storage.Driver }),
+	stringField("storage.memorySnapshot", "MEMORY_SNAPSHOT", "memory-snapshot", "", "snapshot JSON del driver memory",
+		func(c *Config) *string { return &c.Storage.MemorySnapshot }),
+	stringField("storage.sqlitePath", "SQLITE_PATH", "sqlite-path", "", "archivo de base del driver sqlite",
+		func(c *Config) *string { return &c.Storage.SQLitePath }),
+	boolField("storage.autoMigrate", "AUTO_MIGRATE", "auto-migrate", "true", "aplica migraciones Mongo pendientes al arrancar",
+		func(c *Config) *bool { return &c.Storage.AutoMigrate }),
+
+	secretField(stringField("mongo.uri", "MONGODB_URI", "mongo-uri", "", "URI de conexión a MongoDB",
+		func(c *Config) *string { return &c.Mongo.URI })),
+	stringField("mongo.database", "MONGODB_DB", "mongo-db", "", "base de datos MongoDB",
+		func(c *Config) *string { return &c.Mongo.Database }),
+
+	durationField("http.readTimeout", "HTTP_READ_TIMEOUT", "http-read-timeout", "15s", "timeout de lectura HTTP",
+		func(c *Config) *time.Duration { return &c.HTTP.ReadTimeout }),
+	durationField("http.writeTimeout", "HTTP_WRITE_TIMEOUT", "http-write-timeout", "15s", "timeout de escritura HTTP",
+		func(c *Config) *time.Duration { return &c.HTTP.WriteTimeout }),
+	durationField("http.idleTimeout", "HTTP_IDLE_TIMEOUT", "http-idle-timeout", "60s", "timeout de conexiones inactivas",
+		func(c *Config) *time.Duration { return &c.HTTP.IdleTimeout }),
+	durationField("http.shutdownTimeout", "SHUTDOWN_TIMEOUT", "shutdown-timeout", "20s", "plazo del apagado ordenado",
+		func(c *Config) *time.Duration { return &c.HTTP.ShutdownTimeout }),
+	durationField("http.shutdownDelay", "SHUTDOWN_DELAY", "shutdown-delay", "0s", "espera en draining antes de cerrar el listener",
+		func(c *Config) *time.Duration { return &c.HTTP.ShutdownDelay }),
+	listField("http.corsOrigins", "CORS_ORIGINS", "cors-origins", "http://localhost:3000", "orígenes CORS separados por coma",
+		func(c *Config) *[]string { return &c.HTTP.CORSOrigins }),
+
+	durationField("db.timeout", "DB_TIMEOUT", "db-timeout", "5s", "timeout por operación de almacenamiento",
+		func(c *Config) *time.Duration { return &c.DB.Timeout }),
+	intField("db.maxPageLimit", "MAX_PAGE_LIMIT", "max-page-limit", "100", "tope de limit en listados",
+		func(c *Config) *int { return &c.DB.MaxPageLimit }),
+
+	stringField("cache.publishedPost", "CACHE_PUBLISHED_POST", "cache-published-post", "public, max-age=60", "Cache-Control de un post publicado",
+		func(c *Config) *string { return &c.Cache.PublishedPost }),
+	stringField("cache.draftPost", "CACHE_DRAFT_POST", "cache-draft-post", "private, no-store", "Cache-Control de un borrador",
+		func(c *Config) *string { return &c.Cache.DraftPost }),
+	stringField("cache.publishedList", "CACHE_PUBLISHED_LIST", "cache-published-list", "public, max-age=30", "Cache-Control de listados published=true",
+		func(c *Config) *string { return &c.Cache.PublishedList }),
+	stringField("cache.mixedList", "CACHE_MIXED_LIST", "cache-mixed-list", "private, no-cache", "Cache-Control de listados que pueden incluir borradores",
+		func(c *Config) *string { return &c.Cache.MixedList }),
+
+	durationField("trash.retention", "TRASH_RETENTION", "trash-retention", "720h", "tiempo en la papelera antes de la purga (0 = no purgar)",
+		func(c *Config) *time.Duration { return &c.Trash.Retention }),
+	durationField("trash.purgeInterval", "TRASH_PURGE_INTERVAL", "trash-purge-interval", "1h", "cada cuánto se ejecuta la purga de la papelera",
+		func(c *Config) *time.Duration { return &c.Trash.PurgeInterval }),
+
+	intField("revisions.maxCount", "REVISIONS_MAX_COUNT", "revisions-max-count", "50", "revisiones conservadas por post (0 = sin límite)",
+		func(c *Config) *int { return &c.Revisions.MaxCount }),
+	durationField("revisions.maxAge", "REVISIONS_MAX_AGE", "revisions-max-age", "0s", "antigüedad máxima de una revisión (0 = sin límite)",
+		func(c *Config) *time.Duration { return &c.Revisions.MaxAge }),
+
+	durationField("scheduler.interval", "SCHEDULER_INTERVAL", "scheduler-interval", "30s", "cada cuánto se aplican las publicaciones programadas (0 = desactivado)",
+		func(c *Config) *time.Duration { return &c.Scheduler.Interval }),
+
+	aliasField("tags.aliases", "TAG_ALIASES", "tag-aliases", "", "alias de etiquetas alias=canónica separados por coma",
+		func(c *Config) *map[string]string { return &c.Tags.Aliases }),
+
+	listField("reactions.kinds", "REACTION_KINDS", "reaction-kinds", "like,love,party", "tipos de reacción a posts separados por coma",
+		func(c *Config) *[]string { return &c.Reactions.Kinds }),
+
+	secretField(stringField("admin.token", "ADMIN_TOKEN", "admin-token", "", "token Bearer de las rutas de administración (vacío = deshabilitadas)",
+		func(c *Config) *string { return &c.Admin.Token })),
+
+	stringField("log.level", "LOG_LEVEL", "log-level", "info", "nivel de log: debug, info, warn, error",
+		func(c *Config) *string { return &c.Log.Level }),
+
+	stringField("tracing.exporter", "TRACING_EXPORTER", "tracing-exporter", "none", "exporter de trazas: none, stdout, otlp",
+		func(c *Config) *string { return &c.Tracing.Exporter }),
+	stringField("tracing.otlpEndpoint", "TRACING_OTLP_ENDPOINT", "tracing-otlp-endpoint", "localhost:4318", "host:puerto del colector OTLP/HTTP",
+		func(c *Config) *string { return &c.Tracing.OTLPEndpoint }),
+	boolField("tracing.otlpInsecure", "TRACING_OTLP_INSECURE", "tracing-otlp-insecure", "true", "OTLP sin TLS (colector local)",
+		func(c *Config) *bool { return &c.Tracing.OTLPInsecure }),
+	stringField("tracing.serviceName", "TRACING_SERVICE_NAME", "tracing-service-name", "blog-api", "service.name de los spans",
+		func(c *Config) *string { return &c.Tracing.ServiceName }),
+	floatField("tracing.sampleRatio", "TRACING_SAMPLE_RATIO", "tracing-sample-ratio", "1", "fracción de trazas muestreadas (0..1)",
+		func(c *Config) *float64 { return &c.Tracing.SampleRatio }),
+
+	stringField("tls.certFile", "TLS_CERT_FILE", "tls-cert-file", "", "certificado PEM para HTTPS",
+		func(c *Config) *string { return &c.TLS.CertFile }),
+	stringField("tls.keyFile", "TLS_KEY_FILE", "tls-key-file", "", "clave privada PEM para HTTPS",
+		func(c *Config) *string { return &c.TLS.KeyFile }),
+}
+
+// fieldByKey busca una opción por su clave de archivo.
+func fieldByKey(key string) (field, bool) {
+	for _, f := range fields {
+		if f.key == key {
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+// fieldByFlag busca una opción por su nombre de flag.
+func fieldByFlag(name string) (field, bool) {
+	for _, f := range fields {
+		if f.flag == name {
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+// stringField declara una opción de texto libre.
+func stringField(key, env, flag, def, usage string, ptr func(*Config) *string) field {
+	return field{key: key, env: env, flag: flag, def: def, usage: usage,
+		set: func(c *Config, raw string) error { *ptr(c) = strings.TrimSpace(raw); return nil },
+		get: func(c *Config) any { return *ptr(c) },
+	}
+}
+
+// boolField declara una opción booleana (formato strconv.ParseBool).
+func boolField(key, env, flag, def, usage string, ptr func(*Config) *bool) field {
+	return field{key: key, env: env, flag: flag, def: def, usage: usage,
+		set: func(c *Config, raw string) error {
+			v, err := strconv.ParseBool(strings.TrimSpace(raw))
+			if err != nil {
+				return fmt.Errorf("%q no es booleano", raw)
+			}
+			*ptr(c) = v
+			return nil
+		},
+		get: func(c *Config) any { return *ptr(c) },
+	}
+}
+
+// intField declara una opción entera.
+func intField(key, env, flag, def, usage string, ptr func(*Config) *int) field {
+	return field{key: key, env: env, flag: flag, def: def, usage: usage,
+		set: func(c *Config, raw string) error {
+			v, err := strconv.Atoi(strings.TrimSpace(raw))
+			if err != nil {
+				return fmt.Errorf("%q no es un entero", raw)
+			}
+			*ptr(c) = v
+			return nil
+		},
+		get: func(c *Config) any { return *ptr(c) },
+	}
+}
+
+// floatField declara una opción decimal.
+func floatField(key, env, flag, def, usage string, ptr func(*Config) *float64) field {
+	return field{key: key, env: env, flag: flag, def: def, usage: usage,
+		set: func(c *Config, raw string) error {
+			v, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+			if err != nil {
+				return fmt.Errorf("%q no es un número", raw)
+			}
+			*ptr(c) = v
+			return nil
+		},
+		get: func(c *Config) any { return *ptr(c) },
+	}
+}
+
+// durationField declara una duración (formato time.ParseDuration, ej. "15s").
+func durationField(key, env, flag, def, usage string, ptr func(*Config) *time.Duration) field {
+	return field{key: key, env: env, flag: flag, def: def, usage: usage,
+		set: func(c *Config, raw string) error {
+			v, err := time.ParseDuration(strings.TrimSpace(raw))
+			if err != nil {
+				return fmt.Errorf("%q no es una duración (ej. 15s, 2m)", raw)
+			}
+			*ptr(c) = v
+			return nil
+		},
+		get: func(c *Config) any { return ptr(c).String() },
+	}
+}
+
+// listField declara una lista de strings separados por coma.
+func listField(key, env, flag, def, usage string, ptr func(*Config) *[]string) field {
+	return field{key: key, env: env, flag: flag, def: def, usage: usage,
+		set: func(c *Config, raw string) error {
+			var out []string
+			for _, item := range strings.Split(raw, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					out = append(out, item)
+				}
+			}
+			*ptr(c) = out
+			return nil
+		},
+		get: func(c *Config) any { return *ptr(c) },
+	}
+}
+
+// aliasField declara un mapa de etiquetas "alias=canónica" separados por coma; ambos
+// lados se normalizan como las etiquetas de los posts (minúsculas, espacios colapsados).
+func aliasField(key, env, flag, def, usage string, ptr func(*Config) *map[string]string) field {
+	return field{key: key, env: env, flag: flag, def: def, usage: usage,
+		set: func(c *Config, raw string) error {
+			out := map[string]string{}
+			for _, item := range strings.Split(raw, ",") {
+				if strings.TrimSpace(item) == "" {
+					continue
+				}
+				alias, canonical, ok := strings.Cut(item, "=")
+				alias = strings.Join(strings.Fields(strings.ToLower(alias)), " ")
+				canonical = strings.Join(strings.Fields(strings.ToLower(canonical)), " ")
+				if !ok || alias == "" || canonical == "" {
+					return fmt.Errorf("%q no tiene el formato alias=canónica", item)
+				}
+				if alias != canonical {
+					out[alias] = canonical
+				}
+			}
+			*ptr(c) = out
+			return nil
+		},
+		get: func(c *Config) any { return *ptr(c) },
+	}
+}
+
+// secretField marca f para ocultarla en --print-config.
+func secretField(f field) field {
+	f.secret = true
+	return f
+}
+
+// readConfigFile lee un archivo YAML o TOML y lo aplana a {clave.con.puntos: valor}.
+//
+// Errores:
+//   - lectura o parseo fallidos, o extensión no soportada.
+func readConfigFile(path string) (map[string]string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+
+	doc := map[string]any{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(raw, &doc)
+	case ".toml":
+		err = toml.Unmarshal(raw, &doc)
+	default:
+		return nil, fmt.Errorf("config: %s: extensión no soportada (.yaml, .yml, .toml)", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config: %s: %w", path, err)
+	}
+
+	out := map[string]string{}
+	flattenConfig("", doc, out)
+	return out, nil
+}
+
+// flattenConfig convierte mapas anidados en claves con puntos; las listas se unen con coma.
+func flattenConfig(prefix string, v any, out map[string]string) {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, child := range t {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+			flattenConfig(key, child, out)
+		}
+	case []any:
+		items := make([]string, 0, len(t))
+		for _, item := range t {
+			items = append(items, fmt.Sprint(item))
+		}
+		out[prefix] = strings.Join(items, ",")
+	case nil:
+		out[prefix] = ""
+	default:
+		out[prefix] = fmt.Sprint(t)
+	}
+}
+
+// Redacted retorna la configuración efectiva en YAML, ocultando los secretos.
+// Las URIs conservan esquema y host pero no la contraseña.
+func (c *Config) Redacted() string {
+	doc := map[string]any{}
+	for _, f := range fields {
+		v := f.get(c)
+		if f.secret {
+			v = redact(fmt.Sprint(v))
+		}
+		parts := strings.Split(f.key, ".")
+		node := doc
+		for _, p := range parts[:len(parts)-1] {
+			child, ok := node[p].(map[string]any)
+			if !ok {
+				child = map[string]any{}
+				node[p] = child
+			}
+			node = child
+		}
+		node[parts[len(parts)-1]] = v
+	}
+
+	out, err := yaml.Marshal(doc)
+	if err != nil {
+		return fmt.Sprintf("# error serializando configuración: %v\n", err)
+	}
+	return string(out)
+}
+
+// redact oculta la contraseña de una URI; cualquier otro valor no vacío se reemplaza entero.
+func redact(v string) string {
+	if v == "" {
+		return ""
+	}
+	if u, err := url.Parse(v); err == nil && u.Scheme != "" && u.Host != "" {
+		if _, hasPass := u.User.Password(); hasPass {
+			u.User = url.UserPassword(u.User.Username(), "REDACTED")
+		}
+		return u.String()
+	}
+	return "REDACTED"
+}
+
+// isPort indica si s es un puerto TCP válido.
+func isPort(s string) bool {
+	n, err := strconv.Atoi(s)
+	return err == nil && n > 0 && n <= 65535
+}
+
+// isReactionKind indica si s es un tipo de reacción válido: 1 a 32 caracteres entre
+// minúsculas ASCII, dígitos, "-" y "_" (se usa en la URL y como clave de contador).
+func isReactionKind(s string) bool {
+	if len(s) == 0 || len(s) > 32 {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,36 @@
+// controllers/actor.go
+//
+// Paquete controllers: identificación de quién realiza una operación.
+//
+// Convenciones:
+//   - La API no autentica usuarios: el frontend envía el nombre del editor en el header
+//     X-User y se usa sólo para auditoría (deletedBy, autor de revisiones, etc.).
+//   - Sin header (o con un valor inválido) se registra "anonymous".
+package controllers
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ActorHeader es el header con el usuario que realiza la operación.
+const ActorHeader = "X-User"
+
+// anonymousActor es el actor registrado cuando el request no indica uno.
+const anonymousActor = "anonymous"
+
+// maxActorLen limita el largo (en caracteres) de un X-User.
+const maxActorLen = 100
+
+// actorFromRequest retorna el actor del request, o anonymousActor si falta o es inválido
+// (más de maxActorLen caracteres o con caracteres de control).
+func actorFromRequest(c *gin.Context) string {
+	actor := strings.TrimSpace(c.GetHeader(ActorHeader))
+	if actor == "" || utf8.RuneCountInString(actor) > maxActorLen || strings.IndexFunc(actor, unicode.IsControl) >= 0 {
+		return anonymousActor
+	}
+	return actor
+}
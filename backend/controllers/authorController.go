@@ -0,0 +1,149 @@
+// controllers/authorController.go
+//
+// Paquete controllers: capa HTTP para la entidad Author.
+//
+// Convenciones:
+//   - Mismas reglas que PostController: validación de entrada aquí, errores traducidos
+//     con writeError y lógica de negocio en services.AuthorService.
+//   - Eliminar un autor con posts responde 409; los posts deben reasignarse antes.
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"blog-api/dto"
+	"blog-api/models"
+	"blog-api/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AuthorController agrupa los handlers HTTP de autores.
+type AuthorController struct {
+	svc *services.AuthorService
+}
+
+// NewAuthorController crea el controlador de autores sobre svc.
+func NewAuthorController(svc *services.AuthorService) *AuthorController {
+	return &AuthorController{svc: svc}
+}
+
+// ListAuthors maneja GET /api/authors.
+//
+// Respuestas: 200 con los autores ordenados por nombre; 500 si falla el almacenamiento.
+func (ac *AuthorController) ListAuthors(c *gin.Context) {
+	items, err := ac.svc.ListAuthors(c.Request.Context())
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// CreateAuthor maneja POST /api/authors.
+//
+// Body: dto.AuthorDTO.
+//
+// Respuestas: 201 con Location y el autor creado; 400 si el cuerpo o el slug son
+// inválidos; 409 si el slug está en uso; 500 si falla el almacenamiento.
+func (ac *AuthorController) CreateAuthor(c *gin.Context) {
+	in, ok := bindAuthor(c)
+	if !ok {
+		return
+	}
+	created, err := ac.svc.CreateAuthor(c.Request.Context(), in)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.Header("Location", fmt.Sprintf("/api/authors/%s", created.ID.Hex()))
+	c.JSON(http.StatusCreated, created)
+}
+
+// GetAuthor maneja GET /api/authors/:id.
+//
+// Respuestas: 200 con el autor; 400 si el id es inválido; 404 si no existe.
+func (ac *AuthorController) GetAuthor(c *gin.Context) {
+	a, err := ac.svc.GetAuthor(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, a)
+}
+
+// UpdateAuthor maneja PUT /api/authors/:id.
+//
+// Body: dto.AuthorDTO (slug vacío conserva el actual).
+//
+// Respuestas: 200 con el autor actualizado (el nombre ya copiado en sus posts); 400 si
+// el cuerpo o el slug son inválidos; 404 si no existe; 409 si el slug está en uso.
+func (ac *AuthorController) UpdateAuthor(c *gin.Context) {
+	in, ok := bindAuthor(c)
+	if !ok {
+		return
+	}
+	updated, err := ac.svc.UpdateAuthor(c.Request.Context(), c.Param("id"), in)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// DeleteAuthor maneja DELETE /api/authors/:id.
+//
+// Respuestas: 204; 400 si el id es inválido; 404 si no existe; 409 si tiene posts
+// (incluidos los de la papelera).
+func (ac *AuthorController) DeleteAuthor(c *gin.Context) {
+	if err := ac.svc.DeleteAuthor(c.Request.Context(), c.Param("id")); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// bindAuthor valida el cuerpo de alta/edición; si falla responde 400 y retorna ok=false.
+func bindAuthor(c *gin.Context) (models.Author, bool) {
+	var in dto.AuthorDTO
+	if err := c.ShouldBindJSON(&in); err != nil {
+		var verrs validator.ValidationErrors
+		if errors.As(err, &verrs) {
+			writeError(c, services.Wrap(verrs, services.ErrInvalidInput, "validation"))
+			return models.Author{}, false
+		}
+		writeError(c, services.Wrap(err, services.ErrInvalidInput, "bind json"))
+		return models.Author{}, false
+	}
+	if strings.TrimSpace(in.Name) == "" {
+		writeError(c, services.Wrap(errors.New("name is blank"), services.ErrInvalidInput, "validation"))
+		return models.Author{}, false
+	}
+	return models.Author{
+		Name:      in.Name,
+		Slug:      in.Slug,
+		Bio:       in.Bio,
+		AvatarURL: in.AvatarURL,
+	}, true
+}
+
+// authorIDOrZero retorna el ObjectID enviado o cero si el cliente no lo incluyó.
+func authorIDOrZero(id *primitive.ObjectID) primitive.ObjectID {
+	if id == nil {
+		return primitive.NilObjectID
+	}
+	return *id
+}
+
+// authorIDPtr es la inversa de authorIDOrZero (cero → nil), para validar con los DTO.
+func authorIDPtr(id primitive.ObjectID) *primitive.ObjectID {
+	if id.IsZero() {
+		return nil
+	}
+	return &id
+}
@@ -0,0 +1,135 @@
+// controllers/caching.go
+//
+// Paquete controllers: GET condicional (If-None-Match / If-Modified-Since) y Cache-Control.
+//
+// Convenciones:
+//   - Un post usa su ETag de versión ("v<version>", con el hash de sus reacciones; ver
+//     preconditions.go) y Last-Modified = updatedAt o, si nunca se editó, createdAt.
+//     Reaccionar no cambia Last-Modified, así que con reacciones sólo se evalúa
+//     If-None-Match (un If-Modified-Since respondería 304 con contadores viejos).
+//   - Los listados usan un ETag fuerte con el hash SHA-256 del cuerpo JSON ("l-<hex>").
+//     No envían Last-Modified: borrar un post no cambia la fecha máxima de los restantes,
+//     y un If-Modified-Since respondería 304 con datos viejos.
+//   - If-None-Match tiene prioridad sobre If-Modified-Since (RFC 9110 §13.2.2) y se
+//     compara en forma débil.
+//   - Las políticas Cache-Control se configuran por ruta y visibilidad (CachePolicies).
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"blog-api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CachePolicies define el header Cache-Control de cada tipo de respuesta.
+//
+// Campos:
+//   - PublishedPost: GET /api/posts/:id de un post publicado (ej. "public, max-age=60").
+//   - DraftPost: GET /api/posts/:id de un borrador (ej. "private, no-store").
+//   - PublishedList: GET /api/posts con published=true (sólo contenido público).
+//   - MixedList: GET /api/posts sin ese filtro (puede incluir borradores).
+//
+// Un valor vacío omite el header.
+type CachePolicies struct {
+	PublishedPost string
+	DraftPost     string
+	PublishedList string
+	MixedList     string
+}
+
+// setCacheControl agrega Cache-Control si policy no está vacía.
+func setCacheControl(c *gin.Context, policy string) {
+	if policy != "" {
+		c.Header("Cache-Control", policy)
+	}
+}
+
+// postLastModified retorna updatedAt o, si es nil, createdAt.
+func postLastModified(post models.Post) time.Time {
+	if post.UpdatedAt != nil {
+		return *post.UpdatedAt
+	}
+	return post.CreatedAt
+}
+
+// notModified evalúa If-None-Match y, en su ausencia, If-Modified-Since.
+// lastModified cero desactiva la comparación por fecha.
+func notModified(c *gin.Context, etag string, lastModified time.Time) bool {
+	if inm := c.GetHeader("If-None-Match"); inm != "" {
+		return etagMatchesWeak(inm, etag)
+	}
+	if lastModified.IsZero() {
+		return false
+	}
+	ims, err := http.ParseTime(c.GetHeader("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	// Last-Modified tiene resolución de segundos.
+	return !lastModified.Truncate(time.Second).After(ims)
+}
+
+// etagMatchesWeak indica si alguno de los ETags de header coincide con etag
+// ignorando el prefijo débil W/ ("*" coincide siempre).
+func etagMatchesWeak(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// writePost responde un post con ETag, Last-Modified y Cache-Control según su
+// visibilidad; 304 sin cuerpo si el cliente ya tiene esa versión.
+func (pc *PostController) writePost(c *gin.Context, post models.Post) {
+	policy := pc.cache.DraftPost
+	if post.Published {
+		policy = pc.cache.PublishedPost
+	}
+	lastModified := postLastModified(post)
+
+	setPostETag(c, post)
+	c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	setCacheControl(c, policy)
+	since := lastModified
+	if post.Reactions != nil {
+		since = time.Time{}
+	}
+	if notModified(c, postETag(post), since) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.JSON(http.StatusOK, post)
+}
+
+// writeCachedJSON serializa v, calcula su ETag de contenido y responde 200 o 304.
+func writeCachedJSON(c *gin.Context, v any, policy string) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, httpError{
+			Code:    http.StatusInternalServerError,
+			Message: "Error interno",
+			TraceID: traceIDFromContext(c),
+		})
+		return
+	}
+	sum := sha256.Sum256(body)
+	etag := `"l-` + hex.EncodeToString(sum[:16]) + `"`
+
+	c.Header("ETag", etag)
+	setCacheControl(c, policy)
+	if notModified(c, etag, time.Time{}) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
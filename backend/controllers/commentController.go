@@ -0,0 +1,148 @@
+// controllers/commentController.go
+//
+// Paquete controllers: capa HTTP para comentarios y su moderación.
+//
+// Convenciones:
+//   - Mismas reglas que PostController: validación de entrada aquí, errores traducidos
+//     con writeError y lógica de negocio en services.CommentService.
+//   - Leer y comentar son públicos; la cola de moderación y moderar son rutas de
+//     administración (ver routes.SetupRoutes).
+//   - Los listados se paginan con ?cursor= (nextCursor de la respuesta anterior) y
+//     ?limit=.
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"blog-api/dto"
+	"blog-api/models"
+	"blog-api/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// CommentController agrupa los handlers HTTP de comentarios.
+type CommentController struct {
+	svc *services.CommentService
+}
+
+// NewCommentController crea el controlador de comentarios sobre svc.
+func NewCommentController(svc *services.CommentService) *CommentController {
+	return &CommentController{svc: svc}
+}
+
+// ListComments maneja GET /api/posts/:id/comments?cursor=&limit=.
+//
+// Query params:
+//   - cursor (opcional): nextCursor de la página anterior.
+//   - limit (opcional, entero > 0; default 20; máx 100): hilos por página.
+//
+// Respuestas: 200 con services.CommentPage (hilos aprobados con sus respuestas en
+// replies); 400 si el id, el cursor o limit son inválidos; 404 si el post no existe o
+// está en la papelera.
+func (cc *CommentController) ListComments(c *gin.Context) {
+	limit, ok := commentLimitParam(c)
+	if !ok {
+		return
+	}
+	page, err := cc.svc.ListComments(c.Request.Context(), c.Param("id"), strings.TrimSpace(c.Query("cursor")), limit)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// CreateComment maneja POST /api/posts/:id/comments.
+//
+// Body: dto.CommentDTO.
+//
+// Respuestas: 201 con el comentario creado (status "pending" hasta que se modere); 400
+// si el cuerpo es inválido o parentId no es un comentario aprobado del post; 404 si el
+// post no existe o está en la papelera; 409 si el post no está publicado.
+func (cc *CommentController) CreateComment(c *gin.Context) {
+	var in dto.CommentDTO
+	if !bindCommentBody(c, &in) {
+		return
+	}
+	created, err := cc.svc.CreateComment(c.Request.Context(), c.Param("id"), models.Comment{
+		Author:   strings.TrimSpace(in.Author),
+		Content:  strings.TrimSpace(in.Content),
+		ParentID: in.ParentID,
+	})
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// ListModerationQueue maneja GET /api/comments?status=&cursor=&limit=.
+//
+// Query params:
+//   - status (opcional; default "pending"): pending, approved, rejected o spam.
+//   - cursor / limit: como en ListComments.
+//
+// Respuestas: 200 con services.CommentPage (comentarios de todos los posts, del más
+// viejo al más nuevo); 400 si los parámetros son inválidos.
+func (cc *CommentController) ListModerationQueue(c *gin.Context) {
+	limit, ok := commentLimitParam(c)
+	if !ok {
+		return
+	}
+	page, err := cc.svc.ListModerationQueue(c.Request.Context(), strings.TrimSpace(c.Query("status")),
+		strings.TrimSpace(c.Query("cursor")), limit)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// ModerateComments maneja POST /api/comments/moderate.
+//
+// Body: dto.ModerateCommentsDTO.
+//
+// Respuestas: 200 con services.CommentModerationResult (comentarios que cambiaron de
+// estado); 400 si el cuerpo, algún id o el estado son inválidos.
+func (cc *CommentController) ModerateComments(c *gin.Context) {
+	var in dto.ModerateCommentsDTO
+	if !bindCommentBody(c, &in) {
+		return
+	}
+	res, err := cc.svc.ModerateComments(c.Request.Context(), in.IDs, strings.TrimSpace(in.Status), actorFromRequest(c))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// commentLimitParam lee ?limit= (0 si no viene); si es inválido responde 400 y retorna
+// false.
+func commentLimitParam(c *gin.Context) (int, bool) {
+	n, err := positiveQueryInt64(c, "limit")
+	if err != nil {
+		writeError(c, err)
+		return 0, false
+	}
+	return int(n), true
+}
+
+// bindCommentBody decodifica y valida el cuerpo en in; si falla responde 400 y retorna
+// false.
+func bindCommentBody(c *gin.Context, in any) bool {
+	if err := c.ShouldBindJSON(in); err != nil {
+		var verrs validator.ValidationErrors
+		if errors.As(err, &verrs) {
+			writeError(c, services.Wrap(verrs, services.ErrInvalidInput, "validation"))
+			return false
+		}
+		writeError(c, services.Wrap(err, services.ErrInvalidInput, "bind json"))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,79 @@
+// controllers/diff.go
+//
+// Paquete controllers: diff entre versiones de un post (GET /api/posts/:id/diff).
+//
+// Convenciones:
+//   - from/to son números de versión (revisiones o la versión vigente); ambos opcionales.
+//   - El formato se negocia por Accept: JSON estructurado por defecto, diff unificado
+//     con text/x-diff. Un Accept no soportado recibe JSON en vez de 406.
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"blog-api/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// mimeDiff es el Content-Type del diff unificado.
+const mimeDiff = "text/x-diff"
+
+// DiffPost maneja GET /api/posts/:id/diff?from=&to=.
+//
+// Query params:
+//   - from (opcional): versión de origen; default la revisión anterior a to.
+//   - to (opcional): versión de destino; default la versión vigente.
+//
+// Respuestas: 200 con PostDiff (JSON) o texto unificado (Accept: text/x-diff);
+// 400 si :id, from o to son inválidos; 404 si el post o alguna versión no existe.
+func (pc *PostController) DiffPost(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeError(c, services.ErrInvalidID)
+		return
+	}
+	from, err := positiveQueryInt64(c, "from")
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	to, err := positiveQueryInt64(c, "to")
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	diff, err := pc.svc.DiffPost(c.Request.Context(), id, from, to)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.Header("Vary", "Accept")
+	if c.NegotiateFormat(binding.MIMEJSON, mimeDiff) == mimeDiff {
+		setCacheControl(c, pc.cache.DraftPost)
+		c.Data(http.StatusOK, mimeDiff+"; charset=utf-8", []byte(diff.Unified()))
+		return
+	}
+	writeCachedJSON(c, diff, pc.cache.DraftPost)
+}
+
+// positiveQueryInt64 lee el query param name como entero positivo (0 si no viene).
+func positiveQueryInt64(c *gin.Context, name string) (int64, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || n <= 0 {
+		if err == nil {
+			err = errors.New(raw)
+		}
+		return 0, services.Wrap(err, services.ErrInvalidInput, name+" must be a positive integer")
+	}
+	return n, nil
+}
@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDiffNegotiatesUnifiedFormat(t *testing.T) {
+	api := newTestAPI(t)
+	id := api.createPost(t, "Introducción a Go")
+	w := api.do(http.MethodPatch, "/api/posts/"+id, `{"content":"contenido en español"}`,
+		map[string]string{"Content-Type": mimeMergePatch})
+	if w.Code != http.StatusOK {
+		t.Fatalf("patch: status %d: %s", w.Code, w.Body)
+	}
+
+	w = api.do(http.MethodGet, "/api/posts/"+id+"/diff", "", map[string]string{"Accept": mimeDiff})
+	if w.Code != http.StatusOK {
+		t.Fatalf("diff: status %d: %s", w.Code, w.Body)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, mimeDiff) {
+		t.Fatalf("Content-Type %q, want %s", ct, mimeDiff)
+	}
+	want := "--- content@v1\n+++ content@v2\n@@ -1 +1 @@\n-contenido\n+contenido en español\n"
+	if w.Body.String() != want {
+		t.Fatalf("unified:\n%s\nwant:\n%s", w.Body, want)
+	}
+
+	w = api.do(http.MethodGet, "/api/posts/"+id+"/diff", "", map[string]string{"Accept": "application/json"})
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"words":[{"op":"equal","text":"contenido"}]`) {
+		t.Fatalf("diff JSON: status %d: %s", w.Code, w.Body)
+	}
+}
@@ -0,0 +1,71 @@
+// controllers/healthController.go
+//
+// Paquete controllers: endpoints de salud del proceso.
+// Convenciones:
+//   - /livez sólo indica que el proceso responde (no consulta dependencias),
+//     para que el orquestador no reinicie la instancia por una caída de Mongo.
+//   - /readyz ejecuta los HealthCheck del almacenamiento y responde 503 si
+//     alguno crítico falla o si la instancia está drenando.
+//   - El estado "draining" lo fija main al iniciar el apagado ordenado.
+package controllers
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"blog-api/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthController expone el estado de vida y de disponibilidad del proceso.
+type HealthController struct {
+	checks   []services.HealthCheck
+	draining atomic.Bool
+}
+
+// NewHealthController crea el controlador en estado "ok" con los chequeos
+// de dependencias usados por /readyz.
+func NewHealthController(checks []services.HealthCheck) *HealthController {
+	return &HealthController{checks: checks}
+}
+
+// SetDraining marca la instancia como en proceso de apagado.
+func (hc *HealthController) SetDraining() {
+	hc.draining.Store(true)
+}
+
+// Healthz maneja GET /healthz (compatibilidad con el healthcheck de Docker).
+// - 200 {"status":"ok"} en operación normal.
+// - 503 {"status":"draining"} durante el apagado ordenado.
+func (hc *HealthController) Healthz(c *gin.Context) {
+	if hc.draining.Load() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "draining"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Livez maneja GET /livez.
+// - Siempre 200 {"status":"ok"} mientras el proceso atienda requests.
+func (hc *HealthController) Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readyz maneja GET /readyz.
+// - 200 con services.ReadinessReport si todas las dependencias críticas responden.
+// - 503 con el mismo reporte si alguna crítica falla.
+// - 503 {"status":"draining"} durante el apagado ordenado.
+func (hc *HealthController) Readyz(c *gin.Context) {
+	if hc.draining.Load() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "draining"})
+		return
+	}
+
+	report := services.RunHealthChecks(c.Request.Context(), hc.checks)
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
@@ -0,0 +1,228 @@
+// controllers/patch.go
+//
+// Paquete controllers: actualizaciones parciales de posts (PATCH /api/posts/:id).
+//
+// Convenciones:
+//   - El formato del cuerpo se elige por Content-Type; uno no soportado responde 415
+//     con el header Accept-Patch listando los formatos válidos.
+//   - application/merge-patch+json (RFC 7396): el cuerpo es un objeto con los campos
+//     a modificar; los ausentes se conservan. Sólo se validan los campos presentes.
+//   - Los campos de sólo lectura (id, version, fechas, status) y los desconocidos se
+//     rechazan con 400 en vez de ignorarse, para no ocultar errores del cliente; status
+//     cambia con POST /api/posts/:id/transitions.
+//   - null elimina el campo según RFC 7396; sólo tiene sentido para tags y para la
+//     programación (publishAt/unpublishAt); los demás son obligatorios en el modelo y
+//     responden 400.
+//   - application/json-patch+json (RFC 6902): arreglo de operaciones que services aplica
+//     sobre el post y luego valida con las reglas de PUT (validatePost). Un "test"
+//     fallido responde 409 aunque venga If-Match: 412 queda para un If-Match vencido.
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+
+	"blog-api/dto"
+	"blog-api/models"
+	"blog-api/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Content-Types de PATCH soportados.
+const (
+	mimeMergePatch = "application/merge-patch+json"
+	mimeJSONPatch  = "application/json-patch+json"
+)
+
+// acceptPatch es el valor del header Accept-Patch (formatos de PATCH soportados).
+const acceptPatch = mimeMergePatch + ", " + mimeJSONPatch
+
+// patchableFields son los campos que un merge patch puede modificar;
+// el valor indica si acepta null (eliminar).
+var patchableFields = map[string]bool{
+	"title":       false,
+	"slug":        false,
+	"author":      false,
+	"authorId":    false,
+	"content":     false,
+	"tags":        true,
+	"published":   false,
+	"publishAt":   true,
+	"unpublishAt": true,
+}
+
+// readOnlyFields son campos del Post que el servidor administra.
+var readOnlyFields = map[string]bool{
+	"_id":         true,
+	"id":          true,
+	"oldSlugs":    true,
+	"version":     true,
+	"createdAt":   true,
+	"updatedAt":   true,
+	"publishedAt": true,
+	"status":      true,
+}
+
+// PatchPostByID maneja PATCH /api/posts/:id.
+// - Despacha según Content-Type (merge patch o JSON Patch); 415 si no es soportado.
+// - Delegar en PostService.PatchPostByID / JSONPatchPostByID (regla de PublishedAt de PUT).
+// - Honra If-Match: 412 si la versión no coincide.
+// - Responde 200 con el documento actualizado y su nuevo ETag.
+func (pc *PostController) PatchPostByID(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeError(c, services.ErrInvalidID)
+		return
+	}
+
+	switch c.ContentType() {
+	case mimeMergePatch:
+		pc.mergePatch(c, id)
+	case mimeJSONPatch:
+		pc.jsonPatch(c, id)
+	default:
+		c.Header("Accept-Patch", acceptPatch)
+		c.JSON(http.StatusUnsupportedMediaType, httpError{
+			Code:    http.StatusUnsupportedMediaType,
+			Message: "Content-Type no soportado para PATCH",
+			Details: "use " + acceptPatch,
+			TraceID: traceIDFromContext(c),
+		})
+	}
+}
+
+// mergePatch aplica un JSON Merge Patch al post id.
+func (pc *PostController) mergePatch(c *gin.Context, id string) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		writeError(c, services.Wrap(err, services.ErrInvalidInput, "read body"))
+		return
+	}
+	patch, err := parseMergePatch(body)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	updated, err := pc.svc.PatchPostByID(c.Request.Context(), id, patch, actorFromRequest(c), ifMatchVersions(c))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	setPostETag(c, updated)
+	c.JSON(http.StatusOK, updated)
+}
+
+// jsonPatch aplica un JSON Patch al post id.
+func (pc *PostController) jsonPatch(c *gin.Context, id string) {
+	var ops []services.PatchOperation
+	if err := json.NewDecoder(c.Request.Body).Decode(&ops); err != nil {
+		writeError(c, services.Wrap(err, services.ErrInvalidInput, "json patch must be an array of operations"))
+		return
+	}
+
+	updated, err := pc.svc.JSONPatchPostByID(c.Request.Context(), id, ops, validatePost, actorFromRequest(c), ifMatchVersions(c))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	setPostETag(c, updated)
+	c.JSON(http.StatusOK, updated)
+}
+
+// validatePost aplica a un post ya parchado las validaciones de dto.UpdatePostDTO.
+func validatePost(p models.Post) error {
+	in := dto.UpdatePostDTO{
+		Title:       p.Title,
+		Slug:        p.Slug,
+		Author:      p.Author,
+		AuthorID:    authorIDPtr(p.AuthorID),
+		Content:     p.Content,
+		Tags:        p.Tags,
+		Published:   p.Published,
+		PublishAt:   p.PublishAt,
+		UnpublishAt: p.UnpublishAt,
+	}
+	if err := binding.Validator.ValidateStruct(&in); err != nil {
+		var verrs validator.ValidationErrors
+		if errors.As(err, &verrs) {
+			return services.Wrap(verrs, services.ErrInvalidInput, "validation")
+		}
+		return services.Wrap(err, services.ErrInvalidInput, "validation")
+	}
+	return nil
+}
+
+// parseMergePatch interpreta y valida un cuerpo application/merge-patch+json.
+//
+// Retorna el patch listo para services o ErrInvalidInput si el cuerpo no es un
+// objeto JSON, contiene campos de sólo lectura/desconocidos, nulls no permitidos
+// o valores que no pasan las validaciones de dto.PatchPostDTO.
+func parseMergePatch(body []byte) (services.PostPatch, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil || fields == nil {
+		if err == nil {
+			err = errors.New("body is not a JSON object")
+		}
+		return services.PostPatch{}, services.Wrap(err, services.ErrInvalidInput, "merge patch must be a JSON object")
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	nulls := map[string]bool{}
+	for _, name := range names {
+		nullable, ok := patchableFields[name]
+		switch {
+		case readOnlyFields[name]:
+			return services.PostPatch{}, services.Wrap(errors.New("field is read-only"), services.ErrInvalidInput, name)
+		case !ok:
+			return services.PostPatch{}, services.Wrap(errors.New("unknown field"), services.ErrInvalidInput, name)
+		case string(fields[name]) == "null" && !nullable:
+			return services.PostPatch{}, services.Wrap(errors.New("field cannot be null"), services.ErrInvalidInput, name)
+		case string(fields[name]) == "null":
+			nulls[name] = true
+		}
+	}
+
+	var in dto.PatchPostDTO
+	if err := json.Unmarshal(body, &in); err != nil {
+		return services.PostPatch{}, services.Wrap(err, services.ErrInvalidInput, "bind json")
+	}
+	if err := binding.Validator.ValidateStruct(&in); err != nil {
+		var verrs validator.ValidationErrors
+		if errors.As(err, &verrs) {
+			return services.PostPatch{}, services.Wrap(verrs, services.ErrInvalidInput, "validation")
+		}
+		return services.PostPatch{}, services.Wrap(err, services.ErrInvalidInput, "validation")
+	}
+	patch := services.PostPatch{
+		Title:     in.Title,
+		Slug:      in.Slug,
+		Author:    in.Author,
+		AuthorID:  in.AuthorID,
+		Content:   in.Content,
+		Tags:      in.Tags,
+		Published: in.Published,
+	}
+	if nulls["tags"] {
+		var none []string
+		patch.Tags = &none
+	}
+	if in.PublishAt != nil || nulls["publishAt"] {
+		patch.PublishAt = &in.PublishAt
+	}
+	if in.UnpublishAt != nil || nulls["unpublishAt"] {
+		patch.UnpublishAt = &in.UnpublishAt
+	}
+	return patch, nil
+}
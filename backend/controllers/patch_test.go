@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"blog-api/models"
+)
+
+func TestPatchDispatchesByContentType(t *testing.T) {
+	api := newTestAPI(t)
+	id := api.createPost(t, "Primer post")
+	path := "/api/posts/" + id
+
+	w := api.do(http.MethodPatch, path, `{"title":"Primer post editado"}`,
+		map[string]string{"Content-Type": mimeMergePatch})
+	if w.Code != http.StatusOK {
+		t.Fatalf("merge patch: status %d: %s", w.Code, w.Body)
+	}
+	var post models.Post
+	if err := json.Unmarshal(w.Body.Bytes(), &post); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if post.Title != "Primer post editado" || post.Version != 2 {
+		t.Fatalf("merge patch: title %q version %d", post.Title, post.Version)
+	}
+
+	w = api.do(http.MethodPatch, path, `[{"op":"test","path":"/version","value":2},{"op":"add","path":"/tags/-","value":"Go"}]`,
+		map[string]string{"Content-Type": mimeJSONPatch})
+	if w.Code != http.StatusOK {
+		t.Fatalf("json patch: status %d: %s", w.Code, w.Body)
+	}
+	post = models.Post{}
+	if err := json.Unmarshal(w.Body.Bytes(), &post); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(post.Tags) != 1 || post.Tags[0] != "go" {
+		t.Fatalf("json patch: tags %v", post.Tags)
+	}
+
+	// Un arreglo de operaciones con el Content-Type de merge patch no es un objeto.
+	w = api.do(http.MethodPatch, path, `[{"op":"remove","path":"/tags/0"}]`,
+		map[string]string{"Content-Type": mimeMergePatch})
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("json patch como merge patch: status %d, want 400: %s", w.Code, w.Body)
+	}
+
+	for _, ct := range []string{"application/json", "text/plain"} {
+		w = api.do(http.MethodPatch, path, `{"title":"Otro título"}`, map[string]string{"Content-Type": ct})
+		if w.Code != http.StatusUnsupportedMediaType {
+			t.Fatalf("%s: status %d, want 415: %s", ct, w.Code, w.Body)
+		}
+		if got := w.Header().Get("Accept-Patch"); got != acceptPatch {
+			t.Fatalf("%s: Accept-Patch %q, want %q", ct, got, acceptPatch)
+		}
+	}
+}
+
+func TestPatchReadOnlyFieldIsBadRequest(t *testing.T) {
+	api := newTestAPI(t)
+	id := api.createPost(t, "Primer post")
+
+	w := api.do(http.MethodPatch, "/api/posts/"+id, `{"version":5}`,
+		map[string]string{"Content-Type": mimeMergePatch})
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("merge patch de version: status %d, want 400: %s", w.Code, w.Body)
+	}
+}
+
+func TestJSONPatchFailedTestIsConflictEvenWithIfMatch(t *testing.T) {
+	api := newTestAPI(t)
+	id := api.createPost(t, "Primer post")
+
+	w := api.do(http.MethodPatch, "/api/posts/"+id, `[{"op":"test","path":"/title","value":"Otro"}]`,
+		map[string]string{"Content-Type": mimeJSONPatch, "If-Match": `"v1"`})
+	if w.Code != http.StatusConflict {
+		t.Fatalf("test fallido con If-Match vigente: status %d, want 409: %s", w.Code, w.Body)
+	}
+
+	w = api.do(http.MethodPatch, "/api/posts/"+id, `[{"op":"replace","path":"/title","value":"Primer post editado"}]`,
+		map[string]string{"Content-Type": mimeJSONPatch, "If-Match": `"v4"`})
+	if w.Code != http.StatusPreconditionFailed {
+		t.Fatalf("If-Match vencido: status %d, want 412: %s", w.Code, w.Body)
+	}
+}
@@ -5,12 +5,15 @@
 //   - Las validaciones de entrada (DTO / query params) viven aquí.
 //   - La traducción de errores a HTTP se realiza con writeError(...) usando sentinelas de services.
 //   - La lógica de negocio y acceso a datos está encapsulada en services.
+//   - Los handlers son métodos de PostController, que recibe su *services.PostService
+//     por constructor (sin dependencias globales).
 package controllers
 
 import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 
@@ -20,6 +23,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // httpError define la forma uniforme de las respuestas de error HTTP.
@@ -27,11 +32,18 @@ type httpError struct {
 	Code    int         `json:"code"`
 	Message string      `json:"message"`
 	Details interface{} `json:"details,omitempty"`
+	TraceID string      `json:"traceId,omitempty"`
 }
 
 // writeError traduce los errores de dominio (sentinelas de services) a códigos HTTP.
 // Los controladores deben delegar aquí cualquier error retornado por services.
+// ErrVersionMismatch se responde 412 si el request traía If-Match (precondición
+// fallida); cualquier otro ErrConflict (slug en uso, transición no permitida, o una
+// edición concurrente detectada sin If-Match) es 409.
+// Si el request tiene un span activo, la respuesta incluye su traceId para
+// ubicar la traza correspondiente.
 func writeError(c *gin.Context, err error) {
+	traceID := traceIDFromContext(c)
 	switch {
 	case errors.Is(err, services.ErrInvalidInput),
 		errors.Is(err, services.ErrInvalidID):
@@ -39,35 +51,67 @@ func writeError(c *gin.Context, err error) {
 			Code:    http.StatusBadRequest,
 			Message: "Solicitud inválida",
 			Details: err.Error(),
+			TraceID: traceID,
 		})
 	case errors.Is(err, services.ErrNotFound):
 		c.JSON(http.StatusNotFound, httpError{
 			Code:    http.StatusNotFound,
 			Message: "Recurso no encontrado",
+			TraceID: traceID,
+		})
+	case errors.Is(err, services.ErrVersionMismatch) && c.GetHeader("If-Match") != "":
+		c.JSON(http.StatusPreconditionFailed, httpError{
+			Code:    http.StatusPreconditionFailed,
+			Message: "La versión del recurso no coincide con If-Match",
+			TraceID: traceID,
 		})
 	case errors.Is(err, services.ErrConflict):
 		c.JSON(http.StatusConflict, httpError{
 			Code:    http.StatusConflict,
 			Message: "Conflicto de estado",
+			TraceID: traceID,
 		})
 	case errors.Is(err, services.ErrDB):
 		c.JSON(http.StatusInternalServerError, httpError{
 			Code:    http.StatusInternalServerError,
 			Message: "Error interno",
+			TraceID: traceID,
 		})
 	default:
 		c.JSON(http.StatusInternalServerError, httpError{
 			Code:    http.StatusInternalServerError,
 			Message: "Error interno",
+			TraceID: traceID,
 		})
 	}
 }
 
+// traceIDFromContext retorna el trace ID del span activo en el request, o "" si no hay traza.
+func traceIDFromContext(c *gin.Context) string {
+	sc := trace.SpanContextFromContext(c.Request.Context())
+	if !sc.HasTraceID() {
+		return ""
+	}
+	return sc.TraceID().String()
+}
+
+// PostController agrupa los handlers HTTP de posts.
+type PostController struct {
+	svc   *services.PostService
+	cache CachePolicies
+}
+
+// NewPostController crea el controlador de posts sobre svc; cache define los
+// Cache-Control de las lecturas (ver caching.go).
+func NewPostController(svc *services.PostService, cache CachePolicies) *PostController {
+	return &PostController{svc: svc, cache: cache}
+}
+
 // CreatePost maneja POST /api/posts.
 // - Valida el DTO de entrada.
-// - Delegar en services.CreatePost.
+// - Delegar en PostService.CreatePost.
 // - Responde 201 con Location y el insertedID.
-func CreatePost(c *gin.Context) {
+func (pc *PostController) CreatePost(c *gin.Context) {
 	var in dto.CreatePostDTO
 	if err := c.ShouldBindJSON(&in); err != nil {
 		var verrs validator.ValidationErrors
@@ -84,45 +128,78 @@ func CreatePost(c *gin.Context) {
 	}
 
 	post := models.Post{
-		Title:     in.Title,
-		Author:    in.Author,
-		Content:   in.Content,
-		Tags:      in.Tags,
-		Published: in.Published,
+		Title:       in.Title,
+		Slug:        in.Slug,
+		Author:      in.Author,
+		AuthorID:    authorIDOrZero(in.AuthorID),
+		Content:     in.Content,
+		Tags:        in.Tags,
+		Published:   in.Published,
+		PublishAt:   in.PublishAt,
+		UnpublishAt: in.UnpublishAt,
 	}
 
-	id, err := services.CreatePost(c.Request.Context(), post)
+	id, err := pc.svc.CreatePost(c.Request.Context(), post)
 	if err != nil {
 		writeError(c, err)
 		return
 	}
 	c.Header("Location", fmt.Sprintf("/api/posts/%s", id.Hex()))
+	c.Header("ETag", postETag(models.Post{Version: 1}))
 	c.JSON(http.StatusCreated, gin.H{"insertedID": id.Hex()})
 }
 
 // GetPostByID maneja GET /api/posts/:id.
 // - Valida presencia de :id.
-// - Delegar en services.GetPostByID.
-// - Responde 200 con el documento, 400/404/500 según corresponda.
-func GetPostByID(c *gin.Context) {
+// - Delegar en PostService.GetPostByID.
+// - Responde 200 con el documento, ETag, Last-Modified y Cache-Control (público o
+//   privado según esté publicado); 304 si If-None-Match/If-Modified-Since coinciden;
+//   400/404/500 según corresponda.
+func (pc *PostController) GetPostByID(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
 		writeError(c, services.ErrInvalidID)
 		return
 	}
-	post, err := services.GetPostByID(c.Request.Context(), id)
+	post, err := pc.svc.GetPostByID(c.Request.Context(), id)
 	if err != nil {
 		writeError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, post)
+	pc.writePost(c, post)
+}
+
+// GetPostBySlug maneja GET /api/posts/by-slug/:slug.
+// - Delegar en PostService.GetPostBySlug.
+// - Si :slug es un slug anterior del post responde 301 hacia el vigente (con el
+//   Cache-Control del post, para que la redirección no quede cacheada para siempre).
+// - Si no, responde igual que GetPostByID.
+func (pc *PostController) GetPostBySlug(c *gin.Context) {
+	slug := c.Param("slug")
+	post, err := pc.svc.GetPostBySlug(c.Request.Context(), slug)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	if post.Slug != slug {
+		policy := pc.cache.DraftPost
+		if post.Published {
+			policy = pc.cache.PublishedPost
+		}
+		setCacheControl(c, policy)
+		c.Redirect(http.StatusMovedPermanently, "/api/posts/by-slug/"+url.PathEscape(post.Slug))
+		return
+	}
+	pc.writePost(c, post)
 }
 
 // UpdatePostByID maneja PUT /api/posts/:id.
 // - Valida :id y DTO.
-// - Delegar en services.UpdatePostByID (que fija PublishedAt si aplica).
-// - Responde 200 con el documento actualizado.
-func UpdatePostByID(c *gin.Context) {
+// - Delegar en PostService.UpdatePostByID (que fija PublishedAt si aplica y guarda
+//   el estado anterior como revisión a nombre de X-User).
+// - Honra If-Match: 412 si la versión no coincide.
+// - Responde 200 con el documento actualizado y su nuevo ETag.
+func (pc *PostController) UpdatePostByID(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
 		writeError(c, services.ErrInvalidID)
@@ -145,46 +222,54 @@ func UpdatePostByID(c *gin.Context) {
 	}
 
 	post := models.Post{
-		Title:     in.Title,
-		Author:    in.Author,
-		Content:   in.Content,
-		Tags:      in.Tags,
-		Published: in.Published,
+		Title:       in.Title,
+		Slug:        in.Slug,
+		Author:      in.Author,
+		AuthorID:    authorIDOrZero(in.AuthorID),
+		Content:     in.Content,
+		Tags:        in.Tags,
+		Published:   in.Published,
+		PublishAt:   in.PublishAt,
+		UnpublishAt: in.UnpublishAt,
 	}
 
-	updated, err := services.UpdatePostByID(c.Request.Context(), id, post)
+	updated, err := pc.svc.UpdatePostByID(c.Request.Context(), id, post, actorFromRequest(c), ifMatchVersions(c))
 	if err != nil {
 		writeError(c, err)
 		return
 	}
+	setPostETag(c, updated)
 	c.JSON(http.StatusOK, updated)
 }
 
 // DeletePostByID maneja DELETE /api/posts/:id.
 // - Valida :id.
-// - Delegar en services.DeletePostByID.
-// - Responde 204 si elimina; 400/404/500 si falla.
-func DeletePostByID(c *gin.Context) {
+// - Delegar en PostService.DeletePostByID: el post va a la papelera con deletedBy
+//   tomado de X-User (ver trash.go para restaurar o borrar definitivamente).
+// - Honra If-Match: 412 si la versión no coincide.
+// - Responde 204 si elimina; 400/404/412/500 si falla.
+func (pc *PostController) DeletePostByID(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
 		writeError(c, services.ErrInvalidID)
 		return
 	}
-	if err := services.DeletePostByID(c.Request.Context(), id); err != nil {
+	if err := pc.svc.DeletePostByID(c.Request.Context(), id, actorFromRequest(c), ifMatchVersions(c)); err != nil {
 		writeError(c, err)
 		return
 	}
 	c.Status(http.StatusNoContent)
 }
 
-// GetPostsMetricsByTag maneja GET /api/posts/metrics/by-tag?limit=&onlyPublished=.
+// GetPostsMetricsByTag maneja GET /api/posts/metrics/by-tag?limit=&onlyPublished=&status=.
 //
 // Query params:
 //   - limit (opcional, entero > 0; default 10; máx 100).
 //   - onlyPublished (opcional, "true"/"false") para filtrar por estado.
+//   - status (opcional): estado editorial (draft, in_review, approved, published, archived).
 //
 // Respuestas: 200 con []TagMetric; 400 si parámetros inválidos; 500 si falla la agregación.
-func GetPostsMetricsByTag(c *gin.Context) {
+func (pc *PostController) GetPostsMetricsByTag(c *gin.Context) {
 	// limit
 	limit := 10
 	if raw := c.Query("limit"); raw != "" {
@@ -212,7 +297,7 @@ func GetPostsMetricsByTag(c *gin.Context) {
 		}
 	}
 
-	metrics, err := services.GetPostsMetricsByTag(c.Request.Context(), limit, onlyPublished)
+	metrics, err := pc.svc.GetPostsMetricsByTag(c.Request.Context(), limit, onlyPublished, strings.TrimSpace(c.Query("status")))
 	if err != nil {
 		writeError(c, err)
 		return
@@ -221,19 +306,35 @@ func GetPostsMetricsByTag(c *gin.Context) {
 }
 
 // ListPosts maneja:
-//   GET /api/posts?q=&tag=&published=(true|false)&page=&limit=&sort=publishedAt|-publishedAt
+//   GET /api/posts?q=&tag=&published=(true|false)&status=&authorId=&page=&limit=&sort=publishedAt|-publishedAt
 //
 // Query params:
 //   - q: búsqueda de texto (requiere índice {title:"text", content:"text"}).
 //   - tag: filtra por etiqueta exacta.
 //   - published: "true" | "false" | "" (sin filtro).
+//   - status: estado editorial (draft, in_review, approved, published, archived); se
+//     combina con published.
+//   - authorId: ObjectID (hex) del autor; sólo sus posts.
 //   - page, limit: enteros positivos (limit se sujeta a tope en services).
 //   - sort: "publishedAt" | "-publishedAt" (default: "-publishedAt").
 //
-// Respuestas: 200 con ListPostsResult; 400 si parámetros inválidos; 500 si falla el driver.
-func ListPosts(c *gin.Context) {
+// Respuestas: 200 con ListPostsResult y ETag de contenido; 304 si If-None-Match coincide;
+// 400 si parámetros inválidos; 500 si falla el driver. Cache-Control es público sólo
+// con published=true o status=published (el listado no puede incluir borradores).
+func (pc *PostController) ListPosts(c *gin.Context) {
 	q := strings.TrimSpace(c.Query("q"))
 	tag := strings.TrimSpace(c.Query("tag"))
+	status := strings.TrimSpace(c.Query("status"))
+
+	var authorID primitive.ObjectID
+	if raw := strings.TrimSpace(c.Query("authorId")); raw != "" {
+		oid, err := primitive.ObjectIDFromHex(raw)
+		if err != nil {
+			writeError(c, services.Wrap(err, services.ErrInvalidInput, "authorId must be an ObjectID"))
+			return
+		}
+		authorID = oid
+	}
 
 	var publishedPtr *bool
 	if raw := c.Query("published"); raw != "" {
@@ -276,15 +377,22 @@ func ListPosts(c *gin.Context) {
 		Q:         q,
 		Tag:       tag,
 		Published: publishedPtr,
+		Status:    status,
+		AuthorID:  authorID,
 		Page:      page,
 		Limit:     limit,
 		SortField: sort,
 	}
 
-	result, err := services.ListPosts(c.Request.Context(), params)
+	result, err := pc.svc.ListPosts(c.Request.Context(), params)
 	if err != nil {
 		writeError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, result)
+
+	policy := pc.cache.MixedList
+	if (publishedPtr != nil && *publishedPtr) || status == models.StatusPublished {
+		policy = pc.cache.PublishedList
+	}
+	writeCachedJSON(c, result, policy)
 }
@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"blog-api/models"
+	"blog-api/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// testAPI arma las rutas de posts sobre un Store "memory" vacío con un autor creado.
+type testAPI struct {
+	router   *gin.Engine
+	authorID string
+}
+
+func newTestAPI(t *testing.T) *testAPI {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	store, err := services.OpenStore(services.StoreOptions{Driver: services.DriverMemory})
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close(context.Background()) })
+
+	author, err := services.NewAuthorService(store.Authors, store.Posts).
+		CreateAuthor(context.Background(), models.Author{Name: "Ana García"})
+	if err != nil {
+		t.Fatalf("create author: %v", err)
+	}
+	svc := services.NewPostService(store.Posts, store.Revisions, store.Transitions, store.Authors,
+		store.TagCounts, store.Comments, store.Reactions, services.PostServiceOptions{})
+	pc := NewPostController(svc, CachePolicies{})
+
+	r := gin.New()
+	r.POST("/api/posts", pc.CreatePost)
+	r.PUT("/api/posts/:id", pc.UpdatePostByID)
+	r.PATCH("/api/posts/:id", pc.PatchPostByID)
+	r.POST("/api/posts/:id/transitions", pc.TransitionPost)
+	r.GET("/api/posts/:id/diff", pc.DiffPost)
+	return &testAPI{router: r, authorID: author.ID.Hex()}
+}
+
+// do ejecuta el request y retorna la respuesta grabada.
+func (a *testAPI) do(method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	a.router.ServeHTTP(w, req)
+	return w
+}
+
+// createPost crea un borrador con title y retorna su id.
+func (a *testAPI) createPost(t *testing.T, title string) string {
+	t.Helper()
+	w := a.do(http.MethodPost, "/api/posts",
+		`{"title":"`+title+`","content":"contenido","authorId":"`+a.authorID+`"}`, nil)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create post: status %d: %s", w.Code, w.Body)
+	}
+	var out struct {
+		InsertedID string `json:"insertedID"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+		t.Fatalf("decode create: %v", err)
+	}
+	return out.InsertedID
+}
+
+func TestTransitionIllegalWithMatchingIfMatchIsConflict(t *testing.T) {
+	api := newTestAPI(t)
+	id := api.createPost(t, "Borrador uno")
+
+	w := api.do(http.MethodPost, "/api/posts/"+id+"/transitions", `{"to":"archived"}`,
+		map[string]string{"If-Match": `"v1"`})
+	if w.Code != http.StatusConflict {
+		t.Fatalf("draft→archived con If-Match vigente: status %d, want 409: %s", w.Code, w.Body)
+	}
+}
+
+func TestTransitionWithStaleIfMatchIsPreconditionFailed(t *testing.T) {
+	api := newTestAPI(t)
+	id := api.createPost(t, "Borrador uno")
+
+	w := api.do(http.MethodPost, "/api/posts/"+id+"/transitions", `{"to":"in_review"}`,
+		map[string]string{"If-Match": `"v7"`})
+	if w.Code != http.StatusPreconditionFailed {
+		t.Fatalf("If-Match vencido: status %d, want 412: %s", w.Code, w.Body)
+	}
+}
+
+func TestUpdateDuplicateSlugWithMatchingIfMatchIsConflict(t *testing.T) {
+	api := newTestAPI(t)
+	api.createPost(t, "Primer post")
+	id := api.createPost(t, "Segundo post")
+	ifMatch := map[string]string{"If-Match": `"v1"`}
+
+	w := api.do(http.MethodPut, "/api/posts/"+id,
+		`{"title":"Segundo post","slug":"primer-post","content":"contenido","authorId":"`+api.authorID+`"}`, ifMatch)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("PUT con slug en uso: status %d, want 409: %s", w.Code, w.Body)
+	}
+
+	w = api.do(http.MethodPatch, "/api/posts/"+id, `{"slug":"primer-post"}`,
+		map[string]string{"If-Match": `"v1"`, "Content-Type": "application/merge-patch+json"})
+	if w.Code != http.StatusConflict {
+		t.Fatalf("PATCH con slug en uso: status %d, want 409: %s", w.Code, w.Body)
+	}
+
+	w = api.do(http.MethodPatch, "/api/posts/"+id, `{"slug":"segundo-libre"}`,
+		map[string]string{"If-Match": `"v1"`, "Content-Type": "application/merge-patch+json"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("PATCH con slug libre: status %d, want 200: %s", w.Code, w.Body)
+	}
+}
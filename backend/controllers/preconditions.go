@@ -0,0 +1,84 @@
+// controllers/preconditions.go
+//
+// Paquete controllers: ETags y precondiciones HTTP (If-Match) para posts.
+//
+// Convenciones:
+//   - El ETag de un post es fuerte y deriva de su versión: "v<version>". Las lecturas que
+//     traen reacciones le agregan un hash de los contadores ("v<version>-r<hex>"), porque
+//     reaccionar no cambia la versión; If-Match ignora ese sufijo.
+//   - If-Match se compara en forma fuerte (RFC 9110 §13.1.1): los ETags débiles (W/...)
+//     nunca coinciden. "*" sólo exige que el recurso exista.
+//   - La comparación real la hace PostService contra la versión almacenada; un fallo
+//     vuelve como ErrVersionMismatch y writeError lo traduce a 412 si el request traía
+//     If-Match. Cualquier otro ErrConflict (slug en uso, transición no permitida) es 409.
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"blog-api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// postETag retorna el ETag fuerte de post.
+func postETag(post models.Post) string {
+	if post.Reactions == nil {
+		return fmt.Sprintf(`"v%d"`, post.Version)
+	}
+	return fmt.Sprintf(`"v%d-r%s"`, post.Version, reactionsHash(post.Reactions))
+}
+
+// reactionsHash retorna un hash corto y estable (ordenado por tipo) de los contadores.
+func reactionsHash(counts map[string]int64) string {
+	kinds := make([]string, 0, len(counts))
+	for kind := range counts {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	h := sha256.New()
+	for _, kind := range kinds {
+		fmt.Fprintf(h, "%s=%d;", kind, counts[kind])
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// setPostETag agrega el header ETag de post a la respuesta.
+func setPostETag(c *gin.Context, post models.Post) {
+	c.Header("ETag", postETag(post))
+}
+
+// ifMatchVersions interpreta el header If-Match.
+//
+// Retorna:
+//   - nil si el header no viene o es "*" (sin precondición de versión).
+//   - las versiones de los ETags "v<n>" (o "v<n>-r<hex>") fuertes; un slice vacío (no
+//     nil) si ninguno es válido, de modo que la precondición falle.
+func ifMatchVersions(c *gin.Context) []int64 {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return nil
+	}
+	versions := []int64{}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if !strings.HasPrefix(tag, `"v`) || !strings.HasSuffix(tag, `"`) {
+			continue
+		}
+		raw := tag[2 : len(tag)-1]
+		if i := strings.Index(raw, "-r"); i >= 0 {
+			raw = raw[:i]
+		}
+		v, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || v <= 0 {
+			continue
+		}
+		versions = append(versions, v)
+	}
+	return versions
+}
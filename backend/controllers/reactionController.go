@@ -0,0 +1,114 @@
+// controllers/reactionController.go
+//
+// Paquete controllers: capa HTTP para reacciones de lectores a posts.
+//
+// Convenciones:
+//   - Mismas reglas que PostController: validación de entrada aquí, errores traducidos
+//     con writeError y lógica de negocio en services.ReactionService.
+//   - La API no autentica lectores: el frontend genera un identificador opaco por
+//     navegador/dispositivo y lo envía en el header X-Client-Id. Es la clave de
+//     idempotencia, así que (a diferencia de X-User) es obligatorio.
+//   - Reaccionar, quitar la reacción y el ranking son públicos.
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"blog-api/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ClientIDHeader es el header con el identificador del lector que reacciona.
+const ClientIDHeader = "X-Client-Id"
+
+// maxClientIDLen limita el largo (en caracteres) de un X-Client-Id.
+const maxClientIDLen = 100
+
+// ReactionController agrupa los handlers HTTP de reacciones.
+type ReactionController struct {
+	svc *services.ReactionService
+}
+
+// NewReactionController crea el controlador de reacciones sobre svc.
+func NewReactionController(svc *services.ReactionService) *ReactionController {
+	return &ReactionController{svc: svc}
+}
+
+// React maneja POST /api/posts/:id/reactions/:kind.
+//
+// Headers: X-Client-Id (obligatorio).
+//
+// Respuestas: 200 con services.ReactionResult (repetirlo no vuelve a contar:
+// changed=false); 400 si el id, el tipo o X-Client-Id son inválidos; 404 si el post no
+// existe o está en la papelera; 409 si el post no está publicado.
+func (rc *ReactionController) React(c *gin.Context) {
+	clientID, ok := clientIDFromRequest(c)
+	if !ok {
+		return
+	}
+	res, err := rc.svc.React(c.Request.Context(), c.Param("id"), c.Param("kind"), clientID)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// Unreact maneja DELETE /api/posts/:id/reactions/:kind.
+//
+// Headers: X-Client-Id (obligatorio).
+//
+// Respuestas: 200 con services.ReactionResult (si el cliente no había reaccionado,
+// changed=false); 400 si el id, el tipo o X-Client-Id son inválidos; 404 si el post no
+// existe o está en la papelera.
+func (rc *ReactionController) Unreact(c *gin.Context) {
+	clientID, ok := clientIDFromRequest(c)
+	if !ok {
+		return
+	}
+	res, err := rc.svc.Unreact(c.Request.Context(), c.Param("id"), c.Param("kind"), clientID)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// Leaderboard maneja GET /api/posts/metrics/reactions?kind=&limit=.
+//
+// Query params:
+//   - kind (opcional): tipo de reacción; sin él ordena por el total de reacciones.
+//   - limit (opcional, entero > 0; default 10; máx 50): posiciones.
+//
+// Respuestas: 200 con []services.ReactionRank (sólo posts publicados, más reacciones
+// primero); 400 si kind o limit son inválidos.
+func (rc *ReactionController) Leaderboard(c *gin.Context) {
+	limit, err := positiveQueryInt64(c, "limit")
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	ranks, err := rc.svc.Leaderboard(c.Request.Context(), strings.TrimSpace(c.Query("kind")), int(limit))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ranks)
+}
+
+// clientIDFromRequest retorna el X-Client-Id del request; si falta o es inválido (más
+// de maxClientIDLen caracteres o con caracteres de control) responde 400 y retorna
+// false.
+func clientIDFromRequest(c *gin.Context) (string, bool) {
+	id := strings.TrimSpace(c.GetHeader(ClientIDHeader))
+	if id == "" || utf8.RuneCountInString(id) > maxClientIDLen || strings.IndexFunc(id, unicode.IsControl) >= 0 {
+		writeError(c, services.Wrap(errors.New("header is missing or invalid"), services.ErrInvalidInput, ClientIDHeader))
+		return "", false
+	}
+	return id, true
+}
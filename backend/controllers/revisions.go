@@ -0,0 +1,98 @@
+// controllers/revisions.go
+//
+// Paquete controllers: historial de revisiones de un post (listar, ver y restaurar).
+//
+// Convenciones:
+//   - :rev es el número de revisión (la versión del post en ese estado, ver ETag "v<n>").
+//   - Las revisiones de un post en la papelera no son accesibles (404), igual que el post.
+//   - Restaurar es una edición: honra If-Match y registra a X-User como editor.
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"blog-api/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListRevisions maneja GET /api/posts/:id/revisions.
+//
+// Respuestas: 200 con []PostRevision (la más nueva primero); 400 si :id es inválido;
+// 404 si el post no existe; 500 si falla el almacenamiento.
+func (pc *PostController) ListRevisions(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeError(c, services.ErrInvalidID)
+		return
+	}
+	revs, err := pc.svc.ListRevisions(c.Request.Context(), id)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	writeCachedJSON(c, revs, pc.cache.DraftPost)
+}
+
+// GetRevision maneja GET /api/posts/:id/revisions/:rev.
+//
+// Respuestas: 200 con la PostRevision; 400 si :id o :rev son inválidos; 404 si el post
+// o la revisión no existen; 500 si falla el almacenamiento.
+func (pc *PostController) GetRevision(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeError(c, services.ErrInvalidID)
+		return
+	}
+	rev, err := revisionParam(c)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	revision, err := pc.svc.GetRevision(c.Request.Context(), id, rev)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	// Una revisión no cambia nunca: su ETag de contenido sirve para GET condicional.
+	writeCachedJSON(c, revision, pc.cache.DraftPost)
+}
+
+// RestoreRevision maneja POST /api/posts/:id/revisions/:rev/restore.
+// - Delegar en PostService.RestoreRevision (title, author, content y tags de la revisión).
+// - Honra If-Match: 412 si la versión no coincide.
+// - Responde 200 con el post actualizado y su nuevo ETag.
+func (pc *PostController) RestoreRevision(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeError(c, services.ErrInvalidID)
+		return
+	}
+	rev, err := revisionParam(c)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	updated, err := pc.svc.RestoreRevision(c.Request.Context(), id, rev, actorFromRequest(c), ifMatchVersions(c))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	setPostETag(c, updated)
+	c.JSON(http.StatusOK, updated)
+}
+
+// revisionParam lee :rev como entero positivo.
+func revisionParam(c *gin.Context) (int64, error) {
+	raw := c.Param("rev")
+	rev, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || rev <= 0 {
+		if err == nil {
+			err = errors.New(raw)
+		}
+		return 0, services.Wrap(err, services.ErrInvalidInput, "rev must be a positive integer")
+	}
+	return rev, nil
+}
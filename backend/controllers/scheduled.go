@@ -0,0 +1,48 @@
+// controllers/scheduled.go
+//
+// Paquete controllers: publicación programada de posts.
+//
+// Convenciones:
+//   - publishAt/unpublishAt se envían en POST, PUT y PATCH como cualquier otro campo;
+//     aquí sólo se lista lo que está pendiente.
+//   - El listado puede incluir borradores (posts aún no publicados), por lo que usa
+//     la política Cache-Control de borradores.
+package controllers
+
+import (
+	"net/http"
+
+	"blog-api/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListScheduled maneja GET /api/posts/scheduled?page=&limit=.
+//
+// Respuestas: 200 con ListPostsResult (posts con publishAt o unpublishAt pendiente,
+// primero el más próximo); 400 si page/limit no son enteros positivos; 500 si falla
+// el almacenamiento.
+func (pc *PostController) ListScheduled(c *gin.Context) {
+	page, err := positiveQueryInt(c, "page", 1)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	limit, err := positiveQueryInt(c, "limit", 10)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	result, err := pc.svc.ListPosts(c.Request.Context(), services.ListPostsParams{
+		Page:      page,
+		Limit:     limit,
+		Scheduled: true,
+	})
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	setCacheControl(c, pc.cache.DraftPost)
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,145 @@
+// controllers/tagController.go
+//
+// Paquete controllers: capa HTTP para el catálogo y la administración de etiquetas.
+//
+// Convenciones:
+//   - Mismas reglas que PostController: validación de entrada aquí, errores traducidos
+//     con writeError y lógica de negocio en services.TagService.
+//   - El catálogo y las sugerencias son públicos; renombrar y fusionar son rutas de
+//     administración (ver routes.SetupRoutes).
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"blog-api/dto"
+	"blog-api/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// TagController agrupa los handlers HTTP de etiquetas.
+type TagController struct {
+	svc *services.TagService
+}
+
+// NewTagController crea el controlador de etiquetas sobre svc.
+func NewTagController(svc *services.TagService) *TagController {
+	return &TagController{svc: svc}
+}
+
+// ListTags maneja GET /api/tags?published=&status=.
+//
+// Query params:
+//   - published (opcional, "true"/"false"): cuenta sólo posts publicados o no publicados.
+//   - status (opcional): estado editorial (draft, in_review, approved, published,
+//     archived); se combina con published.
+//
+// Respuestas: 200 con []services.TagMetric (todas las etiquetas con posts que cumplen
+// el filtro, más usadas primero); 400 si los parámetros son inválidos.
+func (tc *TagController) ListTags(c *gin.Context) {
+	var published *bool
+	if raw := c.Query("published"); raw != "" {
+		switch strings.ToLower(raw) {
+		case "true":
+			v := true
+			published = &v
+		case "false":
+			v := false
+			published = &v
+		default:
+			writeError(c, services.Wrap(nil, services.ErrInvalidInput, "published must be true or false"))
+			return
+		}
+	}
+
+	tags, err := tc.svc.ListTags(c.Request.Context(), published, strings.TrimSpace(c.Query("status")))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, tags)
+}
+
+// SuggestTags maneja GET /api/tags/suggest?prefix=&limit=.
+//
+// Query params:
+//   - prefix (requerido): inicio de la etiqueta; no distingue mayúsculas ni acentos.
+//   - limit (opcional, entero > 0; default 10; máx 50).
+//
+// Respuestas: 200 con []services.TagMetric (más usadas primero); 400 si prefix está
+// vacío o limit es inválido.
+func (tc *TagController) SuggestTags(c *gin.Context) {
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			writeError(c, services.Wrap(err, services.ErrInvalidInput, "limit must be a positive integer"))
+			return
+		}
+		limit = n
+	}
+
+	tags, err := tc.svc.SuggestTags(c.Request.Context(), c.Query("prefix"), limit)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, tags)
+}
+
+// RenameTag maneja POST /api/tags/:tag/rename.
+//
+// Body: dto.RenameTagDTO.
+//
+// Respuestas: 200 con services.TagRenameResult (posts reescritos); 400 si el cuerpo es
+// inválido o las etiquetas quedan vacías o iguales al normalizarlas.
+func (tc *TagController) RenameTag(c *gin.Context) {
+	var in dto.RenameTagDTO
+	if !bindTagBody(c, &in) {
+		return
+	}
+	res, err := tc.svc.RenameTag(c.Request.Context(), c.Param("tag"), in.To)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// MergeTags maneja POST /api/tags/merge.
+//
+// Body: dto.MergeTagsDTO.
+//
+// Respuestas: 200 con services.TagRenameResult (posts reescritos); 400 si el cuerpo es
+// inválido o ninguna etiqueta de sources difiere de target.
+func (tc *TagController) MergeTags(c *gin.Context) {
+	var in dto.MergeTagsDTO
+	if !bindTagBody(c, &in) {
+		return
+	}
+	res, err := tc.svc.MergeTags(c.Request.Context(), in.Sources, in.Target)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// bindTagBody decodifica y valida el cuerpo en in; si falla responde 400 y retorna false.
+func bindTagBody(c *gin.Context, in any) bool {
+	if err := c.ShouldBindJSON(in); err != nil {
+		var verrs validator.ValidationErrors
+		if errors.As(err, &verrs) {
+			writeError(c, services.Wrap(verrs, services.ErrInvalidInput, "validation"))
+			return false
+		}
+		writeError(c, services.Wrap(err, services.ErrInvalidInput, "bind json"))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,75 @@
+// controllers/transitions.go
+//
+// Paquete controllers: flujo editorial de un post (cambios de estado y su historial).
+//
+// Convenciones:
+//   - Un cambio de estado es una edición: honra If-Match y registra a X-User como autor.
+//   - Una transición no permitida desde el estado actual responde 409; un estado
+//     desconocido, 400.
+//   - Las transiciones de un post en la papelera no son accesibles (404), igual que el post.
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"blog-api/dto"
+	"blog-api/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// TransitionPost maneja POST /api/posts/:id/transitions.
+//
+// Body: dto.TransitionDTO ({"to": "...", "reason": "..."}).
+//
+// Respuestas: 200 con el post actualizado y su nuevo ETag; 400 si el cuerpo o el
+// estado son inválidos; 404 si el post no existe; 409 si la transición no está
+// permitida; 412 si If-Match no coincide; 500 si falla el almacenamiento.
+func (pc *PostController) TransitionPost(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeError(c, services.ErrInvalidID)
+		return
+	}
+
+	var in dto.TransitionDTO
+	if err := c.ShouldBindJSON(&in); err != nil {
+		var verrs validator.ValidationErrors
+		if errors.As(err, &verrs) {
+			writeError(c, services.Wrap(verrs, services.ErrInvalidInput, "validation"))
+			return
+		}
+		writeError(c, services.Wrap(err, services.ErrInvalidInput, "bind json"))
+		return
+	}
+
+	updated, err := pc.svc.TransitionPost(c.Request.Context(), id, strings.TrimSpace(in.To),
+		strings.TrimSpace(in.Reason), actorFromRequest(c), ifMatchVersions(c))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	setPostETag(c, updated)
+	c.JSON(http.StatusOK, updated)
+}
+
+// ListTransitions maneja GET /api/posts/:id/transitions.
+//
+// Respuestas: 200 con []PostTransition (la más nueva primero); 400 si :id es inválido;
+// 404 si el post no existe; 500 si falla el almacenamiento.
+func (pc *PostController) ListTransitions(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeError(c, services.ErrInvalidID)
+		return
+	}
+	items, err := pc.svc.ListTransitions(c.Request.Context(), id)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	writeCachedJSON(c, items, pc.cache.DraftPost)
+}
@@ -0,0 +1,99 @@
+// controllers/trash.go
+//
+// Paquete controllers: papelera de posts (listar, restaurar y borrar definitivamente).
+//
+// Convenciones:
+//   - DELETE /api/posts/:id envía a la papelera (ver DeletePostByID); estos handlers
+//     operan sobre los posts ya eliminados.
+//   - El borrado definitivo es una ruta de administración (middleware.RequireAdmin).
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"blog-api/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListTrash maneja GET /api/trash?page=&limit=.
+//
+// Respuestas: 200 con ListPostsResult (eliminados primero los más recientes);
+// 400 si page/limit no son enteros positivos; 500 si falla el almacenamiento.
+func (pc *PostController) ListTrash(c *gin.Context) {
+	page, err := positiveQueryInt(c, "page", 1)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	limit, err := positiveQueryInt(c, "limit", 10)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	result, err := pc.svc.ListPosts(c.Request.Context(), services.ListPostsParams{
+		Page:    page,
+		Limit:   limit,
+		Deleted: true,
+	})
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	setCacheControl(c, pc.cache.DraftPost)
+	c.JSON(http.StatusOK, result)
+}
+
+// RestorePostByID maneja POST /api/posts/:id/restore.
+// - Delegar en PostService.RestorePostByID.
+// - Honra If-Match: 412 si la versión no coincide.
+// - Responde 200 con el post restaurado y su nuevo ETag; 409 si no estaba en la papelera.
+func (pc *PostController) RestorePostByID(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeError(c, services.ErrInvalidID)
+		return
+	}
+	restored, err := pc.svc.RestorePostByID(c.Request.Context(), id, ifMatchVersions(c))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	setPostETag(c, restored)
+	c.JSON(http.StatusOK, restored)
+}
+
+// PurgePostByID maneja DELETE /api/trash/:id (sólo administradores).
+// - Delegar en PostService.PurgePostByID.
+// - Responde 204 si lo elimina definitivamente; 409 si el post no está en la papelera.
+func (pc *PostController) PurgePostByID(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeError(c, services.ErrInvalidID)
+		return
+	}
+	if err := pc.svc.PurgePostByID(c.Request.Context(), id); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// positiveQueryInt lee el query param name como entero positivo (def si no viene).
+func positiveQueryInt(c *gin.Context, name string, def int) (int, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n <= 0 {
+		if err == nil {
+			err = errors.New(raw)
+		}
+		return 0, services.Wrap(err, services.ErrInvalidInput, name+" must be a positive integer")
+	}
+	return n, nil
+}
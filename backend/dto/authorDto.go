@@ -0,0 +1,31 @@
+// dto/authorDto.go
+//
+// Paquete dto: cuerpo de alta y edición de autores.
+//
+// Convenciones:
+//   - El formato y la unicidad del slug los valida la capa de servicio; aquí sólo se
+//     exige la forma del cuerpo.
+package dto
+
+// AuthorDTO define el cuerpo esperado en POST /api/authors y PUT /api/authors/:id.
+//
+// Validaciones:
+//   - Name: requerido, hasta 100 caracteres; nombre visible copiado en los posts.
+//   - Slug: opcional, hasta 100 caracteres; si falta se genera del nombre (alta) o se
+//     conserva el actual (edición).
+//   - Bio: opcional, hasta 2000 caracteres.
+//   - AvatarURL: opcional, URL absoluta de hasta 500 caracteres.
+//
+// Ejemplo JSON:
+//
+//	{
+//	  "name": "Ana García",
+//	  "bio": "Escribe sobre Go y bases de datos.",
+//	  "avatarUrl": "https://example.com/ana.png"
+//	}
+type AuthorDTO struct {
+	Name      string `json:"name"      binding:"required,max=100"`
+	Slug      string `json:"slug"      binding:"omitempty,max=100"`
+	Bio       string `json:"bio"       binding:"max=2000"`
+	AvatarURL string `json:"avatarUrl" binding:"omitempty,url,max=500"`
+}
@@ -0,0 +1,47 @@
+// dto/commentDto.go
+//
+// Paquete dto: cuerpos de los endpoints de comentarios.
+//
+// Convenciones:
+//   - Los estados de moderación y la validez del comentario padre los valida la capa de
+//     servicio; aquí sólo se exige la forma del cuerpo.
+package dto
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// CommentDTO define el cuerpo esperado en POST /api/posts/:id/comments.
+//
+// Validaciones:
+//   - Author: requerido, hasta 80 caracteres.
+//   - Content: requerido, hasta 5000 caracteres.
+//   - ParentID: opcional; comentario (aprobado, del mismo post) al que se responde.
+//
+// Ejemplo JSON:
+//
+//	{
+//	  "author": "Lucía",
+//	  "content": "¡Muy útil, gracias!",
+//	  "parentId": "66a1f0c2e4b0a1b2c3d4e5f6"
+//	}
+type CommentDTO struct {
+	Author   string              `json:"author"   binding:"required,max=80"`
+	Content  string              `json:"content"  binding:"required,max=5000"`
+	ParentID *primitive.ObjectID `json:"parentId"`
+}
+
+// ModerateCommentsDTO define el cuerpo esperado en POST /api/comments/moderate.
+//
+// Validaciones:
+//   - IDs: requerido, entre 1 y 500 ids de comentarios.
+//   - Status: requerido; estado a aplicar (pending, approved, rejected, spam).
+//
+// Ejemplo JSON:
+//
+//	{
+//	  "ids": ["66a1f0c2e4b0a1b2c3d4e5f6", "66a1f0c2e4b0a1b2c3d4e5f7"],
+//	  "status": "approved"
+//	}
+type ModerateCommentsDTO struct {
+	IDs    []string `json:"ids"    binding:"required,min=1,max=500"`
+	Status string   `json:"status" binding:"required"`
+}
@@ -10,14 +10,26 @@
 //   - No contienen lógica de negocio ni persistencia.
 package dto
 
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
 // CreatePostDTO define el cuerpo esperado en POST /api/posts.
 //
 // Validaciones:
 //   - Title: requerido, entre 5 y 140 caracteres.
-//   - Author: requerido.
+//   - Slug: opcional, hasta 100 caracteres; si falta se genera del título. El formato
+//     (minúsculas, dígitos y guiones) lo valida la capa de servicio.
+//   - Author / AuthorID: al menos uno. AuthorID (hex) referencia un autor de
+//     /api/authors; con sólo Author se busca el autor por nombre. Que exista lo valida
+//     la capa de servicio.
 //   - Content: requerido.
 //   - Tags: opcional, arreglo de strings.
 //   - Published: opcional (default false si no se envía).
+//   - PublishAt / UnpublishAt: opcionales (RFC 3339); programan la publicación y la
+//     despublicación. Las reglas entre ambas y con Published las valida el servicio.
 //
 // Ejemplo JSON:
 //   {
@@ -28,21 +40,29 @@ package dto
 //     "published": true
 //   }
 type CreatePostDTO struct {
-	Title     string   `json:"title"   binding:"required,min=5,max=140"`
-	Author    string   `json:"author"  binding:"required"`
-	Content   string   `json:"content" binding:"required"`
-	Tags      []string `json:"tags"`
-	Published bool     `json:"published"`
+	Title       string              `json:"title"   binding:"required,min=5,max=140"`
+	Slug        string              `json:"slug"    binding:"omitempty,max=100"`
+	Author      string              `json:"author"  binding:"required_without=AuthorID"`
+	AuthorID    *primitive.ObjectID `json:"authorId"`
+	Content     string              `json:"content" binding:"required"`
+	Tags        []string            `json:"tags"`
+	Published   bool                `json:"published"`
+	PublishAt   *time.Time          `json:"publishAt"`
+	UnpublishAt *time.Time          `json:"unpublishAt"`
 }
 
 // UpdatePostDTO define el cuerpo esperado en PUT /api/posts/:id.
 //
 // Validaciones:
 //   - Title: requerido, entre 5 y 140 caracteres.
-//   - Author: requerido.
+//   - Slug: opcional, hasta 100 caracteres; vacío conserva el actual (o lo regenera si
+//     cambia el título).
+//   - Author / AuthorID: al menos uno (como en CreatePostDTO); sin AuthorID y con el
+//     mismo nombre se conserva el autor actual.
 //   - Content: requerido.
 //   - Tags: opcional, arreglo de strings.
 //   - Published: opcional.
+//   - PublishAt / UnpublishAt: opcionales; si faltan se cancela la programación.
 //
 // Nota: PublishedAt no se controla aquí; lo fija la capa de servicio
 //       cuando se cambia Published de false→true.
@@ -56,9 +76,41 @@ type CreatePostDTO struct {
 //     "published": false
 //   }
 type UpdatePostDTO struct {
-	Title     string   `json:"title"   binding:"required,min=5,max=140"`
-	Author    string   `json:"author"  binding:"required"`
-	Content   string   `json:"content" binding:"required"`
-	Tags      []string `json:"tags"`
-	Published bool     `json:"published"`
+	Title       string              `json:"title"   binding:"required,min=5,max=140"`
+	Slug        string              `json:"slug"    binding:"omitempty,max=100"`
+	Author      string              `json:"author"  binding:"required_without=AuthorID"`
+	AuthorID    *primitive.ObjectID `json:"authorId"`
+	Content     string              `json:"content" binding:"required"`
+	Tags        []string            `json:"tags"`
+	Published   bool                `json:"published"`
+	PublishAt   *time.Time          `json:"publishAt"`
+	UnpublishAt *time.Time          `json:"unpublishAt"`
+}
+
+// PatchPostDTO define el cuerpo de PATCH /api/posts/:id (application/merge-patch+json, RFC 7396).
+//
+// Validaciones (sólo sobre los campos presentes; nil = no enviado):
+//   - Title: entre 5 y 140 caracteres.
+//   - Slug: entre 1 y 100 caracteres.
+//   - Author / Content: no vacíos.
+//   - AuthorID: autor por id; con sólo Author se busca por nombre.
+//   - Tags: reemplaza el arreglo completo; null las elimina (lo resuelve el controlador).
+//   - Published: la regla de PublishedAt false→true aplica igual que en PUT.
+//   - PublishAt / UnpublishAt: nueva programación; null la cancela (lo resuelve el controlador).
+//
+// Ejemplo JSON:
+//   {
+//     "published": true,
+//     "tags": ["go", "mongo"]
+//   }
+type PatchPostDTO struct {
+	Title       *string             `json:"title"     binding:"omitnil,min=5,max=140"`
+	Slug        *string             `json:"slug"      binding:"omitnil,min=1,max=100"`
+	Author      *string             `json:"author"    binding:"omitnil,min=1"`
+	AuthorID    *primitive.ObjectID `json:"authorId"`
+	Content     *string             `json:"content"   binding:"omitnil,min=1"`
+	Tags        *[]string           `json:"tags"`
+	Published   *bool               `json:"published"`
+	PublishAt   *time.Time          `json:"publishAt"`
+	UnpublishAt *time.Time          `json:"unpublishAt"`
 }
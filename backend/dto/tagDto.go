@@ -0,0 +1,34 @@
+// dto/tagDto.go
+//
+// Paquete dto: cuerpos de la administración de etiquetas.
+//
+// Convenciones:
+//   - La normalización (minúsculas, espacios, alias) la aplica la capa de servicio;
+//     aquí sólo se exige la forma del cuerpo.
+package dto
+
+// RenameTagDTO define el cuerpo esperado en POST /api/tags/:tag/rename.
+//
+// Validaciones:
+//   - To: requerido, hasta 100 caracteres; nuevo nombre de la etiqueta.
+//
+// Ejemplo JSON:
+//
+//	{ "to": "go" }
+type RenameTagDTO struct {
+	To string `json:"to" binding:"required,max=100"`
+}
+
+// MergeTagsDTO define el cuerpo esperado en POST /api/tags/merge.
+//
+// Validaciones:
+//   - Sources: requerido, entre 1 y 50 etiquetas de hasta 100 caracteres.
+//   - Target: requerido, hasta 100 caracteres; etiqueta resultante.
+//
+// Ejemplo JSON:
+//
+//	{ "sources": ["golang", "go-lang"], "target": "go" }
+type MergeTagsDTO struct {
+	Sources []string `json:"sources" binding:"required,min=1,max=50,dive,required,max=100"`
+	Target  string   `json:"target"  binding:"required,max=100"`
+}
@@ -0,0 +1,25 @@
+// dto/transitionDto.go
+//
+// Paquete dto: cuerpo de los cambios de estado editorial de un post.
+//
+// Convenciones:
+//   - Los estados válidos y las transiciones permitidas los valida la capa de servicio;
+//     aquí sólo se exige la forma del cuerpo.
+package dto
+
+// TransitionDTO define el cuerpo esperado en POST /api/posts/:id/transitions.
+//
+// Validaciones:
+//   - To: requerido; estado de destino (draft, in_review, approved, published, archived).
+//   - Reason: opcional, hasta 500 caracteres; motivo que queda registrado.
+//
+// Ejemplo JSON:
+//
+//	{
+//	  "to": "in_review",
+//	  "reason": "Listo para revisión"
+//	}
+type TransitionDTO struct {
+	To     string `json:"to"     binding:"required"`
+	Reason string `json:"reason" binding:"max=500"`
+}
@@ -0,0 +1,53 @@
+// lifecycle.go
+//
+// Utilidades de ciclo de vida del proceso: tareas en segundo plano que se
+// detienen de forma ordenada durante el apagado.
+package main
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// workerGroup lanza tareas de fondo que comparten un contexto de cancelación.
+type workerGroup struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// newWorkerGroup crea un grupo vacío.
+func newWorkerGroup() *workerGroup {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &workerGroup{ctx: ctx, cancel: cancel}
+}
+
+// Go ejecuta run en una goroutine; run debe retornar cuando su ctx se cancele.
+func (g *workerGroup) Go(name string, run func(ctx context.Context)) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		slog.Info("worker iniciado", slog.String("worker", name))
+		run(g.ctx)
+		slog.Info("worker detenido", slog.String("worker", name))
+	}()
+}
+
+// Stop cancela a todos los workers y espera a que terminen o a que venza ctx.
+func (g *workerGroup) Stop(ctx context.Context) error {
+	g.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
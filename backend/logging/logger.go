@@ -0,0 +1,84 @@
+// logging/logger.go
+//
+// Paquete logging: logger estructurado (JSON) y propagación del request ID.
+//
+// Convenciones:
+//   - Toda la aplicación usa log/slog; Setup instala el logger por defecto.
+//   - El request ID viaja en context.Context (WithRequestID); el handler lo agrega
+//     como atributo "requestId" a cualquier registro emitido con slog.*Context(ctx, ...).
+//   - Si ctx tiene un span de OpenTelemetry, también se agregan "traceId" y "spanId".
+//   - Los servicios no importan este paquete: basta con loguear usando el ctx recibido.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// requestIDKey es la clave privada del request ID en el contexto.
+type requestIDKey struct{}
+
+// WithRequestID retorna una copia de ctx que transporta id.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID retorna el request ID de ctx, o "" si no tiene.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// ParseLevel convierte "debug" | "info" | "warn" | "error" en slog.Level (default info).
+func ParseLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// Setup crea un logger JSON sobre w con el nivel indicado y lo instala como slog.Default
+// (también redirige el paquete log estándar).
+func Setup(w io.Writer, level string) *slog.Logger {
+	logger := slog.New(contextHandler{slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level: ParseLevel(level),
+	})})
+	slog.SetDefault(logger)
+	return logger
+}
+
+// contextHandler agrega el request ID del contexto a cada registro.
+type contextHandler struct {
+	slog.Handler
+}
+
+// Handle implementa slog.Handler.
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("requestId", id))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(slog.String("traceId", sc.TraceID().String()), slog.String("spanId", sc.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+// WithAttrs implementa slog.Handler conservando el envoltorio.
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup implementa slog.Handler conservando el envoltorio.
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -6,50 +6,218 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-contrib/cors"
 	"blog-api/config"
+	"blog-api/controllers"
+	"blog-api/logging"
+	"blog-api/metrics"
+	"blog-api/middleware"
 	"blog-api/routes"
 	"blog-api/services"
+	"blog-api/tracing"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 func main() {
-	// 1. Cargar configuración (desde .env o entorno).
-	cfg := config.Load()
+	// 1. Cargar configuración: defaults < archivo (--config) < entorno < flags.
+	//    - Se informan todos los errores de validación juntos y el programa termina.
+	//    - --print-config muestra la configuración efectiva (sin secretos) y termina.
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "❌ Configuración inválida:")
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if cfg.PrintConfig {
+		fmt.Print(cfg.Redacted())
+		return
+	}
 
-	// 2. Conectar a MongoDB usando la configuración cargada.
-	//    - Si la conexión o el ping fallan, el programa termina con log.Fatal.
-	//    - Se crean índices necesarios en la colección "posts".
-	services.ConnectMongo(cfg.MongoURI, cfg.MongoDB)
+	//    - Logs JSON estructurados (log/slog) con el nivel de LOG_LEVEL.
+	//    - Métricas Prometheus (HTTP, operaciones Mongo, runtime de Go) en /metrics.
+	//    - Trazas OpenTelemetry según TRACING_EXPORTER (none, stdout, otlp).
+	logger := logging.Setup(os.Stdout, cfg.Log.Level)
+	appMetrics := metrics.New()
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		Exporter:     cfg.Tracing.Exporter,
+		OTLPEndpoint: cfg.Tracing.OTLPEndpoint,
+		OTLPInsecure: cfg.Tracing.OTLPInsecure,
+		ServiceName:  cfg.Tracing.ServiceName,
+		SampleRatio:  cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		fatal("error configurando trazas", err)
+	}
+
+	//    - `server migrate <status|up|down> [n]` administra migraciones y termina.
+	if len(cfg.Args) > 0 && cfg.Args[0] == "migrate" {
+		os.Exit(runMigrate(cfg, cfg.Args[1:]))
+	}
+
+	// 2. Abrir el almacenamiento según STORAGE_DRIVER.
+	//    - "mongo" (default): conecta, hace ping y aplica migraciones pendientes
+	//      (índices, backfills) salvo AUTO_MIGRATE=false.
+	//    - "memory": sin dependencias externas; carga MEMORY_SNAPSHOT si está definido.
+	//    - "sqlite": archivo SQLITE_PATH; crea el esquema (tablas, FTS5) si no existe.
+	//    - Si falla, el programa termina con código 1.
+	store, err := services.OpenStore(services.StoreOptions{
+		Driver:       cfg.Storage.Driver,
+		MongoURI:     cfg.Mongo.URI,
+		MongoDB:      cfg.Mongo.Database,
+		SnapshotPath: cfg.Storage.MemorySnapshot,
+		SQLitePath:   cfg.Storage.SQLitePath,
+		Timeout:      cfg.DB.Timeout,
+		Observe:      appMetrics.ObserveMongo,
+		TagAliases:   cfg.Tags.Aliases,
+		AutoMigrate:  cfg.Storage.AutoMigrate,
+	})
+	if err != nil {
+		fatal("error abriendo almacenamiento", err)
+	}
+	logger.Info("almacenamiento abierto", slog.String("driver", store.Driver))
+
+	//    - Armar repositorio → servicio → controlador (inyección por constructor).
+	postSvc := services.NewPostService(store.Posts, store.Revisions, store.Transitions, store.Authors, store.TagCounts, store.Comments, store.Reactions, services.PostServiceOptions{
+		MaxPageLimit:     cfg.DB.MaxPageLimit,
+		RevisionMaxCount: cfg.Revisions.MaxCount,
+		RevisionMaxAge:   cfg.Revisions.MaxAge,
+		TagAliases:       cfg.Tags.Aliases,
+		ReactionKinds:    cfg.Reactions.Kinds,
+	})
+	postCtrl := controllers.NewPostController(postSvc, controllers.CachePolicies{
+		PublishedPost: cfg.Cache.PublishedPost,
+		DraftPost:     cfg.Cache.DraftPost,
+		PublishedList: cfg.Cache.PublishedList,
+		MixedList:     cfg.Cache.MixedList,
+	})
+	commentCtrl := controllers.NewCommentController(services.NewCommentService(store.Comments, store.Posts))
+	reactionCtrl := controllers.NewReactionController(services.NewReactionService(store.Reactions, store.Posts, cfg.Reactions.Kinds))
+	authorCtrl := controllers.NewAuthorController(services.NewAuthorService(store.Authors, store.Posts))
+	tagCtrl := controllers.NewTagController(services.NewTagService(store.Posts, store.TagCounts, cfg.Tags.Aliases))
+	healthCtrl := controllers.NewHealthController(store.HealthChecks)
+
+	//    - Tareas en segundo plano; se detienen durante el apagado.
+	//    - La purga de la papelera borra los posts eliminados hace más de TRASH_RETENTION.
+	//    - El scheduler aplica publishAt/unpublishAt vencidos; con varias instancias
+	//      trabaja sólo la que tiene el lease (vence a los 3 intervalos sin renovar).
+	workers := newWorkerGroup()
+	if cfg.Trash.Retention > 0 {
+		workers.Go("trash-purge", func(ctx context.Context) {
+			postSvc.RunTrashPurge(ctx, cfg.Trash.Retention, cfg.Trash.PurgeInterval)
+		})
+	}
+	if cfg.Scheduler.Interval > 0 {
+		lease := store.Lease("post_scheduler", 3*cfg.Scheduler.Interval)
+		workers.Go("post-scheduler", func(ctx context.Context) {
+			postSvc.RunScheduler(ctx, lease, cfg.Scheduler.Interval)
+		})
+	}
 
-	// 3. Inicializar router con middlewares por defecto (logger + recovery).
-	//    - gin.Default() incluye logging de requests y recuperación ante pánicos.
-	r := gin.Default()
+	// 3. Inicializar router con los middlewares de trazas, logging y recuperación.
+	//    - otelgin abre un span por request (continúa el traceparent W3C entrante);
+	//      probes y /metrics no se trazan.
+	//    - RequestLogger asigna/propaga X-Request-ID (llega a services vía context)
+	//      y emite una línea JSON por request.
+	//    - Metrics cuenta requests y latencias por ruta y status.
+	//    - Recovery responde 500 ante pánicos y los registra con el mismo request ID.
+	//    - Gin sólo corre en modo debug con LOG_LEVEL=debug.
+	if cfg.Log.Level != "debug" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	r := gin.New()
+	r.Use(otelgin.Middleware(cfg.Tracing.ServiceName, otelgin.WithFilter(tracing.SkipInfraRoutes)), middleware.RequestLogger(logger), middleware.Metrics(appMetrics), middleware.Recovery(logger))
 
-	// 4. Configurar CORS para admitir solicitudes desde el frontend.
+	// 4. Configurar CORS para admitir solicitudes desde los orígenes de CORS_ORIGINS.
 	r.Use(cors.New(cors.Config{
-        AllowOrigins:     []string{"http://localhost:3000"},
-        AllowMethods:     []string{"GET", "POST", "PUT", "DELETE"},
-        AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-        ExposeHeaders:    []string{"Content-Length", "Location"},
+        AllowOrigins:     cfg.HTTP.CORSOrigins,
+        AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+        AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", middleware.RequestIDHeader, "traceparent", "tracestate", "If-Match", "If-None-Match", "If-Modified-Since", controllers.ActorHeader, controllers.ClientIDHeader},
+        ExposeHeaders:    []string{"Content-Length", "Location", "ETag", "Last-Modified", "Cache-Control", "Accept-Patch", middleware.RequestIDHeader},
         AllowCredentials: true,
         MaxAge:           12 * time.Hour,
     }))
 
 	// 5. Registrar las rutas de la API.
-	//    - Ver routes.SetupRoutes: agrupa bajo /api y define endpoints de posts.
-	routes.SetupRoutes(r)
+	//    - Ver routes.SetupRoutes: agrupa bajo /api y define endpoints de posts, comentarios, reacciones, autores y etiquetas.
+	//    - Las rutas de administración exigen ADMIN_TOKEN como Bearer.
+	routes.SetupRoutes(r, healthCtrl, postCtrl, commentCtrl, reactionCtrl, authorCtrl, tagCtrl, appMetrics.Handler(), middleware.RequireAdmin(cfg.Admin.Token))
 
-	// 6. Iniciar servidor HTTP en el puerto configurado.
-	if cfg.Port == "" {
-		log.Fatal("❌ No se definió PORT en .env")
+	// 6. Iniciar servidor HTTP(S) en el puerto configurado, con timeouts explícitos.
+	//    - Con TLS_CERT_FILE y TLS_KEY_FILE definidos se sirve HTTPS.
+	srv := &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           r,
+		ReadTimeout:       cfg.HTTP.ReadTimeout,
+		ReadHeaderTimeout: cfg.HTTP.ReadTimeout,
+		WriteTimeout:      cfg.HTTP.WriteTimeout,
+		IdleTimeout:       cfg.HTTP.IdleTimeout,
 	}
-	log.Println("🚀 API escuchando en :" + cfg.Port)
 
-	if err := r.Run(":" + cfg.Port); err != nil {
-		log.Fatal(err)
+	go func() {
+		var err error
+		if cfg.TLS.Enabled() {
+			logger.Info("API escuchando", slog.String("addr", srv.Addr), slog.Bool("tls", true))
+			err = srv.ListenAndServeTLS(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+		} else {
+			logger.Info("API escuchando", slog.String("addr", srv.Addr), slog.Bool("tls", false))
+			err = srv.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fatal("error del servidor HTTP", err)
+		}
+	}()
+
+	// 7. Apagado ordenado al recibir SIGINT/SIGTERM:
+	//    a) /healthz pasa a "draining" y se espera SHUTDOWN_DELAY;
+	//    b) se cierra el listener y se drenan las requests en curso;
+	//    c) se detienen los workers de fondo;
+	//    d) se cierra el almacenamiento (desconecta Mongo, cierra SQLite o vuelca el snapshot);
+	//    e) se exportan los spans pendientes.
+	//    Todo dentro de SHUTDOWN_TIMEOUT.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+	stop()
+
+	logger.Info("señal recibida, drenando conexiones")
+	healthCtrl.SetDraining()
+	time.Sleep(cfg.HTTP.ShutdownDelay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Error("error drenando conexiones", slog.String("error", err.Error()))
 	}
+	if err := workers.Stop(shutdownCtx); err != nil {
+		logger.Error("workers no terminaron a tiempo", slog.String("error", err.Error()))
+	}
+	if err := store.Close(shutdownCtx); err != nil {
+		logger.Error("error cerrando almacenamiento", slog.String("error", err.Error()))
+	}
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error("error exportando trazas", slog.String("error", err.Error()))
+	}
+	logger.Info("API detenida")
+}
+
+// fatal registra err con slog y termina el proceso con código 1.
+func fatal(msg string, err error) {
+	slog.Error(msg, slog.String("error", err.Error()))
+	os.Exit(1)
 }
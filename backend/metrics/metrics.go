@@ -0,0 +1,108 @@
+// metrics/metrics.go
+//
+// Paquete metrics: métricas Prometheus de la API, expuestas en /metrics.
+//
+// Convenciones:
+//   - Se usa un registro propio (no el global de client_golang): lo que se expone
+//     es exactamente lo que se registra aquí.
+//   - Las etiquetas tienen cardinalidad acotada: route es la plantilla de Gin
+//     (ej. /api/posts/:id), nunca la ruta concreta; result/sentinel son los nombres
+//     de los sentinelas de services (ver services.SentinelName).
+//   - Incluye las métricas del runtime de Go y del proceso.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"blog-api/services"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace es el prefijo de todas las métricas propias.
+const namespace = "blog"
+
+// Metrics agrupa el registro y los colectores de la aplicación.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests  *prometheus.CounterVec
+	httpDuration  *prometheus.HistogramVec
+	mongoDuration *prometheus.HistogramVec
+	mongoErrors   *prometheus.CounterVec
+}
+
+// New crea el registro con las métricas HTTP, Mongo, runtime de Go y proceso.
+//
+// Métricas:
+//   - blog_http_requests_total{method,route,status}
+//   - blog_http_request_duration_seconds{method,route,status}
+//   - blog_mongo_operation_duration_seconds{operation,result}
+//   - blog_mongo_operation_errors_total{operation,sentinel}
+//   - go_* y process_*
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Requests HTTP atendidos, por método, ruta y status.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Latencia de los requests HTTP, por método, ruta y status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		mongoDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "mongo",
+			Name:      "operation_duration_seconds",
+			Help:      "Duración de las operaciones Mongo, por operación y resultado (ok o sentinel).",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+		}, []string{"operation", "result"}),
+		mongoErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "mongo",
+			Name:      "operation_errors_total",
+			Help:      "Operaciones Mongo fallidas, por operación y sentinel de dominio.",
+		}, []string{"operation", "sentinel"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.mongoDuration,
+		m.mongoErrors,
+	)
+	return m
+}
+
+// Handler sirve el registro en formato de texto de Prometheus.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// ObserveHTTP registra un request atendido.
+func (m *Metrics) ObserveHTTP(method, route string, status int, elapsed time.Duration) {
+	code := strconv.Itoa(status)
+	m.httpRequests.WithLabelValues(method, route, code).Inc()
+	m.httpDuration.WithLabelValues(method, route, code).Observe(elapsed.Seconds())
+}
+
+// ObserveMongo registra una operación Mongo; su firma coincide con services.OpObserver.
+func (m *Metrics) ObserveMongo(op string, elapsed time.Duration, err error) {
+	result := services.SentinelName(err)
+	m.mongoDuration.WithLabelValues(op, result).Observe(elapsed.Seconds())
+	if err != nil {
+		m.mongoErrors.WithLabelValues(op, result).Inc()
+	}
+}
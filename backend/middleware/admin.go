@@ -0,0 +1,40 @@
+// middleware/admin.go
+//
+// Paquete middleware: protección de las rutas de administración.
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireAdmin exige `Authorization: Bearer <token>` con el token de administración.
+//
+// Comportamiento:
+//   - token vacío deshabilita las rutas protegidas: responden 403 a todos.
+//   - Sin header o con otro token responde 401 con WWW-Authenticate: Bearer.
+//   - La comparación es en tiempo constante.
+func RequireAdmin(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"code":    http.StatusForbidden,
+				"message": "Operación de administración deshabilitada (ADMIN_TOKEN no configurado)",
+			})
+			return
+		}
+		got, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(strings.TrimSpace(got)), []byte(token)) != 1 {
+			c.Header("WWW-Authenticate", `Bearer realm="admin"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"code":    http.StatusUnauthorized,
+				"message": "Se requiere token de administración",
+			})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,23 @@
+// middleware/metrics.go
+//
+// Paquete middleware: instrumentación Prometheus de los requests HTTP.
+package middleware
+
+import (
+	"time"
+
+	"blog-api/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics registra cada request en m (contador e histograma por método, ruta y status).
+// La ruta es la plantilla de Gin; los requests sin ruta se agrupan como "unmatched" (ver routeLabel).
+func Metrics(m *metrics.Metrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		m.ObserveHTTP(c.Request.Method, routeLabel(c), c.Writer.Status(), time.Since(start))
+	}
+}
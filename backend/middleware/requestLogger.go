@@ -0,0 +1,129 @@
+// middleware/requestLogger.go
+//
+// Paquete middleware: middlewares Gin transversales a todas las rutas.
+//
+// Convenciones:
+//   - RequestLogger debe registrarse primero: asigna el request ID que usan
+//     los demás middlewares, los controladores y los servicios (vía context).
+//   - Los logs son JSON estructurado (log/slog, ver paquete logging).
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"blog-api/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader es el header por el que se recibe y devuelve el request ID.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLen limita el largo de un X-Request-ID recibido del cliente.
+const maxRequestIDLen = 128
+
+// RequestLogger asigna o propaga X-Request-ID y emite una línea JSON por request.
+//
+// Comportamiento:
+//   - Si el cliente envía un X-Request-ID válido (ASCII imprimible, hasta 128 caracteres)
+//     se reutiliza; si no, se genera uno aleatorio.
+//   - El ID se devuelve en la respuesta y se guarda en c.Request.Context()
+//     (logging.WithRequestID), de modo que llega a services.
+//   - Al terminar se loguea method, route (plantilla, ej. /api/posts/:id), path, status,
+//     latencyMs, bytes y clientIp. Nivel: error para 5xx, warn para 4xx, info el resto.
+func RequestLogger(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Header(RequestIDHeader, id)
+		ctx := logging.WithRequestID(c.Request.Context(), id)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		route := routeLabel(c)
+		bytes := c.Writer.Size()
+		if bytes < 0 {
+			bytes = 0
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("route", route),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Float64("latencyMs", float64(time.Since(start).Microseconds())/1000),
+			slog.Int("bytes", bytes),
+			slog.String("clientIp", c.ClientIP()),
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("errors", c.Errors.String()))
+		}
+		logger.LogAttrs(ctx, level, "request", attrs...)
+	}
+}
+
+// Recovery reemplaza a gin.Recovery: ante un pánico responde 500 en JSON
+// y lo registra con el request ID.
+func Recovery(logger *slog.Logger) gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered any) {
+		logger.ErrorContext(c.Request.Context(), "panic recuperado",
+			slog.Any("panic", recovered),
+			slog.String("route", c.FullPath()),
+			slog.String("stack", string(debug.Stack())))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"code":    http.StatusInternalServerError,
+			"message": "Error interno",
+		})
+	})
+}
+
+// routeLabel retorna la plantilla de ruta de Gin, o "unmatched" si no hubo match
+// (mantiene acotada la cardinalidad de logs y métricas).
+func routeLabel(c *gin.Context) string {
+	if route := c.FullPath(); route != "" {
+		return route
+	}
+	return "unmatched"
+}
+
+// validRequestID acepta IDs no vacíos de ASCII imprimible sin espacios.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID genera 16 bytes aleatorios en hexadecimal.
+func newRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return hex.EncodeToString([]byte(time.Now().UTC().Format(time.RFC3339Nano)))
+	}
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,100 @@
+// migrate.go
+//
+// Subcomando `migrate`: administra las migraciones versionadas del driver Mongo.
+//
+// Uso:
+//
+//	server migrate status        → lista migraciones y si están aplicadas
+//	server migrate up [versión]  → aplica pendientes (hasta versión, si se indica)
+//	server migrate down [n]      → revierte las últimas n (default 1)
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"blog-api/config"
+	"blog-api/services"
+)
+
+// runMigrate ejecuta el subcomando y retorna el código de salida del proceso.
+func runMigrate(cfg *config.Config, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "uso: migrate <status|up|down> [n]")
+		return 2
+	}
+
+	n := 0
+	if len(args) > 1 {
+		v, err := strconv.Atoi(args[1])
+		if err != nil || v <= 0 {
+			fmt.Fprintln(os.Stderr, "❌ n debe ser un entero positivo")
+			return 2
+		}
+		n = v
+	}
+
+	store, err := services.OpenStore(services.StoreOptions{
+		Driver:     cfg.Storage.Driver,
+		MongoURI:   cfg.Mongo.URI,
+		MongoDB:    cfg.Mongo.Database,
+		Timeout:    cfg.DB.Timeout,
+		TagAliases: cfg.Tags.Aliases,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "❌ Error abriendo almacenamiento:", err)
+		return 1
+	}
+	defer store.Close(context.Background())
+
+	if store.Migrator == nil {
+		fmt.Fprintf(os.Stderr, "❌ El driver %q no usa migraciones (su esquema se crea al iniciar)\n", store.Driver)
+		return 1
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "status":
+		statuses, err := store.Migrator.Status(ctx)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "❌", err)
+			return 1
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSIÓN\tESTADO\tAPLICADA\tDESCRIPCIÓN")
+		for _, st := range statuses {
+			state, at := "pendiente", "-"
+			if st.Applied {
+				state = "aplicada"
+				at = st.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", st.Version, state, at, st.Description)
+		}
+		w.Flush()
+
+	case "up":
+		applied, err := store.Migrator.Up(ctx, n)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "❌", err)
+			return 1
+		}
+		fmt.Println("✅ Migraciones aplicadas:", applied)
+
+	case "down":
+		reverted, err := store.Migrator.Down(ctx, n)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "❌", err)
+			return 1
+		}
+		fmt.Println("✅ Migraciones revertidas:", reverted)
+
+	default:
+		fmt.Fprintf(os.Stderr, "❌ subcomando desconocido %q\n", args[0])
+		return 2
+	}
+	return 0
+}
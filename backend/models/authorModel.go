@@ -0,0 +1,37 @@
+// models/authorModel.go
+//
+// Paquete models: autores de posts (colección "authors").
+//
+// Convenciones:
+//   - Un post referencia a su autor por AuthorID; Post.Author guarda además el nombre
+//     visible (desnormalizado) para que los listados no necesiten otra consulta.
+//   - Slug es único y se deriva del nombre sin acentos ("Ana García" → "ana-garcia"):
+//     dos grafías del mismo nombre corresponden al mismo autor.
+//   - Renombrar un autor no cambia su slug (salvo que se envíe uno nuevo), así los
+//     clientes que todavía envían el nombre anterior siguen resolviendo al mismo autor.
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Author es el perfil de un autor.
+//
+// Campos:
+//   - ID: identificador del documento.
+//   - Name: nombre visible, requerido.
+//   - Slug: identificador legible y único.
+//   - Bio: presentación breve (opcional).
+//   - AvatarURL: URL de la imagen de perfil (opcional).
+//   - CreatedAt / UpdatedAt: fechas en UTC (UpdatedAt nil si nunca se editó).
+type Author struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"       json:"_id"`
+	Name      string             `bson:"name"                json:"name"`
+	Slug      string             `bson:"slug"                json:"slug"`
+	Bio       string             `bson:"bio,omitempty"       json:"bio,omitempty"`
+	AvatarURL string             `bson:"avatarUrl,omitempty" json:"avatarUrl,omitempty"`
+	CreatedAt time.Time          `bson:"createdAt"           json:"createdAt"`
+	UpdatedAt *time.Time         `bson:"updatedAt,omitempty" json:"updatedAt,omitempty"`
+}
@@ -0,0 +1,53 @@
+// models/commentModel.go
+//
+// Paquete models: comentarios de lectores en posts (colección "comments").
+//
+// Convenciones:
+//   - Los hilos tienen un único comentario raíz (ParentID y RootID vacíos); cada
+//     respuesta guarda su padre directo y la raíz del hilo, así un hilo completo se lee
+//     con una sola consulta por RootID.
+//   - Todo comentario nuevo entra como "pending"; sólo los "approved" son públicos.
+//     "rejected" y "spam" se conservan para el historial de moderación.
+//   - Replies no se persiste: lo arma la capa de servicios al listar un hilo.
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Estados de moderación de un comentario (Comment.Status).
+const (
+	CommentPending  = "pending"
+	CommentApproved = "approved"
+	CommentRejected = "rejected"
+	CommentSpam     = "spam"
+)
+
+// Comment es un comentario de un lector en un post.
+//
+// Campos:
+//   - ID: identificador del documento (crece con la fecha de creación; lo usa el cursor).
+//   - PostID: post comentado.
+//   - ParentID: comentario al que responde (nil en la raíz del hilo).
+//   - RootID: raíz del hilo (nil en la raíz).
+//   - Author: nombre visible de quien comenta.
+//   - Content: texto del comentario.
+//   - Status: estado de moderación (pending, approved, rejected, spam).
+//   - CreatedAt: fecha/hora en UTC de creación.
+//   - ModeratedAt / ModeratedBy: última moderación (header X-User); nil/"" si no hubo.
+//   - Replies: respuestas aprobadas, sólo en GET /api/posts/:id/comments.
+type Comment struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty"         json:"_id"`
+	PostID      primitive.ObjectID  `bson:"postId"                json:"postId"`
+	ParentID    *primitive.ObjectID `bson:"parentId,omitempty"    json:"parentId,omitempty"`
+	RootID      *primitive.ObjectID `bson:"rootId,omitempty"      json:"rootId,omitempty"`
+	Author      string              `bson:"author"                json:"author"`
+	Content     string              `bson:"content"               json:"content"`
+	Status      string              `bson:"status"                json:"status"`
+	CreatedAt   time.Time           `bson:"createdAt"             json:"createdAt"`
+	ModeratedAt *time.Time          `bson:"moderatedAt,omitempty" json:"moderatedAt,omitempty"`
+	ModeratedBy string              `bson:"moderatedBy,omitempty" json:"moderatedBy,omitempty"`
+	Replies     []Comment           `bson:"-"                     json:"replies,omitempty"`
+}
@@ -5,7 +5,7 @@
 // Convenciones:
 //   - Los structs llevan tags `bson` para mapearse a MongoDB y `json` para la salida HTTP.
 //   - Validaciones básicas (binding) se pueden usar en controladores/DTO cuando corresponda.
-//   - Los timestamps CreatedAt y PublishedAt son gestionados por la capa de servicios.
+//   - Los timestamps CreatedAt, UpdatedAt y PublishedAt son gestionados por la capa de servicios.
 package models
 
 import (
@@ -19,12 +19,31 @@ import (
 // Campos:
 //   - ID: identificador único (ObjectID de MongoDB).
 //   - Title: título del post, requerido, 5–140 caracteres.
-//   - Author: autor del post, requerido.
+//   - Slug: identificador legible y único para URLs públicas; se genera del título
+//     (transliterando acentos) o lo indica el cliente.
+//   - OldSlugs: slugs anteriores del post; GET /api/posts/by-slug/:slug redirige (301)
+//     desde ellos al slug vigente.
+//   - Author: nombre visible del autor, requerido; se copia del autor referenciado.
+//   - AuthorID: autor del post en la colección "authors" (ver authorModel.go).
 //   - Content: contenido del post, requerido.
 //   - Tags: etiquetas asociadas (opcional).
-//   - Published: indica si el post está publicado.
+//   - Status: estado editorial (draft, in_review, approved, published, archived); ver
+//     postTransitionModel.go.
+//   - Published: indica si el post está publicado (equivale a Status == "published").
 //   - PublishedAt: fecha/hora en UTC en que se publicó (nil si no publicado).
+//   - PublishAt: publicación programada; al llegar la fecha el post pasa a publicado
+//     con PublishedAt = PublishAt y el campo se borra (nil = sin programar).
+//   - UnpublishAt: despublicación programada (embargo o contenido temporal); al llegar
+//     la fecha el post deja de estar publicado y el campo se borra.
 //   - CreatedAt: fecha/hora en UTC en que se creó.
+//   - UpdatedAt: fecha/hora en UTC de la última actualización (nil si nunca se editó).
+//   - Version: contador de control de concurrencia optimista; empieza en 1 y cada
+//     actualización lo incrementa de forma atómica. Se expone como ETag ("v<version>").
+//   - DeletedAt: fecha/hora en UTC en que se envió a la papelera (nil si no está eliminado).
+//   - DeletedBy: quién lo eliminó (header X-User; "anonymous" si no se indicó).
+//   - CommentCount: comentarios aprobados; sólo se completa en los listados (no se persiste).
+//   - Reactions: reacciones por tipo configurado (0 si no hay); se completa en GET por id o
+//     slug y en los listados (no se persiste; ver reactionModel.go).
 //
 // Serialización:
 //   - bson: usado por el driver de MongoDB.
@@ -35,15 +54,34 @@ import (
 //   - binding:"required" en Author y Content.
 //
 // Notas:
-//   - CreatedAt y PublishedAt son controlados por la capa de servicios, no por el cliente.
+//   - CreatedAt, UpdatedAt, PublishedAt, Version, OldSlugs y Status son controlados por la capa de servicios, no por el cliente.
+//   - Status sólo cambia por POST /api/posts/:id/transitions, por el scheduler o, por
+//     compatibilidad, al cambiar Published (true → "published"; false → "draft").
+//   - Slug tiene índice único; al renombrar el post el slug anterior pasa a OldSlugs.
 //   - PublishedAt se fija automáticamente cuando Published cambia de false→true.
+//   - Mientras PublishAt está pendiente Published es false, así los filtros por
+//     publicación no muestran el post antes de tiempo.
+//   - Un post con DeletedAt está en la papelera: las lecturas normales lo excluyen y se
+//     purga definitivamente al vencer la retención configurada.
 type Post struct {
 	ID          primitive.ObjectID `bson:"_id,omitempty"    json:"_id"`
 	Title       string             `bson:"title"            json:"title"   binding:"required,min=5,max=140"`
+	Slug        string             `bson:"slug,omitempty"   json:"slug,omitempty"`
+	OldSlugs    []string           `bson:"oldSlugs,omitempty" json:"oldSlugs,omitempty"`
 	Author      string             `bson:"author"           json:"author"  binding:"required"`
+	AuthorID    primitive.ObjectID `bson:"authorId,omitempty" json:"authorId,omitempty"`
 	Content     string             `bson:"content"          json:"content" binding:"required"`
 	Tags        []string           `bson:"tags,omitempty"   json:"tags,omitempty"`
+	Status      string             `bson:"status,omitempty" json:"status,omitempty"`
 	Published   bool               `bson:"published"        json:"published"`
 	PublishedAt *time.Time         `bson:"publishedAt,omitempty" json:"publishedAt,omitempty"`
+	PublishAt   *time.Time         `bson:"publishAt,omitempty" json:"publishAt,omitempty"`
+	UnpublishAt *time.Time         `bson:"unpublishAt,omitempty" json:"unpublishAt,omitempty"`
 	CreatedAt   time.Time          `bson:"createdAt"        json:"createdAt"`
+	UpdatedAt   *time.Time         `bson:"updatedAt,omitempty" json:"updatedAt,omitempty"`
+	Version     int64              `bson:"version"          json:"version"`
+	DeletedAt   *time.Time         `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`
+	DeletedBy   string             `bson:"deletedBy,omitempty" json:"deletedBy,omitempty"`
+	CommentCount *int64            `bson:"-"                json:"commentCount,omitempty"`
+	Reactions    map[string]int64  `bson:"-"                json:"reactions,omitempty"`
 }
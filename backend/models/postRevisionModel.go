@@ -0,0 +1,41 @@
+// models/postRevisionModel.go
+//
+// Paquete models: historial de revisiones de un post.
+//
+// Convenciones:
+//   - Cada actualización de un post guarda antes su estado previo como una revisión
+//     (colección "post_revisions"); el post vigente nunca está en el historial.
+//   - Revision coincide con la versión (Post.Version) que tenía el post en ese estado,
+//     de modo que es única por post y creciente.
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PostRevision es una foto de los campos editables de un post.
+//
+// Campos:
+//   - ID: identificador del documento de revisión.
+//   - PostID: post al que pertenece.
+//   - Revision: versión del post que se conservó.
+//   - Title, Author, AuthorID, Content, Tags, Published, PublishedAt: contenido en esa
+//     versión (AuthorID falta en revisiones anteriores a la colección de autores).
+//   - CreatedAt: fecha/hora en UTC en que la revisión fue reemplazada (momento de la foto).
+//   - CreatedBy: editor que hizo el cambio que reemplazó esta versión (header X-User).
+type PostRevision struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"         json:"_id"`
+	PostID      primitive.ObjectID `bson:"postId"                json:"postId"`
+	Revision    int64              `bson:"revision"              json:"revision"`
+	Title       string             `bson:"title"                 json:"title"`
+	Author      string             `bson:"author"                json:"author"`
+	AuthorID    primitive.ObjectID `bson:"authorId,omitempty"    json:"authorId,omitempty"`
+	Content     string             `bson:"content"               json:"content"`
+	Tags        []string           `bson:"tags,omitempty"        json:"tags,omitempty"`
+	Published   bool               `bson:"published"             json:"published"`
+	PublishedAt *time.Time         `bson:"publishedAt,omitempty" json:"publishedAt,omitempty"`
+	CreatedAt   time.Time          `bson:"createdAt"             json:"createdAt"`
+	CreatedBy   string             `bson:"createdBy,omitempty"   json:"createdBy,omitempty"`
+}
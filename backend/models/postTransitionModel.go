@@ -0,0 +1,48 @@
+// models/postTransitionModel.go
+//
+// Paquete models: flujo editorial de un post (estados y transiciones).
+//
+// Convenciones:
+//   - Post.Status recorre draft → in_review → approved → published → archived; las
+//     transiciones permitidas las define la capa de servicios.
+//   - Post.Published se mantiene sincronizado (true sólo en "published") para que los
+//     filtros y clientes anteriores al flujo sigan funcionando.
+//   - Cada cambio de estado queda registrado como PostTransition (colección
+//     "post_transitions"), con quién lo hizo y por qué.
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Estados del flujo editorial (Post.Status).
+const (
+	StatusDraft     = "draft"
+	StatusInReview  = "in_review"
+	StatusApproved  = "approved"
+	StatusPublished = "published"
+	StatusArchived  = "archived"
+)
+
+// PostTransition es un cambio de estado de un post.
+//
+// Campos:
+//   - ID: identificador del registro.
+//   - PostID: post al que pertenece.
+//   - From / To: estado anterior y nuevo.
+//   - Version: versión del post tras el cambio.
+//   - Reason: motivo indicado por quien hizo el cambio (opcional).
+//   - CreatedAt: fecha/hora en UTC del cambio.
+//   - CreatedBy: quién hizo el cambio (header X-User; "scheduler" si fue automático).
+type PostTransition struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"       json:"_id"`
+	PostID    primitive.ObjectID `bson:"postId"              json:"postId"`
+	From      string             `bson:"from"                json:"from"`
+	To        string             `bson:"to"                  json:"to"`
+	Version   int64              `bson:"version"             json:"version"`
+	Reason    string             `bson:"reason,omitempty"    json:"reason,omitempty"`
+	CreatedAt time.Time          `bson:"createdAt"           json:"createdAt"`
+	CreatedBy string             `bson:"createdBy,omitempty" json:"createdBy,omitempty"`
+}
@@ -0,0 +1,44 @@
+// models/reactionModel.go
+//
+// Paquete models: reacciones de lectores a posts (colecciones "reactions" y
+// "reaction_counts").
+//
+// Convenciones:
+//   - Reaction es la marca de un cliente (header X-Client-Id) que reaccionó con un tipo;
+//     hay a lo sumo una por (post, tipo, cliente), lo que hace idempotente reaccionar.
+//   - ReactionCount es el agregado por post que leen GET /api/posts y el ranking; sólo
+//     cambia con $inc cuando una marca se crea o se borra, nunca lo edita un cliente.
+//   - Los tipos válidos se configuran (reactions.kinds); no se guardan en el modelo.
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Reaction es la reacción de un cliente a un post.
+//
+// Campos:
+//   - PostID: post al que se reaccionó.
+//   - Kind: tipo de reacción (ej. "like").
+//   - ClientID: identificador opaco del cliente que reaccionó.
+//   - CreatedAt: fecha/hora en UTC de la reacción.
+type Reaction struct {
+	PostID    primitive.ObjectID `bson:"postId"    json:"postId"`
+	Kind      string             `bson:"kind"      json:"kind"`
+	ClientID  string             `bson:"clientId"  json:"clientId"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// ReactionCount es la cantidad de reacciones de un post por tipo.
+//
+// Campos:
+//   - PostID: post contado; es el _id del documento.
+//   - Counts: reacciones por tipo (los tipos sin reacciones pueden faltar o valer 0).
+//   - Total: suma de Counts; ordena el ranking general.
+type ReactionCount struct {
+	PostID primitive.ObjectID `bson:"_id"    json:"postId"`
+	Counts map[string]int64   `bson:"counts" json:"counts"`
+	Total  int64              `bson:"total"  json:"total"`
+}
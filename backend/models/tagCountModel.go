@@ -0,0 +1,25 @@
+// models/tagCountModel.go
+//
+// Paquete models: contadores de uso de etiquetas (colección "tag_counts").
+//
+// Convenciones:
+//   - Es un dato derivado de los posts: se recalcula por etiqueta después de cada
+//     escritura que la afecta y se reconstruye completo al migrar; nunca lo edita un
+//     cliente.
+//   - Sólo cuentan los posts fuera de la papelera; una etiqueta sin posts no tiene
+//     documento.
+package models
+
+// TagCount es la cantidad de posts que usan una etiqueta, por estado editorial.
+//
+// Campos:
+//   - Tag: etiqueta normalizada (ver services/postTags.go); es el _id del documento.
+//   - Folded: Tag sin acentos ("programación" → "programacion"), para buscar por prefijo.
+//   - Statuses: posts por estado editorial (draft, in_review, ..., published).
+//   - Total: suma de Statuses; ordena las sugerencias por uso.
+type TagCount struct {
+	Tag      string           `bson:"_id"      json:"tag"`
+	Folded   string           `bson:"folded"   json:"-"`
+	Statuses map[string]int64 `bson:"statuses" json:"statuses"`
+	Total    int64            `bson:"total"    json:"total"`
+}
@@ -16,29 +16,106 @@ import (
 // SetupRoutes registra todas las rutas de la API en el router Gin.
 //
 // Endpoints principales:
-//   - GET    /api/posts                  → listado con filtros y paginación
+//   - GET    /api/posts                  → listado con filtros y paginación (ETag de contenido, 304)
 //   - POST   /api/posts                  → crear un post
-//   - GET    /api/posts/:id              → obtener un post por ID
-//   - PUT    /api/posts/:id              → actualizar un post por ID
-//   - DELETE /api/posts/:id              → eliminar un post por ID
+//   - GET    /api/posts/:id              → obtener un post por ID (ETag/Last-Modified, 304)
+//   - GET    /api/posts/by-slug/:slug    → obtener un post por slug (301 si es un slug anterior)
+//   - PUT    /api/posts/:id              → actualizar un post por ID (If-Match opcional → 412)
+//   - PATCH  /api/posts/:id              → actualización parcial (merge-patch+json o json-patch+json; If-Match opcional → 412)
+//   - DELETE /api/posts/:id              → enviar un post a la papelera (If-Match opcional → 412)
+//   - POST   /api/posts/:id/restore      → restaurar un post de la papelera
+//   - GET    /api/posts/:id/revisions    → historial de revisiones, la más nueva primero
+//   - GET    /api/posts/:id/revisions/:rev → una revisión
+//   - POST   /api/posts/:id/revisions/:rev/restore → volver al contenido de una revisión (If-Match opcional → 412)
+//   - GET    /api/posts/:id/diff         → diff entre versiones (?from=&to=; JSON o text/x-diff)
+//   - POST   /api/posts/:id/transitions  → cambiar el estado editorial (If-Match opcional → 412; 409 si no está permitido)
+//   - GET    /api/posts/:id/transitions  → historial de cambios de estado, el más nuevo primero
+//   - GET    /api/trash                  → posts eliminados, los más recientes primero
+//   - DELETE /api/trash/:id              → borrado definitivo (requiere token de administración)
+//   - GET    /api/posts/scheduled        → publicaciones/despublicaciones pendientes, la más próxima primero
 //   - GET    /api/posts/metrics/by-tag   → agregación: top-N tags por cantidad
+//   - GET    /api/posts/metrics/reactions → ranking de posts publicados por reacciones (?kind=&limit=)
+//   - POST   /api/posts/:id/reactions/:kind → reaccionar (header X-Client-Id; idempotente por cliente)
+//   - DELETE /api/posts/:id/reactions/:kind → quitar la reacción (header X-Client-Id; idempotente)
+//   - GET    /api/posts/:id/comments     → hilos de comentarios aprobados (?cursor=&limit=)
+//   - POST   /api/posts/:id/comments     → comentar o responder (queda pendiente de moderación)
+//   - GET    /api/comments               → cola de moderación por estado (?status=&cursor=&limit=; requiere token de administración)
+//   - POST   /api/comments/moderate      → moderar comentarios en lote (requiere token de administración)
+//   - GET    /api/authors                → autores ordenados por nombre
+//   - POST   /api/authors                → crear un autor (409 si el slug está en uso)
+//   - GET    /api/authors/:id            → obtener un autor
+//   - PUT    /api/authors/:id            → actualizar un autor (el nombre se copia en sus posts)
+//   - DELETE /api/authors/:id            → eliminar un autor sin posts (409 si tiene)
+//   - GET    /api/tags                   → catálogo de etiquetas con cantidad de posts (?published=&status=)
+//   - GET    /api/tags/suggest           → etiquetas por prefijo sin acentos, más usadas primero (?prefix=&limit=)
+//   - POST   /api/tags/:tag/rename       → renombrar una etiqueta en todos los posts (requiere token de administración)
+//   - POST   /api/tags/merge             → fusionar etiquetas en una (requiere token de administración)
 //
-// Adicionalmente, define un healthcheck en /healthz y manejadores
+// Los handlers se obtienen de posts, comments, reactions, authors y tags (ver
+// controllers.NewPostController, controllers.NewCommentController,
+// controllers.NewReactionController, controllers.NewAuthorController y
+// controllers.NewTagController); admin protege las rutas de administración (ver
+// middleware.RequireAdmin).
+//
+// Probes (ver controllers.HealthController):
+//   - GET    /healthz                    → healthcheck simple (Docker); "draining" al apagar
+//   - GET    /livez                      → liveness: el proceso responde
+//   - GET    /readyz                     → readiness: dependencias con estado y latencia (503 si falla una crítica)
+//
+// Observabilidad:
+//   - GET    /metrics                    → métricas Prometheus (formato texto), servidas por metricsHandler
+//
+// Adicionalmente, define manejadores
 // para rutas no encontradas (404) y métodos no permitidos (405).
-func SetupRoutes(r *gin.Engine) {
+func SetupRoutes(r *gin.Engine, health *controllers.HealthController, posts *controllers.PostController, comments *controllers.CommentController, reactions *controllers.ReactionController, authors *controllers.AuthorController, tags *controllers.TagController, metricsHandler http.Handler, admin gin.HandlerFunc) {
 	// Healthcheck para test (Docker)
-	r.GET("/healthz", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "ok"})
-	})
+	r.GET("/healthz", health.Healthz)
+	r.GET("/livez", health.Livez)
+	r.GET("/readyz", health.Readyz)
+
+	// Scrape de Prometheus
+	r.GET("/metrics", gin.WrapH(metricsHandler))
 
 	api := r.Group("/api")
 	{
-		api.GET("/posts", controllers.ListPosts)
-		api.POST("/posts", controllers.CreatePost)
-		api.GET("/posts/:id", controllers.GetPostByID)
-		api.PUT("/posts/:id", controllers.UpdatePostByID)
-		api.DELETE("/posts/:id", controllers.DeletePostByID)
-		api.GET("/posts/metrics/by-tag", controllers.GetPostsMetricsByTag)
+		api.GET("/posts", posts.ListPosts)
+		api.POST("/posts", posts.CreatePost)
+		api.GET("/posts/:id", posts.GetPostByID)
+		api.GET("/posts/by-slug/:slug", posts.GetPostBySlug)
+		api.PUT("/posts/:id", posts.UpdatePostByID)
+		api.PATCH("/posts/:id", posts.PatchPostByID)
+		api.DELETE("/posts/:id", posts.DeletePostByID)
+		api.POST("/posts/:id/restore", posts.RestorePostByID)
+		api.GET("/posts/:id/revisions", posts.ListRevisions)
+		api.GET("/posts/:id/revisions/:rev", posts.GetRevision)
+		api.POST("/posts/:id/revisions/:rev/restore", posts.RestoreRevision)
+		api.GET("/posts/:id/diff", posts.DiffPost)
+		api.POST("/posts/:id/transitions", posts.TransitionPost)
+		api.GET("/posts/:id/transitions", posts.ListTransitions)
+		api.GET("/trash", posts.ListTrash)
+		api.DELETE("/trash/:id", admin, posts.PurgePostByID)
+		api.GET("/posts/scheduled", posts.ListScheduled)
+		api.GET("/posts/metrics/by-tag", posts.GetPostsMetricsByTag)
+
+		api.GET("/posts/:id/comments", comments.ListComments)
+		api.POST("/posts/:id/comments", comments.CreateComment)
+		api.GET("/comments", admin, comments.ListModerationQueue)
+		api.POST("/comments/moderate", admin, comments.ModerateComments)
+
+		api.POST("/posts/:id/reactions/:kind", reactions.React)
+		api.DELETE("/posts/:id/reactions/:kind", reactions.Unreact)
+		api.GET("/posts/metrics/reactions", reactions.Leaderboard)
+
+		api.GET("/authors", authors.ListAuthors)
+		api.POST("/authors", authors.CreateAuthor)
+		api.GET("/authors/:id", authors.GetAuthor)
+		api.PUT("/authors/:id", authors.UpdateAuthor)
+		api.DELETE("/authors/:id", authors.DeleteAuthor)
+
+		api.GET("/tags", tags.ListTags)
+		api.GET("/tags/suggest", tags.SuggestTags)
+		api.POST("/tags/:tag/rename", admin, tags.RenameTag)
+		api.POST("/tags/merge", admin, tags.MergeTags)
 	}
 
 	// Handler global: 404 en JSON
@@ -0,0 +1,43 @@
+// services/authorRepository.go
+//
+// Paquete services: contrato de persistencia de autores.
+//
+// Convenciones:
+//   - Igual que PostRepository: los servicios dependen de la interfaz, las
+//     implementaciones envuelven sus errores con sentinelas y las reglas (slug, nombre,
+//     integridad con los posts) viven en AuthorService.
+//   - slug es único (índice idx_slug en Mongo, UNIQUE en SQLite).
+package services
+
+import (
+	"context"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AuthorRepository define las operaciones de almacenamiento de autores.
+//
+// Implementaciones:
+//   - MongoAuthorRepository: colección "authors" en MongoDB.
+//   - MemoryAuthorRepository: mapa en memoria.
+//   - SQLiteAuthorRepository: tabla authors.
+//
+// Errores esperados:
+//   - ErrNotFound si el autor no existe (Get, GetBySlug, Update, Delete).
+//   - ErrConflict si el slug ya pertenece a otro autor (Create, Update).
+//   - ErrDB ante fallas del almacenamiento.
+type AuthorRepository interface {
+	// Create persiste a tal cual (CreatedAt ya fijado) y retorna su ObjectID.
+	Create(ctx context.Context, a models.Author) (primitive.ObjectID, error)
+	// Get recupera un autor por id.
+	Get(ctx context.Context, id primitive.ObjectID) (models.Author, error)
+	// GetBySlug recupera un autor por slug.
+	GetBySlug(ctx context.Context, slug string) (models.Author, error)
+	// List retorna todos los autores ordenados por nombre.
+	List(ctx context.Context) ([]models.Author, error)
+	// Update reemplaza name, slug, bio, avatarUrl y updatedAt; retorna el resultado.
+	Update(ctx context.Context, id primitive.ObjectID, a models.Author) (models.Author, error)
+	// Delete elimina un autor por id.
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestAuthorRepository(t *testing.T) {
+	forEachStore(t, func(t *testing.T, f *repoFixture) {
+		ctx := context.Background()
+		if _, err := f.store.Authors.Create(ctx, models.Author{Name: "Otra Ana", Slug: "ana-garcia", CreatedAt: f.now}); !errors.Is(err, ErrConflict) {
+			t.Fatalf("slug duplicado: %v, want ErrConflict", err)
+		}
+		id, err := f.store.Authors.Create(ctx, models.Author{Name: "Bruno Díaz", Slug: "bruno-diaz", CreatedAt: f.now})
+		if err != nil {
+			t.Fatalf("create: %v", err)
+		}
+
+		items, err := f.store.Authors.List(ctx)
+		if err != nil || len(items) != 2 || items[0].Name != "Ana García" || items[1].Name != "Bruno Díaz" {
+			t.Fatalf("list por nombre: %+v %v", items, err)
+		}
+		if got, err := f.store.Authors.GetBySlug(ctx, "bruno-diaz"); err != nil || got.ID != id {
+			t.Fatalf("get by slug: %v %v", got.ID, err)
+		}
+
+		updated, err := f.store.Authors.Update(ctx, id, models.Author{Name: "Bruno D.", Slug: "bruno-d", Bio: "bio"})
+		if err != nil || updated.Name != "Bruno D." || updated.Slug != "bruno-d" || !updated.CreatedAt.Equal(f.now) {
+			t.Fatalf("update: %+v %v", updated, err)
+		}
+		if _, err := f.store.Authors.Update(ctx, id, models.Author{Name: "Bruno", Slug: "ana-garcia"}); !errors.Is(err, ErrConflict) {
+			t.Fatalf("update a slug en uso: %v, want ErrConflict", err)
+		}
+		if _, err := f.store.Authors.Update(ctx, primitive.NewObjectID(), models.Author{Name: "X", Slug: "x"}); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("update inexistente: %v, want ErrNotFound", err)
+		}
+
+		if err := f.store.Authors.Delete(ctx, id); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+		if err := f.store.Authors.Delete(ctx, id); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("delete repetido: %v, want ErrNotFound", err)
+		}
+	})
+}
@@ -0,0 +1,200 @@
+// services/authorService.go
+//
+// Paquete services: lógica de negocio para la entidad Author.
+//
+// Convenciones:
+//   - Mismas reglas que PostService: repositorios por constructor, errores envueltos
+//     con sentinelas, ErrDB registrados con logDBError y un span por método.
+//   - El slug se genera del nombre o se valida si lo envía el cliente, y en ambos casos
+//     debe estar libre: "Ana Garcia" no crea otro autor si ya existe "Ana García".
+//     Un nombre sin letras ni dígitos transliterables usa fallbackAuthorSlug con el
+//     primer sufijo libre (-2, -3...), como backfillAuthors. Renombrar un autor
+//     conserva su slug.
+//   - Integridad referencial: un autor con posts (incluidos los de la papelera) no
+//     puede eliminarse, y renombrarlo actualiza el nombre copiado en sus posts.
+//     Sin transacciones, DeleteAuthor vuelve a contar tras borrar y restaura el autor
+//     si un alta concurrente le asignó un post.
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// errAuthorInUse es la causa al eliminar un autor que todavía tiene posts.
+var errAuthorInUse = errors.New("author has posts")
+
+// AuthorService implementa los casos de uso de autores sobre un AuthorRepository;
+// consulta y actualiza los posts que los referencian a través de un PostRepository.
+type AuthorService struct {
+	authors AuthorRepository
+	posts   PostRepository
+}
+
+// NewAuthorService crea el servicio de autores usando authors como almacenamiento y
+// posts para mantener la integridad de sus referencias.
+func NewAuthorService(authors AuthorRepository, posts PostRepository) *AuthorService {
+	return &AuthorService{authors: authors, posts: posts}
+}
+
+// CreateAuthor inserta un nuevo Author y lo retorna.
+//
+// Reglas:
+//   - Estampa CreatedAt=now; el nombre se guarda sin espacios en los extremos.
+//   - Si a.Slug está vacío lo genera del nombre; si viene definido debe tener formato
+//     válido. En ambos casos debe estar libre: un nombre que sólo difiere en acentos o
+//     mayúsculas de uno existente es el mismo autor y no se sufija.
+//   - Un nombre sin slug propio (sólo símbolos o escritura no transliterable) no
+//     identifica a nadie: recibe fallbackAuthorSlug o el primer sufijo libre.
+//
+// Errores:
+//   - ErrInvalidInput: slug con formato inválido.
+//   - ErrConflict: slug (explícito o generado del nombre) en uso, o sin sufijo libre
+//     para fallbackAuthorSlug tras maxSlugAttempts intentos.
+//   - ErrDB: error del driver.
+func (s *AuthorService) CreateAuthor(ctx context.Context, a models.Author) (_ models.Author, err error) {
+	ctx, span := startSpan(ctx, "AuthorService.CreateAuthor")
+	defer endSpan(span, &err)
+
+	a.ID = primitive.NilObjectID
+	a.Name = strings.TrimSpace(a.Name)
+	a.CreatedAt = time.Now().UTC()
+	a.UpdatedAt = nil
+	explicit := a.Slug != ""
+	if explicit {
+		if err := validateSlug(a.Slug); err != nil {
+			return models.Author{}, err
+		}
+	}
+	base, sluggable := authorSlug(a.Name)
+
+	for n := 1; ; n++ {
+		if !explicit {
+			a.Slug = slugCandidate(base, n)
+		}
+		a.ID, err = s.authors.Create(ctx, a)
+		if errors.Is(err, ErrConflict) && !explicit && !sluggable && n < maxSlugAttempts {
+			continue
+		}
+		if err != nil {
+			return models.Author{}, logDBError(ctx, "create author", err)
+		}
+		return a, nil
+	}
+}
+
+// GetAuthor recupera un Author por su ObjectID (hexadecimal).
+//
+// Errores:
+//   - ErrInvalidID si el id es inválido; ErrNotFound si no existe; ErrDB si falla el driver.
+func (s *AuthorService) GetAuthor(ctx context.Context, idHex string) (_ models.Author, err error) {
+	ctx, span := startSpan(ctx, "AuthorService.GetAuthor", attribute.String("author.id", idHex))
+	defer endSpan(span, &err)
+
+	oid, err := primitive.ObjectIDFromHex(idHex)
+	if err != nil {
+		return models.Author{}, Wrap(err, ErrInvalidID, "parse objectid")
+	}
+	a, err := s.authors.Get(ctx, oid)
+	return a, logDBError(ctx, "get author", err)
+}
+
+// ListAuthors retorna todos los autores ordenados por nombre.
+//
+// Errores:
+//   - ErrDB si falla el driver.
+func (s *AuthorService) ListAuthors(ctx context.Context) (_ []models.Author, err error) {
+	ctx, span := startSpan(ctx, "AuthorService.ListAuthors")
+	defer endSpan(span, &err)
+
+	items, err := s.authors.List(ctx)
+	return items, logDBError(ctx, "list authors", err)
+}
+
+// UpdateAuthor reemplaza nombre, bio, avatar y, si viene definido, el slug de un Author.
+//
+// Reglas:
+//   - Un slug vacío conserva el actual; uno nuevo debe tener formato válido y estar libre.
+//   - El nombre se copia en los posts del autor que tengan otro (incrementa su version).
+//     La copia es idempotente: si falla, repetir el mismo PUT la completa.
+//
+// Errores:
+//   - ErrInvalidID, ErrInvalidInput (slug inválido), ErrNotFound, ErrConflict (slug en
+//     uso), ErrDB.
+func (s *AuthorService) UpdateAuthor(ctx context.Context, idHex string, a models.Author) (_ models.Author, err error) {
+	ctx, span := startSpan(ctx, "AuthorService.UpdateAuthor", attribute.String("author.id", idHex))
+	defer endSpan(span, &err)
+
+	current, err := s.GetAuthor(ctx, idHex)
+	if err != nil {
+		return models.Author{}, err
+	}
+	a.Name = strings.TrimSpace(a.Name)
+	if a.Slug == "" {
+		a.Slug = current.Slug
+	} else if err := validateSlug(a.Slug); err != nil {
+		return models.Author{}, err
+	}
+	now := time.Now().UTC()
+	a.UpdatedAt = &now
+
+	updated, err := s.authors.Update(ctx, current.ID, a)
+	if err != nil {
+		return models.Author{}, logDBError(ctx, "update author", err)
+	}
+	n, err := s.posts.SetAuthorName(ctx, updated.ID, updated.Name, now)
+	if err != nil {
+		return models.Author{}, logDBError(ctx, "update author name in posts", err)
+	}
+	span.SetAttributes(attribute.Int64("author.posts_renamed", n))
+	return updated, nil
+}
+
+// DeleteAuthor elimina un Author sin posts.
+//
+// Reglas:
+//   - Cuenta sus posts antes y después de borrarlo: si un alta concurrente le asignó un
+//     post entre el conteo y el borrado, lo restaura con el mismo id y responde
+//     ErrConflict.
+//
+// Errores:
+//   - ErrInvalidID; ErrNotFound si no existe; ErrConflict si algún post (incluidos los
+//     de la papelera) lo referencia; ErrDB.
+func (s *AuthorService) DeleteAuthor(ctx context.Context, idHex string) (err error) {
+	ctx, span := startSpan(ctx, "AuthorService.DeleteAuthor", attribute.String("author.id", idHex))
+	defer endSpan(span, &err)
+
+	current, err := s.GetAuthor(ctx, idHex)
+	if err != nil {
+		return err
+	}
+	n, err := s.posts.CountByAuthor(ctx, current.ID)
+	if err != nil {
+		return logDBError(ctx, "count posts by author", err)
+	}
+	if n > 0 {
+		return Wrap(errAuthorInUse, ErrConflict, fmt.Sprintf("%d posts", n))
+	}
+	if err := s.authors.Delete(ctx, current.ID); err != nil {
+		return logDBError(ctx, "delete author", err)
+	}
+
+	n, err = s.posts.CountByAuthor(ctx, current.ID)
+	if err == nil && n == 0 {
+		return nil
+	}
+	if _, rerr := s.authors.Create(ctx, current); rerr != nil {
+		return logDBError(ctx, "restore author", rerr)
+	}
+	if err != nil {
+		return logDBError(ctx, "count posts by author", err)
+	}
+	return Wrap(errAuthorInUse, ErrConflict, fmt.Sprintf("%d posts", n))
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCreateAuthorTakenSlugIsConflict(t *testing.T) {
+	svc := NewAuthorService(NewMemoryAuthorRepository(), NewMemoryPostRepository())
+	first, err := svc.CreateAuthor(context.Background(), models.Author{Name: "Ana García"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if first.Slug != "ana-garcia" {
+		t.Fatalf("slug generado: %q, want ana-garcia", first.Slug)
+	}
+
+	if _, err := svc.CreateAuthor(context.Background(), models.Author{Name: "Ana Garcia"}); !errors.Is(err, ErrConflict) {
+		t.Fatalf("nombre con el mismo slug: %v, want ErrConflict", err)
+	}
+	if _, err := svc.CreateAuthor(context.Background(), models.Author{Name: "Otra Ana", Slug: "ana-garcia"}); !errors.Is(err, ErrConflict) {
+		t.Fatalf("slug explícito en uso: %v, want ErrConflict", err)
+	}
+
+	authors, err := svc.ListAuthors(context.Background())
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(authors) != 1 {
+		t.Fatalf("autores: %d, want 1", len(authors))
+	}
+}
+
+func TestCreateAuthorWithoutSlugUsesFreeFallback(t *testing.T) {
+	svc := NewAuthorService(NewMemoryAuthorRepository(), NewMemoryPostRepository())
+	for i, want := range []string{"author", "author-2", "author-3"} {
+		a, err := svc.CreateAuthor(context.Background(), models.Author{Name: []string{"???", "山田", "★"}[i]})
+		if err != nil {
+			t.Fatalf("create %d: %v", i, err)
+		}
+		if a.Slug != want {
+			t.Fatalf("slug %d: %q, want %q", i, a.Slug, want)
+		}
+	}
+	if _, err := svc.CreateAuthor(context.Background(), models.Author{Name: "Otro", Slug: "author"}); !errors.Is(err, ErrConflict) {
+		t.Fatalf("slug explícito en uso: %v, want ErrConflict", err)
+	}
+}
+
+// racingPosts simula un alta de post concurrente: tras el primer CountByAuthor ejecuta
+// onCount, que inserta un post del autor antes de que DeleteAuthor lo borre.
+type racingPosts struct {
+	*MemoryPostRepository
+	onCount func()
+}
+
+func (r *racingPosts) CountByAuthor(ctx context.Context, authorID primitive.ObjectID) (int64, error) {
+	n, err := r.MemoryPostRepository.CountByAuthor(ctx, authorID)
+	if r.onCount != nil {
+		r.onCount()
+		r.onCount = nil
+	}
+	return n, err
+}
+
+func TestDeleteAuthorRestoresOnConcurrentPost(t *testing.T) {
+	authors := NewMemoryAuthorRepository()
+	posts := &racingPosts{MemoryPostRepository: NewMemoryPostRepository()}
+	svc := NewAuthorService(authors, posts)
+	a, err := svc.CreateAuthor(context.Background(), models.Author{Name: "Ana García"})
+	if err != nil {
+		t.Fatalf("create author: %v", err)
+	}
+	posts.onCount = func() {
+		_, err := posts.Create(context.Background(), models.Post{
+			Title: "Primer post", Slug: "primer-post", Author: a.Name, AuthorID: a.ID, Content: "contenido", Version: 1,
+		})
+		if err != nil {
+			t.Fatalf("create post: %v", err)
+		}
+	}
+
+	if err := svc.DeleteAuthor(context.Background(), a.ID.Hex()); !errors.Is(err, ErrConflict) {
+		t.Fatalf("delete con post concurrente: %v, want ErrConflict", err)
+	}
+	got, err := svc.GetAuthor(context.Background(), a.ID.Hex())
+	if err != nil || got.Slug != a.Slug || !got.CreatedAt.Equal(a.CreatedAt) {
+		t.Fatalf("autor restaurado: %+v %v", got, err)
+	}
+}
@@ -0,0 +1,65 @@
+// services/commentRepository.go
+//
+// Paquete services: contrato de persistencia de comentarios.
+//
+// Convenciones:
+//   - Igual que RevisionRepository: CommentService depende de la interfaz, las
+//     implementaciones envuelven sus errores con sentinelas y las reglas (hilos,
+//     moderación, visibilidad) viven en CommentService.
+//   - Los listados se ordenan por id ascendente (del más viejo al más nuevo); el cursor
+//     de paginación es el id del último comentario recibido.
+package services
+
+import (
+	"context"
+	"time"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CommentQuery filtra los comentarios de CommentRepository.List. Los campos vacíos no
+// filtran.
+//
+// Campos:
+//   - PostID: sólo comentarios de ese post.
+//   - Roots: sólo raíces de hilo (sin RootID).
+//   - RootIDs: sólo respuestas de esos hilos (no nil, aunque esté vacío).
+//   - Status: sólo ese estado de moderación.
+//   - After: sólo ids mayores (cursor).
+//   - Limit: máximo de comentarios (<= 0 sin límite).
+type CommentQuery struct {
+	PostID  primitive.ObjectID
+	Roots   bool
+	RootIDs []primitive.ObjectID
+	Status  string
+	After   primitive.ObjectID
+	Limit   int
+}
+
+// CommentRepository define las operaciones de almacenamiento de comentarios.
+//
+// Implementaciones:
+//   - MongoCommentRepository: colección "comments" en MongoDB.
+//   - MemoryCommentRepository: mapa en memoria.
+//   - SQLiteCommentRepository: tabla comments.
+//
+// Errores esperados:
+//   - ErrNotFound si el comentario no existe (Get).
+//   - ErrDB ante fallas del almacenamiento.
+type CommentRepository interface {
+	// Create persiste c tal cual (CreatedAt y Status ya fijados) y retorna su ObjectID.
+	Create(ctx context.Context, c models.Comment) (primitive.ObjectID, error)
+	// Get recupera un comentario por id.
+	Get(ctx context.Context, id primitive.ObjectID) (models.Comment, error)
+	// List retorna los comentarios que cumplen q ordenados por id ascendente.
+	List(ctx context.Context, q CommentQuery) ([]models.Comment, error)
+	// SetStatus fija status, moderatedAt y moderatedBy en los comentarios ids que
+	// tengan otro estado; retorna cuántos cambió (los ids inexistentes se ignoran).
+	SetStatus(ctx context.Context, ids []primitive.ObjectID, status, moderatedBy string, moderatedAt time.Time) (int64, error)
+	// CountByPosts cuenta los comentarios con status de cada post de postIDs (los posts
+	// sin comentarios no aparecen en el mapa).
+	CountByPosts(ctx context.Context, postIDs []primitive.ObjectID, status string) (map[primitive.ObjectID]int64, error)
+	// DeleteByPosts borra todos los comentarios de los posts indicados.
+	DeleteByPosts(ctx context.Context, postIDs []primitive.ObjectID) (int64, error)
+}
@@ -0,0 +1,328 @@
+// services/commentService.go
+//
+// Paquete services: lógica de negocio para comentarios de lectores.
+//
+// Convenciones:
+//   - Mismas reglas que PostService: repositorios por constructor, errores envueltos
+//     con sentinelas, ErrDB registrados con logDBError y un span por método.
+//   - Sólo se comentan posts publicados; los comentarios nuevos quedan "pending" hasta
+//     que un administrador los modera (ModerateComments).
+//   - El listado público pagina raíces de hilo aprobadas con un cursor (id del último
+//     comentario) y trae anidadas sus respuestas aprobadas; una respuesta cuyo padre
+//     no está aprobado no se muestra.
+//   - Un post en la papelera oculta sus comentarios (404) y los recupera al restaurarlo;
+//     al purgarlo se borran (ver PostService.deleteHistory).
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Límites de los listados paginados por cursor.
+const (
+	defaultCommentLimit = 20
+	maxCommentLimit     = 100
+)
+
+var (
+	// errCommentsClosed es la causa al comentar un post que no está publicado.
+	errCommentsClosed = errors.New("post is not published")
+	// errUnknownParent es la causa al responder a un comentario inexistente, de otro
+	// post o no aprobado.
+	errUnknownParent = errors.New("unknown parent comment")
+	// errUnknownCommentStatus es la causa de un estado de moderación inválido.
+	errUnknownCommentStatus = errors.New("unknown comment status")
+)
+
+// ValidCommentStatus indica si status es un estado de moderación de comentarios.
+func ValidCommentStatus(status string) bool {
+	switch status {
+	case models.CommentPending, models.CommentApproved, models.CommentRejected, models.CommentSpam:
+		return true
+	}
+	return false
+}
+
+// CommentService implementa los casos de uso de comentarios sobre un
+// CommentRepository; valida los posts comentados a través de un PostRepository.
+type CommentService struct {
+	comments CommentRepository
+	posts    PostRepository
+}
+
+// NewCommentService crea el servicio de comentarios usando comments como
+// almacenamiento y posts para validar el post comentado.
+func NewCommentService(comments CommentRepository, posts PostRepository) *CommentService {
+	return &CommentService{comments: comments, posts: posts}
+}
+
+// CommentPage es una página de comentarios paginada por cursor.
+//
+// Campos:
+//   - Items: comentarios de la página, del más viejo al más nuevo.
+//   - NextCursor: valor de cursor para la página siguiente ("" si no hay más).
+type CommentPage struct {
+	Items      []models.Comment `json:"items"`
+	NextCursor string           `json:"nextCursor,omitempty"`
+}
+
+// CommentModerationResult resume una moderación en lote.
+//
+// Campos:
+//   - Status: estado aplicado.
+//   - Updated: cantidad de comentarios que cambiaron de estado.
+type CommentModerationResult struct {
+	Status  string `json:"status"`
+	Updated int64  `json:"updated"`
+}
+
+// ListComments retorna una página de hilos aprobados del post postIDHex.
+//
+// Parámetros:
+//   - cursor: NextCursor de la página anterior ("" = primera página).
+//   - limit: hilos por página (si <=0 se usa 20; si >100 se trunca a 100).
+//
+// Errores:
+//   - ErrInvalidID: id de post inválido.
+//   - ErrNotFound: el post no existe o está en la papelera.
+//   - ErrInvalidInput: cursor inválido.
+//   - ErrDB: error del driver.
+func (s *CommentService) ListComments(ctx context.Context, postIDHex, cursor string, limit int) (_ CommentPage, err error) {
+	ctx, span := startSpan(ctx, "CommentService.ListComments", attribute.String("post.id", postIDHex))
+	defer endSpan(span, &err)
+
+	post, err := s.visiblePost(ctx, postIDHex)
+	if err != nil {
+		return CommentPage{}, err
+	}
+	after, err := parseCommentCursor(cursor)
+	if err != nil {
+		return CommentPage{}, err
+	}
+	limit = commentLimit(limit)
+
+	roots, err := s.comments.List(ctx, CommentQuery{
+		PostID: post.ID, Roots: true, Status: models.CommentApproved, After: after, Limit: limit + 1,
+	})
+	if err != nil {
+		return CommentPage{}, logDBError(ctx, "list comments", err)
+	}
+	page := commentPage(roots, limit)
+	if len(page.Items) == 0 {
+		return page, nil
+	}
+
+	rootIDs := make([]primitive.ObjectID, len(page.Items))
+	for i, c := range page.Items {
+		rootIDs[i] = c.ID
+	}
+	replies, err := s.comments.List(ctx, CommentQuery{PostID: post.ID, RootIDs: rootIDs, Status: models.CommentApproved})
+	if err != nil {
+		return CommentPage{}, logDBError(ctx, "list comment replies", err)
+	}
+	page.Items = threadComments(page.Items, replies)
+	span.SetAttributes(attribute.Int("comment.threads", len(page.Items)), attribute.Int("comment.replies", len(replies)))
+	return page, nil
+}
+
+// CreateComment agrega un comentario (o una respuesta si c.ParentID no es nil) al post
+// postIDHex y lo retorna.
+//
+// Reglas:
+//   - Estampa CreatedAt=now y Status=pending; ignora ID, RootID y la moderación de c.
+//   - Una respuesta debe apuntar a un comentario aprobado del mismo post; hereda su
+//     raíz de hilo.
+//
+// Errores:
+//   - ErrInvalidID: id de post inválido.
+//   - ErrNotFound: el post no existe o está en la papelera.
+//   - ErrConflict: el post no está publicado.
+//   - ErrInvalidInput: comentario padre inexistente, de otro post o no aprobado.
+//   - ErrDB: error del driver.
+func (s *CommentService) CreateComment(ctx context.Context, postIDHex string, c models.Comment) (_ models.Comment, err error) {
+	ctx, span := startSpan(ctx, "CommentService.CreateComment", attribute.String("post.id", postIDHex))
+	defer endSpan(span, &err)
+
+	post, err := s.visiblePost(ctx, postIDHex)
+	if err != nil {
+		return models.Comment{}, err
+	}
+	if postStatus(post) != models.StatusPublished {
+		return models.Comment{}, Wrap(errCommentsClosed, ErrConflict, "create comment")
+	}
+
+	c.ID = primitive.NilObjectID
+	c.PostID = post.ID
+	c.RootID = nil
+	if c.ParentID != nil {
+		parent, err := s.comments.Get(ctx, *c.ParentID)
+		if errors.Is(err, ErrNotFound) || (err == nil && (parent.PostID != post.ID || parent.Status != models.CommentApproved)) {
+			return models.Comment{}, Wrap(errUnknownParent, ErrInvalidInput, "parentId")
+		}
+		if err != nil {
+			return models.Comment{}, logDBError(ctx, "get parent comment", err)
+		}
+		root := parent.ID
+		if parent.RootID != nil {
+			root = *parent.RootID
+		}
+		c.RootID = &root
+	}
+	c.Status = models.CommentPending
+	c.CreatedAt = time.Now().UTC()
+	c.ModeratedAt = nil
+	c.ModeratedBy = ""
+	c.Replies = nil
+
+	if c.ID, err = s.comments.Create(ctx, c); err != nil {
+		return models.Comment{}, logDBError(ctx, "create comment", err)
+	}
+	return c, nil
+}
+
+// ListModerationQueue retorna una página de comentarios en status de todos los posts,
+// del más viejo al más nuevo y sin anidar.
+//
+// Parámetros:
+//   - status: estado a listar ("" = pending).
+//   - cursor / limit: como en ListComments.
+//
+// Errores:
+//   - ErrInvalidInput: status o cursor inválidos.
+//   - ErrDB: error del driver.
+func (s *CommentService) ListModerationQueue(ctx context.Context, status, cursor string, limit int) (_ CommentPage, err error) {
+	ctx, span := startSpan(ctx, "CommentService.ListModerationQueue")
+	defer endSpan(span, &err)
+
+	if status == "" {
+		status = models.CommentPending
+	}
+	if !ValidCommentStatus(status) {
+		return CommentPage{}, Wrap(errUnknownCommentStatus, ErrInvalidInput, "status")
+	}
+	after, err := parseCommentCursor(cursor)
+	if err != nil {
+		return CommentPage{}, err
+	}
+	limit = commentLimit(limit)
+
+	items, err := s.comments.List(ctx, CommentQuery{Status: status, After: after, Limit: limit + 1})
+	if err != nil {
+		return CommentPage{}, logDBError(ctx, "list moderation queue", err)
+	}
+	return commentPage(items, limit), nil
+}
+
+// ModerateComments fija status en los comentarios idsHex.
+//
+// Reglas:
+//   - Los ids inexistentes o que ya tienen status se ignoran (no cuentan en Updated).
+//   - moderator queda en ModeratedBy junto con ModeratedAt=now.
+//
+// Errores:
+//   - ErrInvalidInput: status inválido.
+//   - ErrInvalidID: algún id no es un ObjectID.
+//   - ErrDB: error del driver.
+func (s *CommentService) ModerateComments(ctx context.Context, idsHex []string, status, moderator string) (_ CommentModerationResult, err error) {
+	ctx, span := startSpan(ctx, "CommentService.ModerateComments",
+		attribute.String("comment.status", status), attribute.Int("comment.ids", len(idsHex)))
+	defer endSpan(span, &err)
+
+	if !ValidCommentStatus(status) {
+		return CommentModerationResult{}, Wrap(errUnknownCommentStatus, ErrInvalidInput, "status")
+	}
+	ids := make([]primitive.ObjectID, 0, len(idsHex))
+	for _, hex := range idsHex {
+		id, err := primitive.ObjectIDFromHex(hex)
+		if err != nil {
+			return CommentModerationResult{}, Wrap(err, ErrInvalidID, "parse objectid")
+		}
+		if !containsObjectID(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+
+	n, err := s.comments.SetStatus(ctx, ids, status, moderator, time.Now().UTC())
+	if err != nil {
+		return CommentModerationResult{}, logDBError(ctx, "moderate comments", err)
+	}
+	span.SetAttributes(attribute.Int64("comment.updated", n))
+	return CommentModerationResult{Status: status, Updated: n}, nil
+}
+
+// visiblePost recupera el post idHex; los de la papelera cuentan como inexistentes.
+func (s *CommentService) visiblePost(ctx context.Context, idHex string) (models.Post, error) {
+	oid, err := primitive.ObjectIDFromHex(idHex)
+	if err != nil {
+		return models.Post{}, Wrap(err, ErrInvalidID, "parse objectid")
+	}
+	post, err := s.posts.Get(ctx, oid)
+	if err != nil {
+		return models.Post{}, logDBError(ctx, "get post", err)
+	}
+	if post.DeletedAt != nil {
+		return models.Post{}, Wrap(errPostDeleted, ErrNotFound, "post not found")
+	}
+	return post, nil
+}
+
+// parseCommentCursor decodifica un cursor ("" = desde el principio).
+func parseCommentCursor(cursor string) (primitive.ObjectID, error) {
+	if cursor == "" {
+		return primitive.NilObjectID, nil
+	}
+	id, err := primitive.ObjectIDFromHex(cursor)
+	if err != nil {
+		return primitive.NilObjectID, Wrap(err, ErrInvalidInput, "cursor")
+	}
+	return id, nil
+}
+
+// commentLimit aplica el default y el máximo de los listados de comentarios.
+func commentLimit(limit int) int {
+	if limit <= 0 {
+		return defaultCommentLimit
+	}
+	if limit > maxCommentLimit {
+		return maxCommentLimit
+	}
+	return limit
+}
+
+// commentPage recorta items (pedidos con limit+1) a limit y fija NextCursor si sobró uno.
+func commentPage(items []models.Comment, limit int) CommentPage {
+	if len(items) <= limit {
+		return CommentPage{Items: items}
+	}
+	items = items[:limit]
+	return CommentPage{Items: items, NextCursor: items[limit-1].ID.Hex()}
+}
+
+// threadComments anida replies (ordenadas por id) bajo sus padres en roots; las
+// respuestas cuyo padre no está entre roots ni replies se descartan.
+func threadComments(roots, replies []models.Comment) []models.Comment {
+	children := map[primitive.ObjectID][]models.Comment{}
+	for _, c := range replies {
+		if c.ParentID != nil {
+			children[*c.ParentID] = append(children[*c.ParentID], c)
+		}
+	}
+	var attach func(c models.Comment) models.Comment
+	attach = func(c models.Comment) models.Comment {
+		for _, child := range children[c.ID] {
+			c.Replies = append(c.Replies, attach(child))
+		}
+		return c
+	}
+	out := make([]models.Comment, len(roots))
+	for i, root := range roots {
+		out[i] = attach(root)
+	}
+	return out
+}
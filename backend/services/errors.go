@@ -11,8 +11,10 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 )
 
 // Sentinelas de error de dominio.
@@ -31,6 +33,11 @@ var (
 	// ErrConflict: conflicto de estado, duplicados o violación de restricciones únicas.
 	ErrConflict = errors.New("conflict")
 
+	// ErrVersionMismatch: la versión esperada (If-Match) no coincide con la almacenada.
+	// Envuelve a ErrConflict, así que errors.Is(err, ErrConflict) también es true; los
+	// controladores la distinguen para responder 412 en lugar de 409.
+	ErrVersionMismatch = fmt.Errorf("%w: version_mismatch", ErrConflict)
+
 	// ErrDB: error del driver o infraestructura de datos (Mongo, SQL, etc.).
 	ErrDB = errors.New("db_error")
 )
@@ -59,3 +66,36 @@ func Wrap(cause error, sentinel error, msg string) error {
 	}
 	return fmt.Errorf("%w: %s: %v", sentinel, msg, cause)
 }
+
+// sentinels son los sentinelas conocidos, en el orden usado por SentinelName.
+var sentinels = []error{ErrInvalidInput, ErrInvalidID, ErrNotFound, ErrConflict, ErrDB}
+
+// SentinelName retorna el nombre estable del sentinel de err, útil como etiqueta
+// de métricas o trazas: "ok" si err es nil, "invalid_input", "not_found", ...,
+// o "unknown" si err no envuelve ninguno.
+func SentinelName(err error) string {
+	if err == nil {
+		return "ok"
+	}
+	for _, s := range sentinels {
+		if errors.Is(err, s) {
+			return s.Error()
+		}
+	}
+	return "unknown"
+}
+
+// logDBError registra err si es un ErrDB y lo retorna sin cambios.
+//
+// Se usa en la capa de servicios, donde ctx trae el request ID asignado por el
+// middleware HTTP: el registro queda correlacionado con la línea de acceso.
+// Los demás sentinelas (ErrNotFound, ErrInvalidID, ...) son errores esperados y no se loguean.
+//
+// Ejemplo:
+//   return logDBError(ctx, "list posts", err)
+func logDBError(ctx context.Context, op string, err error) error {
+	if err != nil && errors.Is(err, ErrDB) {
+		slog.ErrorContext(ctx, "error de almacenamiento", slog.String("op", op), slog.String("error", err.Error()))
+	}
+	return err
+}
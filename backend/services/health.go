@@ -0,0 +1,180 @@
+// services/health.go
+//
+// Paquete services: chequeos de dependencias para los probes de readiness.
+//
+// Convenciones:
+//   - Cada driver de almacenamiento aporta sus HealthCheck (ver Store.HealthChecks).
+//   - Los chequeos corren en paralelo, cada uno con su propio timeout corto.
+//   - Un chequeo Critical caído marca la instancia como no lista (HTTP 503);
+//     los no críticos sólo se informan.
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// readinessTimeout limita la duración de cada chequeo individual.
+const readinessTimeout = 2 * time.Second
+
+// Estados reportados por los chequeos y el reporte agregado.
+const (
+	HealthUp   = "up"
+	HealthDown = "down"
+)
+
+// HealthCheck verifica una dependencia.
+//
+// Campos:
+//   - Name: identificador del componente en el JSON (ej. "mongo").
+//   - Critical: si falla, la instancia no está lista.
+//   - Check: retorna nil si el componente está sano.
+type HealthCheck struct {
+	Name     string
+	Critical bool
+	Check    func(ctx context.Context) error
+}
+
+// ComponentStatus es el resultado de un HealthCheck.
+type ComponentStatus struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	Critical  bool    `json:"critical"`
+	LatencyMs float64 `json:"latencyMs"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// ReadinessReport agrega los resultados de todos los chequeos.
+//
+// Campos:
+//   - Status: "up" si ningún chequeo crítico falló; "down" en caso contrario.
+//   - Components: resultado por componente, en el mismo orden de los chequeos.
+type ReadinessReport struct {
+	Status     string            `json:"status"`
+	Components []ComponentStatus `json:"components"`
+}
+
+// Ready indica si la instancia puede recibir tráfico.
+func (r ReadinessReport) Ready() bool {
+	return r.Status == HealthUp
+}
+
+// RunHealthChecks ejecuta checks en paralelo (readinessTimeout cada uno).
+func RunHealthChecks(ctx context.Context, checks []HealthCheck) ReadinessReport {
+	report := ReadinessReport{Status: HealthUp, Components: make([]ComponentStatus, len(checks))}
+
+	var wg sync.WaitGroup
+	for i, hc := range checks {
+		wg.Add(1)
+		go func(i int, hc HealthCheck) {
+			defer wg.Done()
+			cctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+			defer cancel()
+
+			start := time.Now()
+			err := hc.Check(cctx)
+			st := ComponentStatus{
+				Name:      hc.Name,
+				Status:    HealthUp,
+				Critical:  hc.Critical,
+				LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+			}
+			if err != nil {
+				st.Status = HealthDown
+				st.Error = err.Error()
+			}
+			report.Components[i] = st
+		}(i, hc)
+	}
+	wg.Wait()
+
+	for _, st := range report.Components {
+		if st.Critical && st.Status == HealthDown {
+			report.Status = HealthDown
+			break
+		}
+	}
+	return report
+}
+
+// requiredMongoIndexes son los índices sin los cuales la API no funciona
+// correctamente (p. ej. $text falla sin text_title_content).
+var requiredMongoIndexes = map[string][]string{
+	"posts": {"text_title_content", "idx_published_publishedAt"},
+}
+
+// mongoHealthChecks arma los chequeos del driver "mongo".
+//
+// Chequeos:
+//   - mongo (crítico): ping al servidor.
+//   - mongo_indexes (crítico): existencia de requiredMongoIndexes.
+//   - mongo_migrations (no crítico): migraciones pendientes.
+func mongoHealthChecks(db *mongo.Database, migrator *Migrator) []HealthCheck {
+	return []HealthCheck{
+		{
+			Name:     "mongo",
+			Critical: true,
+			Check: func(ctx context.Context) error {
+				return db.Client().Ping(ctx, nil)
+			},
+		},
+		{
+			Name:     "mongo_indexes",
+			Critical: true,
+			Check: func(ctx context.Context) error {
+				return checkMongoIndexes(ctx, db)
+			},
+		},
+		{
+			Name:     "mongo_migrations",
+			Critical: false,
+			Check: func(ctx context.Context) error {
+				statuses, err := migrator.Status(ctx)
+				if err != nil {
+					return err
+				}
+				var pending []int
+				for _, st := range statuses {
+					if !st.Applied {
+						pending = append(pending, st.Version)
+					}
+				}
+				if len(pending) > 0 {
+					return fmt.Errorf("migraciones pendientes: %v", pending)
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// checkMongoIndexes verifica que existan todos los requiredMongoIndexes.
+func checkMongoIndexes(ctx context.Context, db *mongo.Database) error {
+	for col, names := range requiredMongoIndexes {
+		cur, err := db.Collection(col).Indexes().List(ctx)
+		if err != nil {
+			return err
+		}
+		var specs []bson.M
+		if err := cur.All(ctx, &specs); err != nil {
+			return err
+		}
+		existing := map[string]bool{}
+		for _, spec := range specs {
+			if name, ok := spec["name"].(string); ok {
+				existing[name] = true
+			}
+		}
+		for _, name := range names {
+			if !existing[name] {
+				return fmt.Errorf("falta el índice %s.%s", col, name)
+			}
+		}
+	}
+	return nil
+}
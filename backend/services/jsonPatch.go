@@ -0,0 +1,299 @@
+// services/jsonPatch.go
+//
+// Paquete services: aplicación de JSON Patch (RFC 6902) sobre documentos JSON genéricos.
+//
+// Convenciones:
+//   - Los documentos son el resultado de json.Unmarshal en any (map[string]any, []any,
+//     string, float64, bool, nil); applyJSONPatch no conoce la entidad Post.
+//   - Las rutas son JSON Pointers (RFC 6901): "/tags/0", "/tags/-", "~1" = "/", "~0" = "~".
+//   - Un "test" fallido retorna ErrConflict (el documento cambió respecto de lo que el
+//     cliente esperaba); cualquier otra falla (op desconocida, ruta inexistente, índice
+//     fuera de rango) retorna ErrInvalidInput.
+//   - La aplicación es todo o nada: ante un error el documento original no se persiste.
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// PatchOperation es una operación de JSON Patch.
+//
+// Campos:
+//   - Op: "add" | "remove" | "replace" | "move" | "copy" | "test".
+//   - Path: JSON Pointer del destino.
+//   - From: JSON Pointer de origen (move, copy).
+//   - Value: valor JSON (add, replace, test); nil si no se envió.
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// errPatchTest es la causa de un "test" que no coincide.
+var errPatchTest = errors.New("test operation failed")
+
+// errPatchPath es el error de una ruta inexistente.
+var errPatchPath = Wrap(errors.New("path not found"), ErrInvalidInput, "json patch")
+
+// applyJSONPatch aplica ops en orden sobre doc y retorna el documento resultante.
+// doc puede modificarse en el lugar; el llamador debe descartarlo si hay error.
+func applyJSONPatch(doc any, ops []PatchOperation) (any, error) {
+	for i, op := range ops {
+		var err error
+		doc, err = applyPatchOperation(doc, op)
+		if err != nil {
+			return nil, fmt.Errorf("%w (operation %d: %s %s)", err, i, op.Op, op.Path)
+		}
+	}
+	return doc, nil
+}
+
+// applyPatchOperation aplica una operación sobre doc.
+func applyPatchOperation(doc any, op PatchOperation) (any, error) {
+	path, err := parseJSONPointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, Wrap(errors.New("missing value"), ErrInvalidInput, op.Op+" "+op.Path)
+		}
+		var value any
+		if err := json.Unmarshal(op.Value, &value); err != nil {
+			return nil, Wrap(err, ErrInvalidInput, op.Op+" "+op.Path)
+		}
+		switch op.Op {
+		case "add":
+			return pointerAdd(doc, path, value)
+		case "replace":
+			if len(path) == 0 {
+				return value, nil
+			}
+			if doc, _, err = pointerRemove(doc, path); err != nil {
+				return nil, err
+			}
+			return pointerAdd(doc, path, value)
+		default:
+			current, err := pointerGet(doc, path)
+			if err != nil {
+				return nil, err
+			}
+			if !reflect.DeepEqual(current, value) {
+				return nil, Wrap(errPatchTest, ErrConflict, "test "+op.Path)
+			}
+			return doc, nil
+		}
+
+	case "remove":
+		doc, _, err = pointerRemove(doc, path)
+		return doc, err
+
+	case "move", "copy":
+		from, err := parseJSONPointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		if op.Op == "move" {
+			if isPointerPrefix(from, path) && len(from) < len(path) {
+				return nil, Wrap(errors.New("cannot move a value into itself"), ErrInvalidInput, "move "+op.From)
+			}
+			var value any
+			if doc, value, err = pointerRemove(doc, from); err != nil {
+				return nil, err
+			}
+			return pointerAdd(doc, path, value)
+		}
+		value, err := pointerGet(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return pointerAdd(doc, path, deepCopyJSON(value))
+
+	default:
+		return nil, Wrap(fmt.Errorf("unknown op %q", op.Op), ErrInvalidInput, "json patch")
+	}
+}
+
+// parseJSONPointer separa un JSON Pointer en tokens ya decodificados ("" = raíz); un
+// "~" que no forma "~0" ni "~1" es inválido.
+func parseJSONPointer(p string) ([]string, error) {
+	if p == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(p, "/") {
+		return nil, Wrap(fmt.Errorf("invalid pointer %q", p), ErrInvalidInput, "json patch")
+	}
+	tokens := strings.Split(p[1:], "/")
+	for i, t := range tokens {
+		if strings.Count(t, "~") != strings.Count(t, "~0")+strings.Count(t, "~1") {
+			return nil, Wrap(fmt.Errorf("invalid escape in pointer %q", p), ErrInvalidInput, "json patch")
+		}
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// isPointerPrefix indica si prefix es prefijo (o igual) de path.
+func isPointerPrefix(prefix, path []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// pointerGet retorna el valor en path.
+func pointerGet(doc any, path []string) (any, error) {
+	for _, token := range path {
+		switch node := doc.(type) {
+		case map[string]any:
+			v, ok := node[token]
+			if !ok {
+				return nil, errPatchPath
+			}
+			doc = v
+		case []any:
+			i, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			doc = node[i]
+		default:
+			return nil, errPatchPath
+		}
+	}
+	return doc, nil
+}
+
+// pointerAdd agrega value en path y retorna el nodo actualizado. En arreglos inserta
+// en la posición indicada ("-" = al final); en objetos crea o reemplaza el miembro.
+func pointerAdd(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	token, rest := path[0], path[1:]
+	switch node := doc.(type) {
+	case map[string]any:
+		if len(rest) == 0 {
+			node[token] = value
+			return node, nil
+		}
+		child, ok := node[token]
+		if !ok {
+			return nil, errPatchPath
+		}
+		child, err := pointerAdd(child, rest, value)
+		if err != nil {
+			return nil, err
+		}
+		node[token] = child
+		return node, nil
+	case []any:
+		if len(rest) == 0 {
+			i := len(node)
+			if token != "-" {
+				var err error
+				if i, err = arrayIndex(token, len(node)); err != nil {
+					return nil, err
+				}
+			}
+			node = append(node, nil)
+			copy(node[i+1:], node[i:])
+			node[i] = value
+			return node, nil
+		}
+		i, err := arrayIndex(token, len(node)-1)
+		if err != nil {
+			return nil, err
+		}
+		if node[i], err = pointerAdd(node[i], rest, value); err != nil {
+			return nil, err
+		}
+		return node, nil
+	default:
+		return nil, errPatchPath
+	}
+}
+
+// pointerRemove quita el valor en path; retorna el nodo actualizado y el valor quitado.
+func pointerRemove(doc any, path []string) (any, any, error) {
+	if len(path) == 0 {
+		return nil, nil, Wrap(errors.New("cannot remove the document root"), ErrInvalidInput, "json patch")
+	}
+	token, rest := path[0], path[1:]
+	switch node := doc.(type) {
+	case map[string]any:
+		child, ok := node[token]
+		if !ok {
+			return nil, nil, errPatchPath
+		}
+		if len(rest) == 0 {
+			delete(node, token)
+			return node, child, nil
+		}
+		child, removed, err := pointerRemove(child, rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		node[token] = child
+		return node, removed, nil
+	case []any:
+		i, err := arrayIndex(token, len(node)-1)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(rest) == 0 {
+			removed := node[i]
+			return append(node[:i], node[i+1:]...), removed, nil
+		}
+		child, removed, err := pointerRemove(node[i], rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		node[i] = child
+		return node, removed, nil
+	default:
+		return nil, nil, errPatchPath
+	}
+}
+
+// arrayIndex convierte token en un índice entre 0 y max (inclusive).
+func arrayIndex(token string, max int) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i > max || (len(token) > 1 && token[0] == '0') {
+		return 0, Wrap(fmt.Errorf("invalid array index %q", token), ErrInvalidInput, "json patch")
+	}
+	return i, nil
+}
+
+// deepCopyJSON copia un valor JSON genérico (copy no debe compartir nodos con el origen).
+func deepCopyJSON(v any) any {
+	switch node := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(node))
+		for k, child := range node {
+			out[k] = deepCopyJSON(child)
+		}
+		return out
+	case []any:
+		out := make([]any, len(node))
+		for i, child := range node {
+			out[i] = deepCopyJSON(child)
+		}
+		return out
+	default:
+		return v
+	}
+}
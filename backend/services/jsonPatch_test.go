@@ -0,0 +1,106 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestApplyJSONPatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		patch   string
+		want    string
+		wantErr error
+	}{
+		// add
+		{"add miembro", `{"a":1}`, `[{"op":"add","path":"/b","value":2}]`, `{"a":1,"b":2}`, nil},
+		{"add reemplaza miembro", `{"a":1}`, `[{"op":"add","path":"/a","value":[1]}]`, `{"a":[1]}`, nil},
+		{"add al final", `{"tags":["go"]}`, `[{"op":"add","path":"/tags/-","value":"mongo"}]`, `{"tags":["go","mongo"]}`, nil},
+		{"add inserta", `{"tags":["a","c"]}`, `[{"op":"add","path":"/tags/1","value":"b"}]`, `{"tags":["a","b","c"]}`, nil},
+		{"add en len", `{"tags":["a"]}`, `[{"op":"add","path":"/tags/1","value":"b"}]`, `{"tags":["a","b"]}`, nil},
+		{"add índice fuera de rango", `{"tags":["a"]}`, `[{"op":"add","path":"/tags/2","value":"b"}]`, "", ErrInvalidInput},
+		{"add índice negativo", `{"tags":["a"]}`, `[{"op":"add","path":"/tags/-1","value":"b"}]`, "", ErrInvalidInput},
+		{"add índice con cero inicial", `{"tags":["a","b"]}`, `[{"op":"add","path":"/tags/01","value":"c"}]`, "", ErrInvalidInput},
+		{"add padre inexistente", `{}`, `[{"op":"add","path":"/a/b","value":1}]`, "", ErrInvalidInput},
+		{"add sin value", `{}`, `[{"op":"add","path":"/a"}]`, "", ErrInvalidInput},
+		{"add raíz", `{"a":1}`, `[{"op":"add","path":"","value":{"b":2}}]`, `{"b":2}`, nil},
+
+		// remove
+		{"remove miembro", `{"a":1,"b":2}`, `[{"op":"remove","path":"/a"}]`, `{"b":2}`, nil},
+		{"remove elemento", `{"tags":["a","b","c"]}`, `[{"op":"remove","path":"/tags/1"}]`, `{"tags":["a","c"]}`, nil},
+		{"remove fuera de rango", `{"tags":["a"]}`, `[{"op":"remove","path":"/tags/1"}]`, "", ErrInvalidInput},
+		{"remove con guion", `{"tags":["a"]}`, `[{"op":"remove","path":"/tags/-"}]`, "", ErrInvalidInput},
+		{"remove inexistente", `{"a":1}`, `[{"op":"remove","path":"/b"}]`, "", ErrInvalidInput},
+		{"remove raíz", `{"a":1}`, `[{"op":"remove","path":""}]`, "", ErrInvalidInput},
+
+		// replace
+		{"replace miembro", `{"a":1}`, `[{"op":"replace","path":"/a","value":"x"}]`, `{"a":"x"}`, nil},
+		{"replace elemento", `{"tags":["a","b"]}`, `[{"op":"replace","path":"/tags/0","value":"z"}]`, `{"tags":["z","b"]}`, nil},
+		{"replace inexistente", `{"a":1}`, `[{"op":"replace","path":"/b","value":2}]`, "", ErrInvalidInput},
+		{"replace raíz", `{"a":1}`, `[{"op":"replace","path":"","value":[1]}]`, `[1]`, nil},
+
+		// move
+		{"move miembro", `{"a":1,"b":{}}`, `[{"op":"move","from":"/a","path":"/b/c"}]`, `{"b":{"c":1}}`, nil},
+		{"move dentro del arreglo", `{"tags":["a","b","c"]}`, `[{"op":"move","from":"/tags/0","path":"/tags/-"}]`, `{"tags":["b","c","a"]}`, nil},
+		{"move a sí mismo", `{"a":1}`, `[{"op":"move","from":"/a","path":"/a"}]`, `{"a":1}`, nil},
+		{"move dentro de un hijo", `{"a":{"b":1}}`, `[{"op":"move","from":"/a","path":"/a/c"}]`, "", ErrInvalidInput},
+		{"move origen inexistente", `{"a":1}`, `[{"op":"move","from":"/x","path":"/b"}]`, "", ErrInvalidInput},
+
+		// copy
+		{"copy miembro", `{"a":{"x":1}}`, `[{"op":"copy","from":"/a","path":"/b"}]`, `{"a":{"x":1},"b":{"x":1}}`, nil},
+		{"copy no comparte nodos", `{"a":{"x":1}}`,
+			`[{"op":"copy","from":"/a","path":"/b"},{"op":"replace","path":"/b/x","value":2}]`, `{"a":{"x":1},"b":{"x":2}}`, nil},
+		{"copy a arreglo", `{"tags":["a"]}`, `[{"op":"copy","from":"/tags/0","path":"/tags/0"}]`, `{"tags":["a","a"]}`, nil},
+
+		// test
+		{"test igual", `{"version":3,"tags":["a"]}`, `[{"op":"test","path":"/version","value":3},{"op":"test","path":"/tags","value":["a"]}]`,
+			`{"version":3,"tags":["a"]}`, nil},
+		{"test distinto", `{"version":3}`, `[{"op":"test","path":"/version","value":2}]`, "", ErrConflict},
+		{"test fallido descarta lo anterior", `{"version":3,"tags":[]}`,
+			`[{"op":"add","path":"/tags/-","value":"go"},{"op":"test","path":"/version","value":2}]`, "", ErrConflict},
+		{"test tipo distinto", `{"version":3}`, `[{"op":"test","path":"/version","value":"3"}]`, "", ErrConflict},
+		{"test inexistente", `{}`, `[{"op":"test","path":"/version","value":3}]`, "", ErrInvalidInput},
+
+		// JSON Pointer
+		{"escape ~1", `{"a/b":1}`, `[{"op":"replace","path":"/a~1b","value":2}]`, `{"a/b":2}`, nil},
+		{"escape ~0", `{"m~n":1}`, `[{"op":"remove","path":"/m~0n"}]`, `{}`, nil},
+		{"escape ~01 es ~1 literal", `{"~1":1,"/":2}`, `[{"op":"remove","path":"/~01"}]`, `{"/":2}`, nil},
+		{"escape inválido", `{"a":1}`, `[{"op":"remove","path":"/~2"}]`, "", ErrInvalidInput},
+		{"puntero sin barra", `{"a":1}`, `[{"op":"remove","path":"a"}]`, "", ErrInvalidInput},
+		{"miembro vacío", `{"":1}`, `[{"op":"replace","path":"/","value":2}]`, `{"":2}`, nil},
+		{"op desconocida", `{"a":1}`, `[{"op":"merge","path":"/a"}]`, "", ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var doc any
+			if err := json.Unmarshal([]byte(tt.doc), &doc); err != nil {
+				t.Fatalf("doc: %v", err)
+			}
+			var ops []PatchOperation
+			if err := json.Unmarshal([]byte(tt.patch), &ops); err != nil {
+				t.Fatalf("patch: %v", err)
+			}
+			got, err := applyJSONPatch(doc, ops)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err: %v", err)
+			}
+			var want any
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatalf("want: %v", err)
+			}
+			gotJSON, _ := json.Marshal(got)
+			wantJSON, _ := json.Marshal(want)
+			if string(gotJSON) != string(wantJSON) {
+				t.Fatalf("got %s, want %s", gotJSON, wantJSON)
+			}
+		})
+	}
+}
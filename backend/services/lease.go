@@ -0,0 +1,29 @@
+// services/lease.go
+//
+// Paquete services: coordinación de trabajos de fondo entre instancias.
+//
+// Convenciones:
+//   - Un trabajo que no debe correr en paralelo en varias instancias (ej. el scheduler
+//     de publicaciones) toma un Lease antes de cada ciclo y lo renueva en el siguiente.
+//   - Store.Lease elige la implementación según el driver: mongoLease (colección
+//     "leases") o localLease, que siempre concede porque hay un único proceso.
+package services
+
+import "context"
+
+// Lease es un lock con nombre y expiración.
+type Lease interface {
+	// Acquire toma o renueva el lease; false si otra instancia lo tiene vigente.
+	Acquire(ctx context.Context) (bool, error)
+	// Release libera el lease si sigue a nombre de esta instancia.
+	Release(ctx context.Context) error
+}
+
+// localLease es el Lease de los drivers de un solo proceso: siempre se concede.
+type localLease struct{}
+
+// Acquire implementa Lease.
+func (localLease) Acquire(context.Context) (bool, error) { return true, nil }
+
+// Release implementa Lease.
+func (localLease) Release(context.Context) error { return nil }
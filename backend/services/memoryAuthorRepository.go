@@ -0,0 +1,160 @@
+// services/memoryAuthorRepository.go
+//
+// Paquete services: implementación en memoria de AuthorRepository.
+//
+// Convenciones:
+//   - Misma semántica que MongoAuthorRepository (incluida la unicidad del slug);
+//     segura para uso concurrente.
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// errNoAuthor es la causa usada cuando el autor no existe en memoria.
+var errNoAuthor = errors.New("no author with that id")
+
+// MemoryAuthorRepository guarda autores en un mapa protegido por un RWMutex.
+type MemoryAuthorRepository struct {
+	mu      sync.RWMutex
+	authors map[primitive.ObjectID]models.Author
+}
+
+// NewMemoryAuthorRepository crea un repositorio vacío.
+func NewMemoryAuthorRepository() *MemoryAuthorRepository {
+	return &MemoryAuthorRepository{authors: map[primitive.ObjectID]models.Author{}}
+}
+
+// Create guarda a; asigna un ObjectID nuevo si no trae uno. ErrConflict si el slug
+// pertenece a otro autor.
+func (r *MemoryAuthorRepository) Create(_ context.Context, a models.Author) (primitive.ObjectID, error) {
+	if a.ID.IsZero() {
+		a.ID = primitive.NewObjectID()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.slugOwnerLocked(a.Slug, a.ID) {
+		return primitive.NilObjectID, Wrap(errSlugTaken, ErrConflict, "insert author")
+	}
+	r.authors[a.ID] = cloneAuthor(a)
+	return a.ID, nil
+}
+
+// Get recupera un autor por id; ErrNotFound si no existe.
+func (r *MemoryAuthorRepository) Get(_ context.Context, id primitive.ObjectID) (models.Author, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	a, ok := r.authors[id]
+	if !ok {
+		return models.Author{}, Wrap(errNoAuthor, ErrNotFound, "author not found")
+	}
+	return cloneAuthor(a), nil
+}
+
+// GetBySlug recupera un autor por slug; ErrNotFound si no existe.
+func (r *MemoryAuthorRepository) GetBySlug(_ context.Context, slug string) (models.Author, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, a := range r.authors {
+		if a.Slug == slug {
+			return cloneAuthor(a), nil
+		}
+	}
+	return models.Author{}, Wrap(errNoAuthor, ErrNotFound, "author not found")
+}
+
+// List retorna copias de todos los autores ordenados por nombre (a igual nombre, por id).
+func (r *MemoryAuthorRepository) List(_ context.Context) ([]models.Author, error) {
+	out := r.snapshot()
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// Update aplica los campos editables de a bajo el lock.
+func (r *MemoryAuthorRepository) Update(_ context.Context, id primitive.ObjectID, a models.Author) (models.Author, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cur, ok := r.authors[id]
+	if !ok {
+		return models.Author{}, Wrap(errNoAuthor, ErrNotFound, "author not found")
+	}
+	if r.slugOwnerLocked(a.Slug, id) {
+		return models.Author{}, Wrap(errSlugTaken, ErrConflict, "update author")
+	}
+	cur.Name = a.Name
+	cur.Slug = a.Slug
+	cur.Bio = a.Bio
+	cur.AvatarURL = a.AvatarURL
+	cur.UpdatedAt = a.UpdatedAt
+	r.authors[id] = cloneAuthor(cur)
+	return cloneAuthor(cur), nil
+}
+
+// Delete elimina un autor; ErrNotFound si no existía.
+func (r *MemoryAuthorRepository) Delete(_ context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.authors[id]; !ok {
+		return Wrap(errNoAuthor, ErrNotFound, "author not found")
+	}
+	delete(r.authors, id)
+	return nil
+}
+
+// slugOwnerLocked indica si otro autor (distinto de id) tiene slug. Requiere el lock tomado.
+func (r *MemoryAuthorRepository) slugOwnerLocked(slug string, id primitive.ObjectID) bool {
+	for otherID, a := range r.authors {
+		if otherID != id && a.Slug == slug {
+			return true
+		}
+	}
+	return false
+}
+
+// snapshot retorna una copia de todos los autores ordenados por id.
+func (r *MemoryAuthorRepository) snapshot() []models.Author {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]models.Author, 0, len(r.authors))
+	for _, a := range r.authors {
+		out = append(out, cloneAuthor(a))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID.Hex() < out[j].ID.Hex() })
+	return out
+}
+
+// restore reemplaza el contenido del repositorio por items.
+func (r *MemoryAuthorRepository) restore(items []models.Author) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.authors = make(map[primitive.ObjectID]models.Author, len(items))
+	for _, a := range items {
+		if a.ID.IsZero() {
+			a.ID = primitive.NewObjectID()
+		}
+		r.authors[a.ID] = cloneAuthor(a)
+	}
+}
+
+// cloneAuthor copia a sin compartir el puntero de UpdatedAt.
+func cloneAuthor(a models.Author) models.Author {
+	if a.UpdatedAt != nil {
+		t := *a.UpdatedAt
+		a.UpdatedAt = &t
+	}
+	return a
+}
@@ -0,0 +1,197 @@
+// services/memoryCommentRepository.go
+//
+// Paquete services: implementación en memoria de CommentRepository.
+//
+// Convenciones:
+//   - Misma semántica que MongoCommentRepository; segura para uso concurrente.
+//   - List recorre todos los comentarios: pensado para desarrollo y volúmenes chicos.
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// errNoComment es la causa usada cuando el comentario no existe en memoria.
+var errNoComment = errors.New("no comment with that id")
+
+// MemoryCommentRepository guarda comentarios en un mapa protegido por un RWMutex.
+type MemoryCommentRepository struct {
+	mu       sync.RWMutex
+	comments map[primitive.ObjectID]models.Comment
+}
+
+// NewMemoryCommentRepository crea un repositorio vacío.
+func NewMemoryCommentRepository() *MemoryCommentRepository {
+	return &MemoryCommentRepository{comments: map[primitive.ObjectID]models.Comment{}}
+}
+
+// Create guarda c; asigna un ObjectID nuevo si no trae uno.
+func (r *MemoryCommentRepository) Create(_ context.Context, c models.Comment) (primitive.ObjectID, error) {
+	if c.ID.IsZero() {
+		c.ID = primitive.NewObjectID()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.comments[c.ID] = cloneComment(c)
+	return c.ID, nil
+}
+
+// Get recupera un comentario por id; ErrNotFound si no existe.
+func (r *MemoryCommentRepository) Get(_ context.Context, id primitive.ObjectID) (models.Comment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c, ok := r.comments[id]
+	if !ok {
+		return models.Comment{}, Wrap(errNoComment, ErrNotFound, "comment not found")
+	}
+	return cloneComment(c), nil
+}
+
+// List filtra todos los comentarios con q y los ordena por id.
+func (r *MemoryCommentRepository) List(_ context.Context, q CommentQuery) ([]models.Comment, error) {
+	r.mu.RLock()
+	out := []models.Comment{}
+	for _, c := range r.comments {
+		if matchesCommentQuery(c, q) {
+			out = append(out, cloneComment(c))
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(out, func(i, j int) bool { return out[i].ID.Hex() < out[j].ID.Hex() })
+	if q.Limit > 0 && len(out) > q.Limit {
+		out = out[:q.Limit]
+	}
+	return out, nil
+}
+
+// SetStatus modera los comentarios de ids bajo el lock.
+func (r *MemoryCommentRepository) SetStatus(_ context.Context, ids []primitive.ObjectID, status, moderatedBy string, moderatedAt time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var n int64
+	for _, id := range ids {
+		c, ok := r.comments[id]
+		if !ok || c.Status == status {
+			continue
+		}
+		at := moderatedAt
+		c.Status = status
+		c.ModeratedAt = &at
+		c.ModeratedBy = moderatedBy
+		r.comments[id] = c
+		n++
+	}
+	return n, nil
+}
+
+// CountByPosts cuenta bajo el lock los comentarios con status de postIDs.
+func (r *MemoryCommentRepository) CountByPosts(_ context.Context, postIDs []primitive.ObjectID, status string) (map[primitive.ObjectID]int64, error) {
+	wanted := make(map[primitive.ObjectID]bool, len(postIDs))
+	for _, id := range postIDs {
+		wanted[id] = true
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := map[primitive.ObjectID]int64{}
+	for _, c := range r.comments {
+		if wanted[c.PostID] && c.Status == status {
+			out[c.PostID]++
+		}
+	}
+	return out, nil
+}
+
+// DeleteByPosts descarta los comentarios de postIDs.
+func (r *MemoryCommentRepository) DeleteByPosts(_ context.Context, postIDs []primitive.ObjectID) (int64, error) {
+	wanted := make(map[primitive.ObjectID]bool, len(postIDs))
+	for _, id := range postIDs {
+		wanted[id] = true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var n int64
+	for id, c := range r.comments {
+		if wanted[c.PostID] {
+			delete(r.comments, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// snapshot retorna una copia de todos los comentarios ordenados por id.
+func (r *MemoryCommentRepository) snapshot() []models.Comment {
+	out, _ := r.List(context.Background(), CommentQuery{})
+	return out
+}
+
+// restore reemplaza el contenido del repositorio por items.
+func (r *MemoryCommentRepository) restore(items []models.Comment) {
+	r.mu.Lock()
+	r.comments = make(map[primitive.ObjectID]models.Comment, len(items))
+	r.mu.Unlock()
+	for _, c := range items {
+		_, _ = r.Create(context.Background(), c)
+	}
+}
+
+// matchesCommentQuery indica si c cumple los filtros de q (sin Limit).
+func matchesCommentQuery(c models.Comment, q CommentQuery) bool {
+	switch {
+	case !q.PostID.IsZero() && c.PostID != q.PostID:
+		return false
+	case q.Roots && c.RootID != nil:
+		return false
+	case q.RootIDs != nil && (c.RootID == nil || !containsObjectID(q.RootIDs, *c.RootID)):
+		return false
+	case q.Status != "" && c.Status != q.Status:
+		return false
+	case !q.After.IsZero() && c.ID.Hex() <= q.After.Hex():
+		return false
+	}
+	return true
+}
+
+// containsObjectID indica si id está en ids.
+func containsObjectID(ids []primitive.ObjectID, id primitive.ObjectID) bool {
+	for _, x := range ids {
+		if x == id {
+			return true
+		}
+	}
+	return false
+}
+
+// cloneComment copia c sin compartir punteros ni las respuestas armadas al listar.
+func cloneComment(c models.Comment) models.Comment {
+	if c.ParentID != nil {
+		id := *c.ParentID
+		c.ParentID = &id
+	}
+	if c.RootID != nil {
+		id := *c.RootID
+		c.RootID = &id
+	}
+	if c.ModeratedAt != nil {
+		t := *c.ModeratedAt
+		c.ModeratedAt = &t
+	}
+	c.Replies = nil
+	return c
+}
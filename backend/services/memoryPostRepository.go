@@ -0,0 +1,520 @@
+// services/memoryPostRepository.go
+//
+// Paquete services: implementación en memoria de PostRepository.
+//
+// Convenciones:
+//   - Pensada para tests unitarios y desarrollo sin MongoDB.
+//   - Reproduce la semántica de MongoPostRepository (filtros, orden, paginación, agregación).
+//   - Es segura para uso concurrente (sync.RWMutex) y siempre entrega copias de los posts.
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	// errNoPost es la causa usada cuando el id no existe en memoria.
+	errNoPost = errors.New("no post with that id")
+	// errDuplicateID es la causa usada al insertar un id ya existente.
+	errDuplicateID = errors.New("duplicate id")
+)
+
+// MemoryPostRepository guarda los posts en un mapa indexado por ObjectID.
+type MemoryPostRepository struct {
+	mu    sync.RWMutex
+	posts map[primitive.ObjectID]models.Post
+}
+
+// NewMemoryPostRepository crea un repositorio vacío.
+func NewMemoryPostRepository() *MemoryPostRepository {
+	return &MemoryPostRepository{posts: map[primitive.ObjectID]models.Post{}}
+}
+
+// Create guarda p asignando un ObjectID nuevo si no trae uno.
+func (r *MemoryPostRepository) Create(_ context.Context, p models.Post) (primitive.ObjectID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if p.ID.IsZero() {
+		p.ID = primitive.NewObjectID()
+	}
+	if _, exists := r.posts[p.ID]; exists {
+		return primitive.NilObjectID, Wrap(errDuplicateID, ErrConflict, "insert post")
+	}
+	if r.slugOwnerLocked(p.Slug, p.ID) {
+		return primitive.NilObjectID, Wrap(errSlugTaken, ErrConflict, "insert post")
+	}
+	r.posts[p.ID] = clonePost(p)
+	return p.ID, nil
+}
+
+// Get recupera un post por id; ErrNotFound si no existe.
+func (r *MemoryPostRepository) Get(_ context.Context, id primitive.ObjectID) (models.Post, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.posts[id]
+	if !ok {
+		return models.Post{}, Wrap(errNoPost, ErrNotFound, "post not found")
+	}
+	return clonePost(p), nil
+}
+
+// GetBySlug busca primero por slug vigente y luego en oldSlugs; ErrNotFound si no hay.
+func (r *MemoryPostRepository) GetBySlug(_ context.Context, slug string) (models.Post, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var old *models.Post
+	for _, p := range r.posts {
+		if p.Slug == slug {
+			return clonePost(p), nil
+		}
+		if old == nil && containsString(p.OldSlugs, slug) {
+			p := p
+			old = &p
+		}
+	}
+	if old != nil {
+		return clonePost(*old), nil
+	}
+	return models.Post{}, Wrap(errNoPost, ErrNotFound, "post not found")
+}
+
+// slugOwnerLocked indica si otro post (distinto de id) tiene slug como slug vigente,
+// como haría el índice único de Mongo. Requiere el lock tomado.
+func (r *MemoryPostRepository) slugOwnerLocked(slug string, id primitive.ObjectID) bool {
+	if slug == "" {
+		return false
+	}
+	for otherID, p := range r.posts {
+		if otherID != id && p.Slug == slug {
+			return true
+		}
+	}
+	return false
+}
+
+// Update aplica los campos editables de p; publishedAt y status sólo si vienen definidos.
+// La comparación de versión y la escritura ocurren bajo el mismo lock.
+func (r *MemoryPostRepository) Update(_ context.Context, id primitive.ObjectID, p models.Post, expectedVersion int64) (models.Post, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cur, ok := r.posts[id]
+	if !ok {
+		return models.Post{}, Wrap(errNoPost, ErrNotFound, "post not found after update")
+	}
+	if expectedVersion > 0 && cur.Version != expectedVersion {
+		return models.Post{}, Wrap(errVersionMismatch, ErrVersionMismatch, fmt.Sprintf("expected version %d", expectedVersion))
+	}
+	if r.slugOwnerLocked(p.Slug, id) {
+		return models.Post{}, Wrap(errSlugTaken, ErrConflict, "update post")
+	}
+	cur.Title = p.Title
+	cur.Slug = p.Slug
+	cur.OldSlugs = p.OldSlugs
+	cur.Author = p.Author
+	cur.AuthorID = p.AuthorID
+	cur.Content = p.Content
+	cur.Tags = p.Tags
+	cur.Published = p.Published
+	if p.Status != "" {
+		cur.Status = p.Status
+	}
+	cur.PublishAt = p.PublishAt
+	cur.UnpublishAt = p.UnpublishAt
+	cur.UpdatedAt = p.UpdatedAt
+	if p.PublishedAt != nil {
+		cur.PublishedAt = p.PublishedAt
+	}
+	cur.Version++
+	r.posts[id] = clonePost(cur)
+	return clonePost(cur), nil
+}
+
+// UpdateTags aplica change sobre las etiquetas bajo el lock (ver TagChange.apply).
+func (r *MemoryPostRepository) UpdateTags(_ context.Context, id primitive.ObjectID, change TagChange, updatedAt time.Time, expectedVersion int64) (models.Post, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cur, ok := r.posts[id]
+	if !ok {
+		return models.Post{}, Wrap(errNoPost, ErrNotFound, "post not found after update")
+	}
+	if expectedVersion > 0 && cur.Version != expectedVersion {
+		return models.Post{}, Wrap(errVersionMismatch, ErrVersionMismatch, fmt.Sprintf("expected version %d", expectedVersion))
+	}
+	cur.Tags = change.apply(cur.Tags)
+	cur.UpdatedAt = &updatedAt
+	cur.Version++
+	r.posts[id] = clonePost(cur)
+	return clonePost(cur), nil
+}
+
+// SetDeleted fija o limpia deletedAt/deletedBy bajo el lock.
+func (r *MemoryPostRepository) SetDeleted(_ context.Context, id primitive.ObjectID, deletedAt *time.Time, deletedBy string, expectedVersion int64) (models.Post, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cur, ok := r.posts[id]
+	if !ok {
+		return models.Post{}, Wrap(errNoPost, ErrNotFound, "post not found after update")
+	}
+	if expectedVersion > 0 && cur.Version != expectedVersion {
+		return models.Post{}, Wrap(errVersionMismatch, ErrVersionMismatch, fmt.Sprintf("expected version %d", expectedVersion))
+	}
+	cur.DeletedAt = deletedAt
+	cur.DeletedBy = deletedBy
+	if deletedAt == nil {
+		cur.DeletedBy = ""
+	}
+	cur.Version++
+	r.posts[id] = clonePost(cur)
+	return clonePost(cur), nil
+}
+
+// PurgeDeleted borra los posts con deletedAt anterior a before.
+func (r *MemoryPostRepository) PurgeDeleted(_ context.Context, before time.Time) ([]primitive.ObjectID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var ids []primitive.ObjectID
+	for id, post := range r.posts {
+		if post.DeletedAt != nil && post.DeletedAt.Before(before) {
+			delete(r.posts, id)
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// Delete elimina un post por id; ErrNotFound si no existía, ErrConflict si la versión cambió.
+func (r *MemoryPostRepository) Delete(_ context.Context, id primitive.ObjectID, expectedVersion int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cur, ok := r.posts[id]
+	if !ok {
+		return Wrap(errNoPost, ErrNotFound, "post not found")
+	}
+	if expectedVersion > 0 && cur.Version != expectedVersion {
+		return Wrap(errVersionMismatch, ErrVersionMismatch, fmt.Sprintf("expected version %d", expectedVersion))
+	}
+	delete(r.posts, id)
+	return nil
+}
+
+// List filtra, ordena por publishedAt (deletedAt descendente para la papelera) y
+// pagina igual que la versión Mongo.
+//
+// Notas:
+//   - Q se evalúa con textQuery (ver textSearch.go), que emula $text sobre title+content.
+//   - Los posts sin publishedAt van primero en orden ascendente y al final en descendente.
+func (r *MemoryPostRepository) List(_ context.Context, p ListPostsParams) ([]models.Post, int64, error) {
+	tq := parseTextQuery(p.Q)
+
+	r.mu.RLock()
+	matched := make([]models.Post, 0, len(r.posts))
+	for _, post := range r.posts {
+		if matchesListParams(post, p, tq) {
+			matched = append(matched, post)
+		}
+	}
+	r.mu.RUnlock()
+
+	ascending := p.SortField == "publishedAt"
+	sort.Slice(matched, func(i, j int) bool {
+		a, b := matched[i], matched[j]
+		if p.Deleted {
+			if !a.DeletedAt.Equal(*b.DeletedAt) {
+				return a.DeletedAt.After(*b.DeletedAt)
+			}
+			return a.ID.Hex() < b.ID.Hex()
+		}
+		if p.Scheduled {
+			if at, bt := nextScheduledAt(a), nextScheduledAt(b); !at.Equal(*bt) {
+				return at.Before(*bt)
+			}
+			return a.ID.Hex() < b.ID.Hex()
+		}
+		if !samePublishedAt(a.PublishedAt, b.PublishedAt) {
+			if ascending {
+				return publishedBefore(a.PublishedAt, b.PublishedAt)
+			}
+			return publishedBefore(b.PublishedAt, a.PublishedAt)
+		}
+		return a.ID.Hex() < b.ID.Hex()
+	})
+
+	total := int64(len(matched))
+	start := (p.Page - 1) * p.Limit
+	if start > len(matched) {
+		start = len(matched)
+	}
+	end := start + p.Limit
+	if end > len(matched) {
+		end = len(matched)
+	}
+
+	items := make([]models.Post, 0, end-start)
+	for _, post := range matched[start:end] {
+		items = append(items, clonePost(post))
+	}
+	return items, total, nil
+}
+
+// AggregateByTag cuenta posts por etiqueta (ignorando vacías) y retorna el top-N.
+// Los empates se ordenan alfabéticamente para que el resultado sea estable.
+func (r *MemoryPostRepository) AggregateByTag(_ context.Context, limit int, onlyPublished *bool, status string) ([]TagMetric, error) {
+	counts := map[string]int64{}
+
+	r.mu.RLock()
+	for _, post := range r.posts {
+		if post.DeletedAt != nil || (onlyPublished != nil && post.Published != *onlyPublished) ||
+			(status != "" && post.Status != status) {
+			continue
+		}
+		for _, tag := range post.Tags {
+			if tag != "" {
+				counts[tag]++
+			}
+		}
+	}
+	r.mu.RUnlock()
+
+	out := make([]TagMetric, 0, len(counts))
+	for tag, n := range counts {
+		out = append(out, TagMetric{Tag: tag, Count: n})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Tag < out[j].Tag
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// CountByAuthor cuenta los posts de authorID, incluidos los de la papelera.
+func (r *MemoryPostRepository) CountByAuthor(_ context.Context, authorID primitive.ObjectID) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var n int64
+	for _, post := range r.posts {
+		if post.AuthorID == authorID {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// SetAuthorName copia name en los posts de authorID que tienen otro nombre.
+func (r *MemoryPostRepository) SetAuthorName(_ context.Context, authorID primitive.ObjectID, name string, updatedAt time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var n int64
+	for id, post := range r.posts {
+		if post.AuthorID != authorID || post.Author == name {
+			continue
+		}
+		t := updatedAt
+		post.Author = name
+		post.UpdatedAt = &t
+		post.Version++
+		r.posts[id] = post
+		n++
+	}
+	return n, nil
+}
+
+// ReplaceTags reemplaza from por to en los posts que tienen alguna de esas etiquetas.
+func (r *MemoryPostRepository) ReplaceTags(_ context.Context, from []string, to string, updatedAt time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var n int64
+	for id, post := range r.posts {
+		tags, changed := replaceTags(post.Tags, from, to)
+		if !changed {
+			continue
+		}
+		t := updatedAt
+		post.Tags = tags
+		post.UpdatedAt = &t
+		post.Version++
+		r.posts[id] = post
+		n++
+	}
+	return n, nil
+}
+
+// CountTags recorre los posts fuera de la papelera y cuenta las etiquetas de tags.
+func (r *MemoryPostRepository) CountTags(_ context.Context, tags []string) ([]models.TagCount, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var set tagCountSet
+	for _, post := range r.posts {
+		if post.DeletedAt != nil {
+			continue
+		}
+		for _, tag := range post.Tags {
+			if tags == nil || containsString(tags, tag) {
+				set.add(tag, postStatus(post), 1)
+			}
+		}
+	}
+	return set.counts(), nil
+}
+
+// snapshot retorna una copia de todos los posts, ordenados por id para que el
+// archivo generado sea estable entre ejecuciones.
+func (r *MemoryPostRepository) snapshot() []models.Post {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]models.Post, 0, len(r.posts))
+	for _, post := range r.posts {
+		out = append(out, clonePost(post))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID.Hex() < out[j].ID.Hex() })
+	return out
+}
+
+// restore reemplaza el contenido del repositorio por posts.
+func (r *MemoryPostRepository) restore(posts []models.Post) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.posts = make(map[primitive.ObjectID]models.Post, len(posts))
+	for _, post := range posts {
+		if post.ID.IsZero() {
+			post.ID = primitive.NewObjectID()
+		}
+		if post.Version == 0 {
+			// Snapshots anteriores al control de versiones.
+			post.Version = 1
+		}
+		if post.Status == "" {
+			// Snapshots anteriores al flujo editorial.
+			post.Status = statusFromPublished(post.Published)
+		}
+		r.posts[post.ID] = clonePost(post)
+	}
+
+	// Snapshots anteriores a los slugs: se generan del más viejo al más nuevo.
+	items := make([]models.Post, 0, len(r.posts))
+	for _, post := range r.posts {
+		items = append(items, post)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if !items[i].CreatedAt.Equal(items[j].CreatedAt) {
+			return items[i].CreatedAt.Before(items[j].CreatedAt)
+		}
+		return items[i].ID.Hex() < items[j].ID.Hex()
+	})
+	for _, i := range backfillSlugs(items, takenSlugs(items)) {
+		r.posts[items[i].ID] = items[i]
+	}
+}
+
+// matchesListParams evalúa los filtros Deleted, Scheduled, Q (ya parseado en tq), Tag,
+// Published, Status y AuthorID sobre un post.
+func matchesListParams(post models.Post, p ListPostsParams, tq textQuery) bool {
+	if (post.DeletedAt != nil) != p.Deleted {
+		return false
+	}
+	if p.Scheduled && nextScheduledAt(post) == nil {
+		return false
+	}
+	if p.Published != nil && post.Published != *p.Published {
+		return false
+	}
+	if p.Status != "" && post.Status != p.Status {
+		return false
+	}
+	if !p.AuthorID.IsZero() && post.AuthorID != p.AuthorID {
+		return false
+	}
+	if p.Tag != "" && !containsString(post.Tags, p.Tag) {
+		return false
+	}
+	if p.Q != "" && !tq.matches(post.Title+" "+post.Content) {
+		return false
+	}
+	return true
+}
+
+// samePublishedAt compara dos PublishedAt considerando nil.
+func samePublishedAt(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
+
+// publishedBefore ordena nil antes que cualquier fecha, como lo hace Mongo.
+func publishedBefore(a, b *time.Time) bool {
+	if a == nil {
+		return b != nil
+	}
+	if b == nil {
+		return false
+	}
+	return a.Before(*b)
+}
+
+// containsString indica si s contiene exactamente v.
+func containsString(s []string, v string) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// clonePost copia los slices y punteros de p para no compartir estado con el llamador.
+func clonePost(p models.Post) models.Post {
+	if p.Tags != nil {
+		p.Tags = append([]string(nil), p.Tags...)
+	}
+	if p.OldSlugs != nil {
+		p.OldSlugs = append([]string(nil), p.OldSlugs...)
+	}
+	if p.PublishedAt != nil {
+		t := *p.PublishedAt
+		p.PublishedAt = &t
+	}
+	if p.UpdatedAt != nil {
+		t := *p.UpdatedAt
+		p.UpdatedAt = &t
+	}
+	if p.DeletedAt != nil {
+		t := *p.DeletedAt
+		p.DeletedAt = &t
+	}
+	if p.PublishAt != nil {
+		t := *p.PublishAt
+		p.PublishAt = &t
+	}
+	if p.UnpublishAt != nil {
+		t := *p.UnpublishAt
+		p.UnpublishAt = &t
+	}
+	return p
+}
@@ -0,0 +1,207 @@
+// services/memoryReactionRepository.go
+//
+// Paquete services: implementación en memoria de ReactionRepository.
+//
+// Convenciones:
+//   - Misma semántica que MongoReactionRepository; segura para uso concurrente.
+//   - Las marcas y los contadores se actualizan bajo el mismo lock, así que nunca se
+//     desfasan; el snapshot guarda sólo las marcas y los contadores se recalculan al
+//     restaurarlo.
+package services
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryReactionKey identifica la reacción de un cliente.
+type memoryReactionKey struct {
+	postID   primitive.ObjectID
+	kind     string
+	clientID string
+}
+
+// MemoryReactionRepository guarda reacciones y contadores en mapas protegidos por un
+// RWMutex.
+type MemoryReactionRepository struct {
+	mu        sync.RWMutex
+	reactions map[memoryReactionKey]time.Time
+	counts    map[primitive.ObjectID]map[string]int64
+}
+
+// NewMemoryReactionRepository crea un repositorio vacío.
+func NewMemoryReactionRepository() *MemoryReactionRepository {
+	return &MemoryReactionRepository{
+		reactions: map[memoryReactionKey]time.Time{},
+		counts:    map[primitive.ObjectID]map[string]int64{},
+	}
+}
+
+// Add registra la reacción y suma el contador bajo el lock.
+func (r *MemoryReactionRepository) Add(_ context.Context, postID primitive.ObjectID, kind, clientID string, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.add(memoryReactionKey{postID: postID, kind: kind, clientID: clientID}, at), nil
+}
+
+// Remove borra la reacción y resta el contador bajo el lock.
+func (r *MemoryReactionRepository) Remove(_ context.Context, postID primitive.ObjectID, kind, clientID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := memoryReactionKey{postID: postID, kind: kind, clientID: clientID}
+	if _, ok := r.reactions[key]; !ok {
+		return false, nil
+	}
+	delete(r.reactions, key)
+	counts := r.counts[postID]
+	counts[kind]--
+	if counts[kind] <= 0 {
+		delete(counts, kind)
+	}
+	if len(counts) == 0 {
+		delete(r.counts, postID)
+	}
+	return true, nil
+}
+
+// Counts copia bajo el lock los contadores de postIDs.
+func (r *MemoryReactionRepository) Counts(_ context.Context, postIDs []primitive.ObjectID) (map[primitive.ObjectID]map[string]int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := map[primitive.ObjectID]map[string]int64{}
+	for _, id := range postIDs {
+		if counts, ok := r.counts[id]; ok {
+			out[id] = cloneReactionCounts(counts)
+		}
+	}
+	return out, nil
+}
+
+// Top ordena todos los contadores con reacciones de kind y retorna la ventana pedida.
+func (r *MemoryReactionRepository) Top(_ context.Context, kind string, skip, limit int) ([]models.ReactionCount, error) {
+	r.mu.RLock()
+	out := []models.ReactionCount{}
+	for id, counts := range r.counts {
+		rc := models.ReactionCount{PostID: id, Counts: cloneReactionCounts(counts)}
+		for _, n := range counts {
+			rc.Total += n
+		}
+		if reactionScore(rc, kind) > 0 {
+			out = append(out, rc)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(out, func(i, j int) bool {
+		si, sj := reactionScore(out[i], kind), reactionScore(out[j], kind)
+		if si != sj {
+			return si > sj
+		}
+		return out[i].PostID.Hex() < out[j].PostID.Hex()
+	})
+	if skip >= len(out) {
+		return []models.ReactionCount{}, nil
+	}
+	out = out[skip:]
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// DeleteByPosts descarta las reacciones y los contadores de postIDs.
+func (r *MemoryReactionRepository) DeleteByPosts(_ context.Context, postIDs []primitive.ObjectID) (int64, error) {
+	wanted := make(map[primitive.ObjectID]bool, len(postIDs))
+	for _, id := range postIDs {
+		wanted[id] = true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var n int64
+	for key := range r.reactions {
+		if wanted[key.postID] {
+			delete(r.reactions, key)
+			n++
+		}
+	}
+	for id := range wanted {
+		delete(r.counts, id)
+	}
+	return n, nil
+}
+
+// snapshot retorna una copia de todas las reacciones ordenadas por post, tipo y cliente.
+func (r *MemoryReactionRepository) snapshot() []models.Reaction {
+	r.mu.RLock()
+	out := make([]models.Reaction, 0, len(r.reactions))
+	for key, at := range r.reactions {
+		out = append(out, models.Reaction{PostID: key.postID, Kind: key.kind, ClientID: key.clientID, CreatedAt: at})
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].PostID != out[j].PostID {
+			return out[i].PostID.Hex() < out[j].PostID.Hex()
+		}
+		if out[i].Kind != out[j].Kind {
+			return out[i].Kind < out[j].Kind
+		}
+		return out[i].ClientID < out[j].ClientID
+	})
+	return out
+}
+
+// restore reemplaza el contenido del repositorio por items y recalcula los contadores.
+func (r *MemoryReactionRepository) restore(items []models.Reaction) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.reactions = make(map[memoryReactionKey]time.Time, len(items))
+	r.counts = map[primitive.ObjectID]map[string]int64{}
+	for _, it := range items {
+		r.add(memoryReactionKey{postID: it.PostID, kind: it.Kind, clientID: it.ClientID}, it.CreatedAt)
+	}
+}
+
+// add registra key y suma su contador si no existía; requiere el lock de escritura.
+func (r *MemoryReactionRepository) add(key memoryReactionKey, at time.Time) bool {
+	if _, ok := r.reactions[key]; ok {
+		return false
+	}
+	r.reactions[key] = at
+	counts, ok := r.counts[key.postID]
+	if !ok {
+		counts = map[string]int64{}
+		r.counts[key.postID] = counts
+	}
+	counts[key.kind]++
+	return true
+}
+
+// reactionScore es el valor por el que Top ordena rc: sus reacciones de kind, o Total
+// si kind es "".
+func reactionScore(rc models.ReactionCount, kind string) int64 {
+	if kind == "" {
+		return rc.Total
+	}
+	return rc.Counts[kind]
+}
+
+// cloneReactionCounts copia un mapa de contadores por tipo.
+func cloneReactionCounts(counts map[string]int64) map[string]int64 {
+	out := make(map[string]int64, len(counts))
+	for kind, n := range counts {
+		out[kind] = n
+	}
+	return out
+}
@@ -0,0 +1,158 @@
+// services/memoryRevisionRepository.go
+//
+// Paquete services: implementación en memoria de RevisionRepository.
+//
+// Convenciones:
+//   - Misma semántica que MongoRevisionRepository; segura para uso concurrente.
+//   - Las revisiones de cada post se guardan ordenadas por número ascendente.
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// errNoRevision es la causa usada cuando la revisión no existe en memoria.
+var errNoRevision = errors.New("no revision with that number")
+
+// MemoryRevisionRepository guarda las revisiones agrupadas por post.
+type MemoryRevisionRepository struct {
+	mu   sync.RWMutex
+	revs map[primitive.ObjectID][]models.PostRevision
+}
+
+// NewMemoryRevisionRepository crea un repositorio vacío.
+func NewMemoryRevisionRepository() *MemoryRevisionRepository {
+	return &MemoryRevisionRepository{revs: map[primitive.ObjectID][]models.PostRevision{}}
+}
+
+// Create agrega rev manteniendo el orden; ignora una revisión ya existente.
+func (r *MemoryRevisionRepository) Create(_ context.Context, rev models.PostRevision) error {
+	if rev.ID.IsZero() {
+		rev.ID = primitive.NewObjectID()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	list := r.revs[rev.PostID]
+	i := sort.Search(len(list), func(i int) bool { return list[i].Revision >= rev.Revision })
+	if i < len(list) && list[i].Revision == rev.Revision {
+		return nil
+	}
+	list = append(list, models.PostRevision{})
+	copy(list[i+1:], list[i:])
+	list[i] = cloneRevision(rev)
+	r.revs[rev.PostID] = list
+	return nil
+}
+
+// List retorna copias de las revisiones de postID, de la más nueva a la más vieja.
+func (r *MemoryRevisionRepository) List(_ context.Context, postID primitive.ObjectID) ([]models.PostRevision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := r.revs[postID]
+	out := make([]models.PostRevision, 0, len(list))
+	for i := len(list) - 1; i >= 0; i-- {
+		out = append(out, cloneRevision(list[i]))
+	}
+	return out, nil
+}
+
+// Get retorna una copia de la revisión indicada o ErrNotFound.
+func (r *MemoryRevisionRepository) Get(_ context.Context, postID primitive.ObjectID, revision int64) (models.PostRevision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, rev := range r.revs[postID] {
+		if rev.Revision == revision {
+			return cloneRevision(rev), nil
+		}
+	}
+	return models.PostRevision{}, Wrap(errNoRevision, ErrNotFound, "revision not found")
+}
+
+// Prune descarta las revisiones más viejas que exceden keep o son anteriores a before.
+func (r *MemoryRevisionRepository) Prune(_ context.Context, postID primitive.ObjectID, keep int, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	list := r.revs[postID]
+	kept := make([]models.PostRevision, 0, len(list))
+	for i, rev := range list {
+		tooMany := keep > 0 && len(list)-i > keep
+		tooOld := !before.IsZero() && rev.CreatedAt.Before(before)
+		if !tooMany && !tooOld {
+			kept = append(kept, rev)
+		}
+	}
+	if len(kept) == 0 {
+		delete(r.revs, postID)
+	} else {
+		r.revs[postID] = kept
+	}
+	return int64(len(list) - len(kept)), nil
+}
+
+// DeleteByPosts descarta el historial de postIDs.
+func (r *MemoryRevisionRepository) DeleteByPosts(_ context.Context, postIDs []primitive.ObjectID) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var n int64
+	for _, id := range postIDs {
+		n += int64(len(r.revs[id]))
+		delete(r.revs, id)
+	}
+	return n, nil
+}
+
+// snapshot retorna todas las revisiones ordenadas por post y número.
+func (r *MemoryRevisionRepository) snapshot() []models.PostRevision {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := []models.PostRevision{}
+	for _, list := range r.revs {
+		for _, rev := range list {
+			out = append(out, cloneRevision(rev))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].PostID != out[j].PostID {
+			return out[i].PostID.Hex() < out[j].PostID.Hex()
+		}
+		return out[i].Revision < out[j].Revision
+	})
+	return out
+}
+
+// restore reemplaza el contenido del repositorio por revs.
+func (r *MemoryRevisionRepository) restore(revs []models.PostRevision) {
+	r.mu.Lock()
+	r.revs = map[primitive.ObjectID][]models.PostRevision{}
+	r.mu.Unlock()
+
+	for _, rev := range revs {
+		_ = r.Create(context.Background(), rev)
+	}
+}
+
+// cloneRevision copia los slices y punteros de rev.
+func cloneRevision(rev models.PostRevision) models.PostRevision {
+	if rev.Tags != nil {
+		rev.Tags = append([]string(nil), rev.Tags...)
+	}
+	if rev.PublishedAt != nil {
+		t := *rev.PublishedAt
+		rev.PublishedAt = &t
+	}
+	return rev
+}
@@ -0,0 +1,155 @@
+// services/memorySnapshot.go
+//
+// Paquete services: persistencia del driver "memory" en un archivo JSON.
+//
+// Convenciones:
+//   - El snapshot se carga al abrir el Store y se vuelca al cerrarlo.
+//   - Un archivo inexistente no es error: el driver arranca vacío y lo crea al cerrar.
+//   - La escritura es atómica (archivo temporal + rename) para no dejar snapshots truncados.
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"blog-api/models"
+)
+
+// memorySnapshotVersion es la versión actual del formato del snapshot.
+const memorySnapshotVersion = 1
+
+// memorySnapshot es el contenido serializado del driver "memory".
+// Revisions, Transitions, Authors, Comments y Reactions se agregaron sin cambiar la versión: un
+// snapshot anterior simplemente no los trae (los autores se derivan entonces de los posts).
+type memorySnapshot struct {
+	Version     int                     `json:"version"`
+	SavedAt     time.Time               `json:"savedAt"`
+	Posts       []models.Post           `json:"posts"`
+	Revisions   []models.PostRevision   `json:"revisions,omitempty"`
+	Transitions []models.PostTransition `json:"transitions,omitempty"`
+	Authors     []models.Author         `json:"authors,omitempty"`
+	Comments    []models.Comment        `json:"comments,omitempty"`
+	Reactions   []models.Reaction       `json:"reactions,omitempty"`
+}
+
+// loadMemorySnapshot lee path y restaura su contenido en posts, revisions, transitions,
+// authors, comments y reactions. Los posts sin autor referenciado se asignan con backfillAuthors y sus
+// etiquetas se normalizan con tags, que aplica los alias configurados (ver postTags.go).
+//
+// Errores:
+//   - ErrDB si el archivo existe pero no puede leerse o tiene un formato inválido.
+func loadMemorySnapshot(path string, posts *MemoryPostRepository, revisions *MemoryRevisionRepository, transitions *MemoryTransitionRepository, authors *MemoryAuthorRepository, comments *MemoryCommentRepository, reactions *MemoryReactionRepository, tags tagNormalizer) error {
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return Wrap(err, ErrDB, "read snapshot")
+	}
+
+	var snap memorySnapshot
+	if err := json.Unmarshal(raw, &snap); err != nil {
+		return Wrap(err, ErrDB, "decode snapshot")
+	}
+	if snap.Version != memorySnapshotVersion {
+		return Wrap(fmt.Errorf("version %d", snap.Version), ErrDB, "unsupported snapshot")
+	}
+
+	posts.restore(snap.Posts)
+	revisions.restore(snap.Revisions)
+	transitions.restore(snap.Transitions)
+	authors.restore(snap.Authors)
+	comments.restore(snap.Comments)
+	reactions.restore(snap.Reactions)
+	backfillMemoryAuthors(posts, authors)
+	normalizeMemoryTags(posts, tags)
+	return nil
+}
+
+// normalizeMemoryTags normaliza con normalizer las etiquetas de los posts restaurados.
+func normalizeMemoryTags(posts *MemoryPostRepository, normalizer tagNormalizer) {
+	posts.mu.Lock()
+	defer posts.mu.Unlock()
+
+	var n int
+	for id, post := range posts.posts {
+		tags := normalizer.tags(post.Tags)
+		if equalTags(tags, post.Tags) {
+			continue
+		}
+		post.Tags = tags
+		posts.posts[id] = post
+		n++
+	}
+	if n > 0 {
+		slog.Info("etiquetas normalizadas en posts existentes", slog.Int("posts", n))
+	}
+}
+
+// backfillMemoryAuthors crea los autores de los posts que no referencian uno existente.
+func backfillMemoryAuthors(posts *MemoryPostRepository, authors *MemoryAuthorRepository) {
+	items := posts.snapshot()
+	sort.SliceStable(items, func(i, j int) bool { return items[i].CreatedAt.Before(items[j].CreatedAt) })
+	created, changed := backfillAuthors(authors.snapshot(), items, time.Now().UTC())
+	if len(changed) == 0 {
+		return
+	}
+	authors.mu.Lock()
+	for _, a := range created {
+		authors.authors[a.ID] = a
+	}
+	authors.mu.Unlock()
+	posts.mu.Lock()
+	for _, i := range changed {
+		posts.posts[items[i].ID] = items[i]
+	}
+	posts.mu.Unlock()
+	slog.Info("autores asignados a posts existentes", slog.Int("posts", len(changed)), slog.Int("authors", len(created)))
+}
+
+// saveMemorySnapshot vuelca el contenido de posts, revisions, transitions, authors,
+// comments y reactions en path de forma atómica.
+//
+// Errores:
+//   - ErrDB si no se puede serializar o escribir el archivo.
+func saveMemorySnapshot(path string, posts *MemoryPostRepository, revisions *MemoryRevisionRepository, transitions *MemoryTransitionRepository, authors *MemoryAuthorRepository, comments *MemoryCommentRepository, reactions *MemoryReactionRepository) error {
+	snap := memorySnapshot{
+		Version:     memorySnapshotVersion,
+		SavedAt:     time.Now().UTC(),
+		Posts:       posts.snapshot(),
+		Revisions:   revisions.snapshot(),
+		Transitions: transitions.snapshot(),
+		Authors:     authors.snapshot(),
+		Comments:    comments.snapshot(),
+		Reactions:   reactions.snapshot(),
+	}
+	raw, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return Wrap(err, ErrDB, "encode snapshot")
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return Wrap(err, ErrDB, "create snapshot temp file")
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return Wrap(err, ErrDB, "write snapshot")
+	}
+	if err := tmp.Close(); err != nil {
+		return Wrap(err, ErrDB, "close snapshot")
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return Wrap(err, ErrDB, "rename snapshot")
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+// services/memoryTagCountRepository.go
+//
+// Paquete services: implementación en memoria de TagCountRepository.
+//
+// Convenciones:
+//   - Misma semántica que MongoTagCountRepository; segura para uso concurrente.
+//   - No forma parte del snapshot: se reconstruye de los posts al abrir el Store.
+package services
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"blog-api/models"
+)
+
+// MemoryTagCountRepository guarda contadores en un mapa protegido por un RWMutex.
+type MemoryTagCountRepository struct {
+	mu     sync.RWMutex
+	counts map[string]models.TagCount
+}
+
+// NewMemoryTagCountRepository crea un repositorio vacío.
+func NewMemoryTagCountRepository() *MemoryTagCountRepository {
+	return &MemoryTagCountRepository{counts: map[string]models.TagCount{}}
+}
+
+// List retorna una copia de todos los contadores.
+func (r *MemoryTagCountRepository) List(_ context.Context) ([]models.TagCount, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]models.TagCount, 0, len(r.counts))
+	for _, tc := range r.counts {
+		out = append(out, cloneTagCount(tc))
+	}
+	return out, nil
+}
+
+// Suggest recorre los contadores y retorna los de prefijo prefix, más usados primero.
+func (r *MemoryTagCountRepository) Suggest(_ context.Context, prefix string, limit int) ([]models.TagCount, error) {
+	r.mu.RLock()
+	var out []models.TagCount
+	for _, tc := range r.counts {
+		if strings.HasPrefix(tc.Folded, prefix) {
+			out = append(out, cloneTagCount(tc))
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Total != out[j].Total {
+			return out[i].Total > out[j].Total
+		}
+		return out[i].Tag < out[j].Tag
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// Replace reemplaza los contadores de tags (todos si tags es nil) bajo el lock.
+func (r *MemoryTagCountRepository) Replace(_ context.Context, tags []string, counts []models.TagCount) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if tags == nil {
+		r.counts = make(map[string]models.TagCount, len(counts))
+	}
+	for _, tag := range tags {
+		delete(r.counts, tag)
+	}
+	for _, tc := range counts {
+		r.counts[tc.Tag] = cloneTagCount(tc)
+	}
+	return nil
+}
+
+// cloneTagCount copia tc sin compartir el mapa Statuses.
+func cloneTagCount(tc models.TagCount) models.TagCount {
+	statuses := make(map[string]int64, len(tc.Statuses))
+	for status, n := range tc.Statuses {
+		statuses[status] = n
+	}
+	tc.Statuses = statuses
+	return tc
+}
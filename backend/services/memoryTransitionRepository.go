@@ -0,0 +1,96 @@
+// services/memoryTransitionRepository.go
+//
+// Paquete services: implementación en memoria de TransitionRepository.
+//
+// Convenciones:
+//   - Misma semántica que MongoTransitionRepository; segura para uso concurrente.
+//   - Las transiciones de cada post se guardan en orden de llegada.
+package services
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryTransitionRepository guarda las transiciones agrupadas por post.
+type MemoryTransitionRepository struct {
+	mu    sync.RWMutex
+	items map[primitive.ObjectID][]models.PostTransition
+}
+
+// NewMemoryTransitionRepository crea un repositorio vacío.
+func NewMemoryTransitionRepository() *MemoryTransitionRepository {
+	return &MemoryTransitionRepository{items: map[primitive.ObjectID][]models.PostTransition{}}
+}
+
+// Create agrega t al final del historial de su post.
+func (r *MemoryTransitionRepository) Create(_ context.Context, t models.PostTransition) error {
+	if t.ID.IsZero() {
+		t.ID = primitive.NewObjectID()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.items[t.PostID] = append(r.items[t.PostID], t)
+	return nil
+}
+
+// List retorna las transiciones de postID, de la más nueva a la más vieja.
+func (r *MemoryTransitionRepository) List(_ context.Context, postID primitive.ObjectID) ([]models.PostTransition, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := r.items[postID]
+	out := make([]models.PostTransition, 0, len(list))
+	for i := len(list) - 1; i >= 0; i-- {
+		out = append(out, list[i])
+	}
+	return out, nil
+}
+
+// DeleteByPosts descarta el historial de postIDs.
+func (r *MemoryTransitionRepository) DeleteByPosts(_ context.Context, postIDs []primitive.ObjectID) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var n int64
+	for _, id := range postIDs {
+		n += int64(len(r.items[id]))
+		delete(r.items, id)
+	}
+	return n, nil
+}
+
+// snapshot retorna todas las transiciones ordenadas por post y en orden de llegada.
+func (r *MemoryTransitionRepository) snapshot() []models.PostTransition {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ids := make([]primitive.ObjectID, 0, len(r.items))
+	for id := range r.items {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].Hex() < ids[j].Hex() })
+
+	out := []models.PostTransition{}
+	for _, id := range ids {
+		out = append(out, r.items[id]...)
+	}
+	return out
+}
+
+// restore reemplaza el contenido del repositorio por items (en el orden de snapshot).
+func (r *MemoryTransitionRepository) restore(items []models.PostTransition) {
+	r.mu.Lock()
+	r.items = map[primitive.ObjectID][]models.PostTransition{}
+	r.mu.Unlock()
+
+	for _, t := range items {
+		_ = r.Create(context.Background(), t)
+	}
+}
@@ -0,0 +1,375 @@
+// services/migrations.go
+//
+// Paquete services: lista ordenada de migraciones del esquema Mongo.
+//
+// Convenciones:
+//   - Nunca se modifica ni reordena una migración ya publicada; los cambios
+//     se agregan como una versión nueva al final de MongoMigrations. Lo que depende de
+//     la configuración (los alias de etiquetas) llega como parámetro de MongoMigrations.
+//   - Up debe ser idempotente; Down debe dejar el esquema como estaba antes de Up sin
+//     tocar datos escritos después. Si no puede distinguirlos, Down es un no-op que
+//     documenta por qué el dato es válido para la versión anterior.
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoMigrations retorna el historial completo de migraciones de la base Mongo.
+// tagAliases es la configuración tags.aliases (alias → canónica, la misma de
+// PostServiceOptions.TagAliases) que la migración 10 aplica al normalizar.
+func MongoMigrations(tagAliases map[string]string) []Migration {
+	return []Migration{
+		{
+			Version:     1,
+			Description: "índices text_title_content e idx_published_publishedAt en posts",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				return ensureIndexes(ctx, db.Collection("posts"))
+			},
+			Down: func(ctx context.Context, db *mongo.Database) error {
+				return dropIndexes(ctx, db.Collection("posts"), "text_title_content", "idx_published_publishedAt")
+			},
+		},
+		{
+			Version:     2,
+			Description: "backfill de updatedAt=createdAt en posts que no lo tienen",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				_, err := db.Collection("posts").UpdateMany(ctx,
+					bson.M{"updatedAt": bson.M{"$exists": false}},
+					mongo.Pipeline{{{Key: "$set", Value: bson.M{"updatedAt": "$createdAt"}}}})
+				return err
+			},
+			Down: func(ctx context.Context, db *mongo.Database) error {
+				// Sólo se revierten los documentos que siguen con el valor del backfill.
+				_, err := db.Collection("posts").UpdateMany(ctx,
+					bson.M{"$expr": bson.M{"$eq": bson.A{"$updatedAt", "$createdAt"}}},
+					bson.M{"$unset": bson.M{"updatedAt": ""}})
+				return err
+			},
+		},
+		{
+			Version:     3,
+			Description: "backfill de version=1 en posts sin control de concurrencia",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				_, err := db.Collection("posts").UpdateMany(ctx,
+					bson.M{"version": bson.M{"$exists": false}},
+					bson.M{"$set": bson.M{"version": int64(1)}})
+				return err
+			},
+			Down: func(ctx context.Context, db *mongo.Database) error {
+				// El dato no se revierte: no se distingue el version=1 del backfill del de los
+				// posts creados después, y quitarlo a todos rompería su control de
+				// concurrencia. La versión anterior ignora el campo.
+				return nil
+			},
+		},
+		{
+			Version:     4,
+			Description: "índice idx_deletedAt en posts (papelera y purga)",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				_, err := db.Collection("posts").Indexes().CreateOne(ctx, mongo.IndexModel{
+					Keys:    bson.D{{Key: "deletedAt", Value: 1}},
+					Options: options.Index().SetName("idx_deletedAt").SetSparse(true),
+				})
+				return err
+			},
+			Down: func(ctx context.Context, db *mongo.Database) error {
+				return dropIndexes(ctx, db.Collection("posts"), "idx_deletedAt")
+			},
+		},
+		{
+			Version:     5,
+			Description: "índice único idx_postId_revision en post_revisions (historial)",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				_, err := db.Collection("post_revisions").Indexes().CreateOne(ctx, mongo.IndexModel{
+					Keys:    bson.D{{Key: "postId", Value: 1}, {Key: "revision", Value: -1}},
+					Options: options.Index().SetName("idx_postId_revision").SetUnique(true),
+				})
+				return err
+			},
+			Down: func(ctx context.Context, db *mongo.Database) error {
+				return dropIndexes(ctx, db.Collection("post_revisions"), "idx_postId_revision")
+			},
+		},
+		{
+			Version:     6,
+			Description: "slug en posts existentes e índices idx_slug (único) e idx_oldSlugs",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				col := db.Collection("posts")
+				opts := options.Find().
+					SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}).
+					SetProjection(bson.M{"title": 1, "slug": 1, "oldSlugs": 1})
+				cur, err := col.Find(ctx, bson.M{}, opts)
+				if err != nil {
+					return err
+				}
+				var items []models.Post
+				if err := cur.All(ctx, &items); err != nil {
+					return err
+				}
+				for _, i := range backfillSlugs(items, takenSlugs(items)) {
+					if _, err := col.UpdateOne(ctx, bson.M{"_id": items[i].ID},
+						bson.M{"$set": bson.M{"slug": items[i].Slug}}); err != nil {
+						return err
+					}
+				}
+				_, err = col.Indexes().CreateMany(ctx, []mongo.IndexModel{
+					{
+						Keys: bson.D{{Key: "slug", Value: 1}},
+						Options: options.Index().SetName("idx_slug").SetUnique(true).
+							SetPartialFilterExpression(bson.M{"slug": bson.M{"$type": "string"}}),
+					},
+					{
+						Keys:    bson.D{{Key: "oldSlugs", Value: 1}},
+						Options: options.Index().SetName("idx_oldSlugs"),
+					},
+				})
+				return err
+			},
+			Down: func(ctx context.Context, db *mongo.Database) error {
+				// Los slugs generados se conservan: son datos válidos sin los índices.
+				return dropIndexes(ctx, db.Collection("posts"), "idx_slug", "idx_oldSlugs")
+			},
+		},
+		{
+			Version:     7,
+			Description: "índices idx_publishAt e idx_unpublishAt en posts (publicación programada)",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				_, err := db.Collection("posts").Indexes().CreateMany(ctx, []mongo.IndexModel{
+					{
+						Keys:    bson.D{{Key: "publishAt", Value: 1}},
+						Options: options.Index().SetName("idx_publishAt").SetSparse(true),
+					},
+					{
+						Keys:    bson.D{{Key: "unpublishAt", Value: 1}},
+						Options: options.Index().SetName("idx_unpublishAt").SetSparse(true),
+					},
+				})
+				return err
+			},
+			Down: func(ctx context.Context, db *mongo.Database) error {
+				return dropIndexes(ctx, db.Collection("posts"), "idx_publishAt", "idx_unpublishAt")
+			},
+		},
+		{
+			Version:     8,
+			Description: "status derivado de published, índice idx_status_publishedAt e idx_postId_createdAt en post_transitions",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				col := db.Collection("posts")
+				_, err := col.UpdateMany(ctx,
+					bson.M{"status": bson.M{"$exists": false}},
+					mongo.Pipeline{{{Key: "$set", Value: bson.M{"status": bson.M{
+						"$cond": bson.A{"$published", models.StatusPublished, models.StatusDraft},
+					}}}}})
+				if err != nil {
+					return err
+				}
+				_, err = col.Indexes().CreateOne(ctx, mongo.IndexModel{
+					Keys:    bson.D{{Key: "status", Value: 1}, {Key: "publishedAt", Value: -1}},
+					Options: options.Index().SetName("idx_status_publishedAt"),
+				})
+				if err != nil {
+					return err
+				}
+				_, err = db.Collection("post_transitions").Indexes().CreateOne(ctx, mongo.IndexModel{
+					Keys:    bson.D{{Key: "postId", Value: 1}, {Key: "createdAt", Value: -1}},
+					Options: options.Index().SetName("idx_postId_createdAt"),
+				})
+				return err
+			},
+			Down: func(ctx context.Context, db *mongo.Database) error {
+				// status se conserva: sin el índice sigue siendo un dato válido.
+				if err := dropIndexes(ctx, db.Collection("posts"), "idx_status_publishedAt"); err != nil {
+					return err
+				}
+				return dropIndexes(ctx, db.Collection("post_transitions"), "idx_postId_createdAt")
+			},
+		},
+		{
+			Version:     9,
+			Description: "colección authors (índice único idx_slug) derivada de posts.author e índice idx_authorId en posts",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				authorsCol, postsCol := db.Collection("authors"), db.Collection("posts")
+				_, err := authorsCol.Indexes().CreateOne(ctx, mongo.IndexModel{
+					Keys:    bson.D{{Key: "slug", Value: 1}},
+					Options: options.Index().SetName("idx_slug").SetUnique(true),
+				})
+				if err != nil {
+					return err
+				}
+
+				cur, err := authorsCol.Find(ctx, bson.M{})
+				if err != nil {
+					return err
+				}
+				var existing []models.Author
+				if err := cur.All(ctx, &existing); err != nil {
+					return err
+				}
+				opts := options.Find().
+					SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}).
+					SetProjection(bson.M{"author": 1, "authorId": 1})
+				if cur, err = postsCol.Find(ctx, bson.M{}, opts); err != nil {
+					return err
+				}
+				var items []models.Post
+				if err := cur.All(ctx, &items); err != nil {
+					return err
+				}
+
+				created, changed := backfillAuthors(existing, items, time.Now().UTC())
+				for _, a := range created {
+					if _, err := authorsCol.InsertOne(ctx, a); err != nil {
+						return err
+					}
+				}
+				for _, i := range changed {
+					if _, err := postsCol.UpdateOne(ctx, bson.M{"_id": items[i].ID}, bson.M{"$set": bson.M{
+						"authorId": items[i].AuthorID,
+						"author":   items[i].Author,
+					}}); err != nil {
+						return err
+					}
+				}
+				_, err = postsCol.Indexes().CreateOne(ctx, mongo.IndexModel{
+					Keys:    bson.D{{Key: "authorId", Value: 1}},
+					Options: options.Index().SetName("idx_authorId").SetSparse(true),
+				})
+				return err
+			},
+			Down: func(ctx context.Context, db *mongo.Database) error {
+				// Los autores y las referencias se conservan: sin los índices siguen siendo
+				// datos válidos.
+				if err := dropIndexes(ctx, db.Collection("posts"), "idx_authorId"); err != nil {
+					return err
+				}
+				return dropIndexes(ctx, db.Collection("authors"), "idx_slug")
+			},
+		},
+		{
+			Version:     10,
+			Description: "etiquetas de posts normalizadas (minúsculas, sin espacios sobrantes ni repetidas)",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				normalizer := newTagNormalizer(tagAliases)
+				col := db.Collection("posts")
+				opts := options.Find().SetProjection(bson.M{"tags": 1})
+				cur, err := col.Find(ctx, bson.M{"tags.0": bson.M{"$exists": true}}, opts)
+				if err != nil {
+					return err
+				}
+				defer cur.Close(ctx)
+
+				for cur.Next(ctx) {
+					var post models.Post
+					if err := cur.Decode(&post); err != nil {
+						return err
+					}
+					tags := normalizer.tags(post.Tags)
+					if equalTags(tags, post.Tags) {
+						continue
+					}
+					if _, err := col.UpdateOne(ctx, bson.M{"_id": post.ID}, bson.M{"$set": bson.M{"tags": tags}}); err != nil {
+						return err
+					}
+				}
+				return cur.Err()
+			},
+			Down: func(ctx context.Context, db *mongo.Database) error {
+				// La forma original de las etiquetas no se conserva; las normalizadas son
+				// datos válidos para la versión anterior.
+				return nil
+			},
+		},
+		{
+			Version:     11,
+			Description: "colección tag_counts con índice idx_folded, reconstruida desde posts",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				_, err := db.Collection("tag_counts").Indexes().CreateOne(ctx, mongo.IndexModel{
+					Keys:    bson.D{{Key: "folded", Value: 1}},
+					Options: options.Index().SetName("idx_folded"),
+				})
+				if err != nil {
+					return err
+				}
+				return RebuildTagCounts(ctx, NewMongoPostRepository(db, 0, nil), NewMongoTagCountRepository(db, 0, nil))
+			},
+			Down: func(ctx context.Context, db *mongo.Database) error {
+				return db.Collection("tag_counts").Drop(ctx)
+			},
+		},
+		{
+			Version:     12,
+			Description: "índices idx_postId_status, idx_rootId e idx_status en comments",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				_, err := db.Collection("comments").Indexes().CreateMany(ctx, []mongo.IndexModel{
+					{
+						Keys:    bson.D{{Key: "postId", Value: 1}, {Key: "status", Value: 1}, {Key: "_id", Value: 1}},
+						Options: options.Index().SetName("idx_postId_status"),
+					},
+					{
+						Keys:    bson.D{{Key: "rootId", Value: 1}, {Key: "_id", Value: 1}},
+						Options: options.Index().SetName("idx_rootId").SetSparse(true),
+					},
+					{
+						Keys:    bson.D{{Key: "status", Value: 1}, {Key: "_id", Value: 1}},
+						Options: options.Index().SetName("idx_status"),
+					},
+				})
+				return err
+			},
+			Down: func(ctx context.Context, db *mongo.Database) error {
+				// Los comentarios se conservan: sin los índices siguen siendo datos válidos.
+				return dropIndexes(ctx, db.Collection("comments"), "idx_postId_status", "idx_rootId", "idx_status")
+			},
+		},
+		{
+			Version:     13,
+			Description: "índice único idx_post_kind_client en reactions e idx_total en reaction_counts",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				_, err := db.Collection("reactions").Indexes().CreateOne(ctx, mongo.IndexModel{
+					Keys:    bson.D{{Key: "postId", Value: 1}, {Key: "kind", Value: 1}, {Key: "clientId", Value: 1}},
+					Options: options.Index().SetName("idx_post_kind_client").SetUnique(true),
+				})
+				if err != nil {
+					return err
+				}
+				_, err = db.Collection("reaction_counts").Indexes().CreateOne(ctx, mongo.IndexModel{
+					Keys:    bson.D{{Key: "total", Value: -1}, {Key: "_id", Value: 1}},
+					Options: options.Index().SetName("idx_total"),
+				})
+				return err
+			},
+			Down: func(ctx context.Context, db *mongo.Database) error {
+				// Las reacciones y sus contadores se conservan; sin idx_post_kind_client la
+				// versión anterior no los usa.
+				if err := dropIndexes(ctx, db.Collection("reactions"), "idx_post_kind_client"); err != nil {
+					return err
+				}
+				return dropIndexes(ctx, db.Collection("reaction_counts"), "idx_total")
+			},
+		},
+	}
+}
+
+// dropIndexes elimina los índices indicados ignorando los que ya no existen.
+func dropIndexes(ctx context.Context, col *mongo.Collection, names ...string) error {
+	for _, name := range names {
+		if _, err := col.Indexes().DropOne(ctx, name); err != nil && !isIndexNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// isIndexNotFound detecta el error IndexNotFound (código 27) del servidor.
+func isIndexNotFound(err error) bool {
+	var cmdErr mongo.CommandError
+	return errors.As(err, &cmdErr) && cmdErr.Code == 27
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMigration3DownKeepsVersions(t *testing.T) {
+	db := newTestMongoDB(t)
+	ctx := context.Background()
+	id := primitive.NewObjectID()
+	if _, err := db.Collection("posts").InsertOne(ctx, bson.M{"_id": id, "title": "Primer post", "version": int64(4)}); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	migrator, err := NewMigrator(db, MongoMigrations(nil))
+	if err != nil {
+		t.Fatalf("migrator: %v", err)
+	}
+	reverted, err := migrator.Down(ctx, len(MongoMigrations(nil))-2)
+	if err != nil {
+		t.Fatalf("down: %v", err)
+	}
+	if last := reverted[len(reverted)-1]; last != 3 {
+		t.Fatalf("revertidas %v, la última debería ser 3", reverted)
+	}
+
+	var got struct {
+		Version int64 `bson:"version"`
+	}
+	if err := db.Collection("posts").FindOne(ctx, bson.M{"_id": id}).Decode(&got); err != nil || got.Version != 4 {
+		t.Fatalf("version tras down: %d %v, want 4", got.Version, err)
+	}
+}
+
+func TestMigration10AppliesTagAliases(t *testing.T) {
+	db := newTestMongoDB(t)
+	ctx := context.Background()
+	migrations := MongoMigrations(map[string]string{"golang": "go"})
+	migrator, err := NewMigrator(db, migrations)
+	if err != nil {
+		t.Fatalf("migrator: %v", err)
+	}
+	if _, err := migrator.Down(ctx, len(migrations)-9); err != nil {
+		t.Fatalf("down: %v", err)
+	}
+	id := primitive.NewObjectID()
+	if _, err := db.Collection("posts").InsertOne(ctx, bson.M{"_id": id, "title": "Primer post", "tags": bson.A{"Golang", "go ", "Mongo"}}); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if _, err := migrator.Up(ctx, 10); err != nil {
+		t.Fatalf("up: %v", err)
+	}
+
+	var got struct {
+		Tags []string `bson:"tags"`
+	}
+	if err := db.Collection("posts").FindOne(ctx, bson.M{"_id": id}).Decode(&got); err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	if !equalStrings(got.Tags, []string{"go", "mongo"}) {
+		t.Fatalf("tags: %v, want [go mongo]", got.Tags)
+	}
+}
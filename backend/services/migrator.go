@@ -0,0 +1,287 @@
+// services/migrator.go
+//
+// Paquete services: motor de migraciones versionadas para MongoDB.
+//
+// Convenciones:
+//   - Las migraciones son funciones Go ordenadas por Version (ver migrations.go).
+//   - Cada versión aplicada se registra en la colección "schema_migrations"
+//     como {_id: version, description, appliedAt}.
+//   - Up, Down y Status se ejecutan bajo el lease "schema_migrations" (ver mongoLease.go)
+//     para que instancias concurrentes no apliquen la misma migración dos veces.
+//   - Una migración fallida detiene el proceso; las anteriores quedan registradas.
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// migrationLockTTL es la vigencia del lease; se renueva antes de cada migración.
+	migrationLockTTL = 5 * time.Minute
+	// migrationLockWait es cuánto espera una instancia a que otra libere el lease.
+	migrationLockWait = 2 * time.Minute
+)
+
+// Migration es un paso versionado del esquema.
+//
+// Campos:
+//   - Version: entero positivo y único; define el orden de aplicación.
+//   - Description: texto corto mostrado en `migrate status`.
+//   - Up: aplica el cambio. Debe ser idempotente (puede re-ejecutarse tras un fallo).
+//   - Down: revierte el cambio; nil si la migración es irreversible.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, db *mongo.Database) error
+	Down        func(ctx context.Context, db *mongo.Database) error
+}
+
+// MigrationStatus describe el estado de una migración conocida.
+type MigrationStatus struct {
+	Version     int        `json:"version"`
+	Description string     `json:"description"`
+	Applied     bool       `json:"applied"`
+	AppliedAt   *time.Time `json:"appliedAt,omitempty"`
+}
+
+// appliedMigration es el documento guardado en schema_migrations.
+type appliedMigration struct {
+	Version     int       `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"appliedAt"`
+}
+
+// Migrator aplica y revierte migraciones sobre una base Mongo.
+type Migrator struct {
+	db         *mongo.Database
+	migrations []Migration
+	lease      *mongoLease
+}
+
+// NewMigrator crea un Migrator con migrations ordenadas por versión.
+//
+// Errores:
+//   - ErrInvalidInput si hay versiones no positivas o duplicadas, o migraciones sin Up.
+func NewMigrator(db *mongo.Database, migrations []Migration) (*Migrator, error) {
+	sorted := append([]Migration(nil), migrations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	for i, m := range sorted {
+		if m.Version <= 0 || m.Up == nil {
+			return nil, Wrap(fmt.Errorf("migración %d inválida", m.Version), ErrInvalidInput, "new migrator")
+		}
+		if i > 0 && sorted[i-1].Version == m.Version {
+			return nil, Wrap(fmt.Errorf("versión %d duplicada", m.Version), ErrInvalidInput, "new migrator")
+		}
+	}
+
+	return &Migrator{
+		db:         db,
+		migrations: sorted,
+		lease:      newMongoLease(db, "schema_migrations", migrationLockTTL),
+	}, nil
+}
+
+// Status lista todas las migraciones conocidas indicando si fueron aplicadas.
+// Las versiones registradas en la base pero desconocidas por este binario
+// también se incluyen (Description "(desconocida)").
+//
+// Errores:
+//   - ErrDB ante fallas del driver.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]MigrationStatus, 0, len(m.migrations))
+	known := map[int]bool{}
+	for _, mig := range m.migrations {
+		known[mig.Version] = true
+		st := MigrationStatus{Version: mig.Version, Description: mig.Description}
+		if a, ok := applied[mig.Version]; ok {
+			at := a.AppliedAt
+			st.Applied = true
+			st.AppliedAt = &at
+		}
+		out = append(out, st)
+	}
+	for v, a := range applied {
+		if !known[v] {
+			at := a.AppliedAt
+			out = append(out, MigrationStatus{Version: v, Description: "(desconocida)", Applied: true, AppliedAt: &at})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+// Up aplica en orden las migraciones pendientes con Version <= target
+// (target <= 0 aplica todas).
+//
+// Retornos:
+//   - versiones aplicadas en esta ejecución.
+//   - error con sentinelas: ErrConflict si otra instancia retiene el lock; ErrDB si una migración falla.
+func (m *Migrator) Up(ctx context.Context, target int) ([]int, error) {
+	var done []int
+	err := m.withLock(ctx, func() error {
+		applied, err := m.applied(ctx)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			if target > 0 && mig.Version > target {
+				break
+			}
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			if err := m.renewLock(ctx); err != nil {
+				return err
+			}
+			slog.InfoContext(ctx, "aplicando migración", slog.Int("version", mig.Version), slog.String("description", mig.Description))
+			if err := mig.Up(ctx, m.db); err != nil {
+				return Wrap(err, ErrDB, fmt.Sprintf("migration %d up", mig.Version))
+			}
+			if err := m.record(ctx, mig); err != nil {
+				return err
+			}
+			done = append(done, mig.Version)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down revierte las últimas steps migraciones aplicadas (steps <= 0 revierte una).
+//
+// Retornos:
+//   - versiones revertidas en esta ejecución, de la más nueva a la más antigua.
+//   - error con sentinelas: ErrConflict si otra instancia retiene el lock o la migración
+//     es irreversible/desconocida; ErrDB si el Down falla.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]int, error) {
+	if steps <= 0 {
+		steps = 1
+	}
+	byVersion := map[int]Migration{}
+	for _, mig := range m.migrations {
+		byVersion[mig.Version] = mig
+	}
+
+	var done []int
+	err := m.withLock(ctx, func() error {
+		applied, err := m.applied(ctx)
+		if err != nil {
+			return err
+		}
+		versions := make([]int, 0, len(applied))
+		for v := range applied {
+			versions = append(versions, v)
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(versions)))
+
+		for _, v := range versions {
+			if len(done) == steps {
+				break
+			}
+			mig, ok := byVersion[v]
+			if !ok || mig.Down == nil {
+				return Wrap(fmt.Errorf("migración %d sin Down", v), ErrConflict, "migration down")
+			}
+			if err := m.renewLock(ctx); err != nil {
+				return err
+			}
+			slog.InfoContext(ctx, "revirtiendo migración", slog.Int("version", mig.Version), slog.String("description", mig.Description))
+			if err := mig.Down(ctx, m.db); err != nil {
+				return Wrap(err, ErrDB, fmt.Sprintf("migration %d down", v))
+			}
+			if err := m.unrecord(ctx, v); err != nil {
+				return err
+			}
+			done = append(done, v)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// withLock ejecuta fn con el lease de migraciones tomado, esperando hasta
+// migrationLockWait si otra instancia lo retiene.
+func (m *Migrator) withLock(ctx context.Context, fn func() error) error {
+	waitCtx, cancel := context.WithTimeout(ctx, migrationLockWait)
+	defer cancel()
+	if err := m.lease.acquireWait(waitCtx, 500*time.Millisecond); err != nil {
+		return err
+	}
+	defer func() {
+		if err := m.lease.Release(context.Background()); err != nil {
+			slog.Warn("no se pudo liberar el lock de migraciones", slog.String("error", err.Error()))
+		}
+	}()
+	return fn()
+}
+
+// renewLock extiende el lease antes de cada migración; falla si otra instancia lo tomó.
+func (m *Migrator) renewLock(ctx context.Context) error {
+	ok, err := m.lease.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return Wrap(fmt.Errorf("lease perdido"), ErrConflict, "renew migration lock")
+	}
+	return nil
+}
+
+// applied lee schema_migrations indexado por versión.
+func (m *Migrator) applied(ctx context.Context) (map[int]appliedMigration, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	cur, err := m.db.Collection("schema_migrations").Find(ctx, bson.M{})
+	if err != nil {
+		return nil, Wrap(err, ErrDB, "find schema_migrations")
+	}
+	defer cur.Close(ctx)
+
+	out := map[int]appliedMigration{}
+	for cur.Next(ctx) {
+		var a appliedMigration
+		if err := cur.Decode(&a); err != nil {
+			return nil, Wrap(err, ErrDB, "decode schema_migration")
+		}
+		out[a.Version] = a
+	}
+	if err := cur.Err(); err != nil {
+		return nil, Wrap(err, ErrDB, "cursor error")
+	}
+	return out, nil
+}
+
+// record registra mig como aplicada (upsert por si un intento previo quedó a medias).
+func (m *Migrator) record(ctx context.Context, mig Migration) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	doc := appliedMigration{Version: mig.Version, Description: mig.Description, AppliedAt: time.Now().UTC()}
+	_, err := m.db.Collection("schema_migrations").ReplaceOne(ctx,
+		bson.M{"_id": mig.Version}, doc, options.Replace().SetUpsert(true))
+	return Wrap(err, ErrDB, "record migration")
+}
+
+// unrecord elimina el registro de la versión v.
+func (m *Migrator) unrecord(ctx context.Context, v int) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	_, err := m.db.Collection("schema_migrations").DeleteOne(ctx, bson.M{"_id": v})
+	return Wrap(err, ErrDB, "unrecord migration")
+}
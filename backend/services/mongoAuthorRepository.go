@@ -0,0 +1,165 @@
+// services/mongoAuthorRepository.go
+//
+// Paquete services: implementación de AuthorRepository sobre MongoDB.
+//
+// Convenciones:
+//   - Colección "authors" con índice único idx_slug (migración 9).
+//   - Mismo manejo de timeout, errores y OpObserver que MongoPostRepository.
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoAuthorRepository persiste autores en la colección "authors".
+type MongoAuthorRepository struct {
+	col     *mongo.Collection
+	timeout time.Duration
+	observe OpObserver
+}
+
+// NewMongoAuthorRepository crea un repositorio sobre la colección "authors" de db.
+// timeout limita cada operación (<=0 usa defaultTimeout); observe puede ser nil.
+func NewMongoAuthorRepository(db *mongo.Database, timeout time.Duration, observe OpObserver) *MongoAuthorRepository {
+	return &MongoAuthorRepository{col: db.Collection("authors"), timeout: orDefaultTimeout(timeout), observe: observe}
+}
+
+// Create inserta a; asigna un ObjectID nuevo si no trae uno.
+//
+// Errores:
+//   - ErrConflict: slug en uso (índice idx_slug).
+//   - ErrDB: error del driver.
+func (r *MongoAuthorRepository) Create(ctx context.Context, a models.Author) (_ primitive.ObjectID, err error) {
+	defer r.observe.track("insert", time.Now(), &err)
+
+	if a.ID.IsZero() {
+		a.ID = primitive.NewObjectID()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	if _, err := r.col.InsertOne(ctx, a); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return primitive.NilObjectID, Wrap(err, ErrConflict, "insert author")
+		}
+		return primitive.NilObjectID, Wrap(err, ErrDB, "insert author")
+	}
+	return a.ID, nil
+}
+
+// Get recupera un autor por ObjectID.
+//
+// Errores:
+//   - ErrNotFound si no existe; ErrDB si falla el driver.
+func (r *MongoAuthorRepository) Get(ctx context.Context, id primitive.ObjectID) (models.Author, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+// GetBySlug recupera un autor por slug (índice idx_slug).
+//
+// Errores:
+//   - ErrNotFound si no existe; ErrDB si falla el driver.
+func (r *MongoAuthorRepository) GetBySlug(ctx context.Context, slug string) (models.Author, error) {
+	return r.findOne(ctx, bson.M{"slug": slug})
+}
+
+// findOne recupera el autor que cumple filter.
+func (r *MongoAuthorRepository) findOne(ctx context.Context, filter bson.M) (_ models.Author, err error) {
+	defer r.observe.track("find", time.Now(), &err)
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var out models.Author
+	if err := r.col.FindOne(ctx, filter).Decode(&out); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return models.Author{}, Wrap(err, ErrNotFound, "author not found")
+		}
+		return models.Author{}, Wrap(err, ErrDB, "find author")
+	}
+	return out, nil
+}
+
+// List retorna todos los autores ordenados por nombre (a igual nombre, por _id).
+//
+// Errores:
+//   - ErrDB: error del driver o del cursor.
+func (r *MongoAuthorRepository) List(ctx context.Context) (_ []models.Author, err error) {
+	defer r.observe.track("find", time.Now(), &err)
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}})
+	cur, err := r.col.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, Wrap(err, ErrDB, "find authors")
+	}
+	defer cur.Close(ctx)
+
+	out := []models.Author{}
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, Wrap(err, ErrDB, "decode authors")
+	}
+	return out, nil
+}
+
+// Update aplica los campos editables de a con FindOneAndUpdate y retorna el estado After.
+//
+// Errores:
+//   - ErrNotFound si no existe; ErrConflict si el slug está en uso; ErrDB si falla el driver.
+func (r *MongoAuthorRepository) Update(ctx context.Context, id primitive.ObjectID, a models.Author) (_ models.Author, err error) {
+	defer r.observe.track("findOneAndUpdate", time.Now(), &err)
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{
+		"name":      a.Name,
+		"slug":      a.Slug,
+		"bio":       a.Bio,
+		"avatarUrl": a.AvatarURL,
+		"updatedAt": a.UpdatedAt,
+	}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var out models.Author
+	if err := r.col.FindOneAndUpdate(ctx, bson.M{"_id": id}, update, opts).Decode(&out); err != nil {
+		switch {
+		case errors.Is(err, mongo.ErrNoDocuments):
+			return models.Author{}, Wrap(err, ErrNotFound, "author not found")
+		case mongo.IsDuplicateKeyError(err):
+			return models.Author{}, Wrap(err, ErrConflict, "update author")
+		}
+		return models.Author{}, Wrap(err, ErrDB, "update author")
+	}
+	return out, nil
+}
+
+// Delete elimina un autor por id.
+//
+// Errores:
+//   - ErrNotFound si no existía; ErrDB si falla el driver.
+func (r *MongoAuthorRepository) Delete(ctx context.Context, id primitive.ObjectID) (err error) {
+	defer r.observe.track("delete", time.Now(), &err)
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	res, err := r.col.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return Wrap(err, ErrDB, "delete author")
+	}
+	if res.DeletedCount == 0 {
+		return Wrap(mongo.ErrNoDocuments, ErrNotFound, "author not found")
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+// services/mongoCommentRepository.go
+//
+// Paquete services: implementación de CommentRepository sobre MongoDB.
+//
+// Convenciones:
+//   - Colección "comments" con índices {postId:1, status:1, _id:1} (hilos de un post y
+//     conteos), {rootId:1, _id:1} (respuestas) y {status:1, _id:1} (cola de moderación),
+//     creados por la migración 12.
+//   - Mismo manejo de timeout, errores y OpObserver que MongoPostRepository.
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoCommentRepository persiste comentarios en la colección "comments".
+type MongoCommentRepository struct {
+	col     *mongo.Collection
+	timeout time.Duration
+	observe OpObserver
+}
+
+// NewMongoCommentRepository crea un repositorio sobre la colección "comments" de db.
+// timeout limita cada operación (<=0 usa defaultTimeout); observe puede ser nil.
+func NewMongoCommentRepository(db *mongo.Database, timeout time.Duration, observe OpObserver) *MongoCommentRepository {
+	return &MongoCommentRepository{col: db.Collection("comments"), timeout: orDefaultTimeout(timeout), observe: observe}
+}
+
+// Create inserta c; asigna un ObjectID nuevo si no trae uno.
+//
+// Errores:
+//   - ErrDB: error del driver.
+func (r *MongoCommentRepository) Create(ctx context.Context, c models.Comment) (_ primitive.ObjectID, err error) {
+	defer r.observe.track("insert", time.Now(), &err)
+
+	if c.ID.IsZero() {
+		c.ID = primitive.NewObjectID()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	if _, err := r.col.InsertOne(ctx, c); err != nil {
+		return primitive.NilObjectID, Wrap(err, ErrDB, "insert comment")
+	}
+	return c.ID, nil
+}
+
+// Get recupera un comentario por ObjectID.
+//
+// Errores:
+//   - ErrNotFound si no existe; ErrDB si falla el driver.
+func (r *MongoCommentRepository) Get(ctx context.Context, id primitive.ObjectID) (_ models.Comment, err error) {
+	defer r.observe.track("find", time.Now(), &err)
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var out models.Comment
+	if err := r.col.FindOne(ctx, bson.M{"_id": id}).Decode(&out); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return models.Comment{}, Wrap(err, ErrNotFound, "comment not found")
+		}
+		return models.Comment{}, Wrap(err, ErrDB, "find comment")
+	}
+	return out, nil
+}
+
+// List retorna los comentarios que cumplen q ordenados por _id.
+//
+// Errores:
+//   - ErrDB: error del driver o del cursor.
+func (r *MongoCommentRepository) List(ctx context.Context, q CommentQuery) (_ []models.Comment, err error) {
+	defer r.observe.track("find", time.Now(), &err)
+
+	filter := bson.M{}
+	if !q.PostID.IsZero() {
+		filter["postId"] = q.PostID
+	}
+	if q.Roots {
+		filter["rootId"] = bson.M{"$exists": false}
+	}
+	if q.RootIDs != nil {
+		filter["rootId"] = bson.M{"$in": q.RootIDs}
+	}
+	if q.Status != "" {
+		filter["status"] = q.Status
+	}
+	if !q.After.IsZero() {
+		filter["_id"] = bson.M{"$gt": q.After}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	if q.Limit > 0 {
+		opts.SetLimit(int64(q.Limit))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	cur, err := r.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, Wrap(err, ErrDB, "find comments")
+	}
+	defer cur.Close(ctx)
+
+	out := []models.Comment{}
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, Wrap(err, ErrDB, "decode comments")
+	}
+	return out, nil
+}
+
+// SetStatus modera los comentarios de ids que tengan otro estado con un UpdateMany.
+//
+// Errores:
+//   - ErrDB: error del driver.
+func (r *MongoCommentRepository) SetStatus(ctx context.Context, ids []primitive.ObjectID, status, moderatedBy string, moderatedAt time.Time) (_ int64, err error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	defer r.observe.track("update", time.Now(), &err)
+
+	set := bson.M{"status": status, "moderatedAt": moderatedAt}
+	update := bson.M{"$set": set}
+	if moderatedBy != "" {
+		set["moderatedBy"] = moderatedBy
+	} else {
+		update["$unset"] = bson.M{"moderatedBy": ""}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	res, err := r.col.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}, "status": bson.M{"$ne": status}}, update)
+	if err != nil {
+		return 0, Wrap(err, ErrDB, "moderate comments")
+	}
+	return res.ModifiedCount, nil
+}
+
+// CountByPosts agrupa por postId los comentarios con status de postIDs.
+//
+// Errores:
+//   - ErrDB: error del pipeline o del cursor.
+func (r *MongoCommentRepository) CountByPosts(ctx context.Context, postIDs []primitive.ObjectID, status string) (_ map[primitive.ObjectID]int64, err error) {
+	out := map[primitive.ObjectID]int64{}
+	if len(postIDs) == 0 {
+		return out, nil
+	}
+	defer r.observe.track("aggregate", time.Now(), &err)
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"postId": bson.M{"$in": postIDs}, "status": status}}},
+		{{Key: "$group", Value: bson.M{"_id": "$postId", "count": bson.M{"$sum": 1}}}},
+	}
+	cur, err := r.col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, Wrap(err, ErrDB, "count comments")
+	}
+	defer cur.Close(ctx)
+
+	var rows []struct {
+		PostID primitive.ObjectID `bson:"_id"`
+		Count  int64              `bson:"count"`
+	}
+	if err := cur.All(ctx, &rows); err != nil {
+		return nil, Wrap(err, ErrDB, "decode comment counts")
+	}
+	for _, row := range rows {
+		out[row.PostID] = row.Count
+	}
+	return out, nil
+}
+
+// DeleteByPosts borra los comentarios de postIDs.
+//
+// Errores:
+//   - ErrDB: error del driver.
+func (r *MongoCommentRepository) DeleteByPosts(ctx context.Context, postIDs []primitive.ObjectID) (_ int64, err error) {
+	if len(postIDs) == 0 {
+		return 0, nil
+	}
+	defer r.observe.track("delete", time.Now(), &err)
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	res, err := r.col.DeleteMany(ctx, bson.M{"postId": bson.M{"$in": postIDs}})
+	if err != nil {
+		return 0, Wrap(err, ErrDB, "delete comments")
+	}
+	return res.DeletedCount, nil
+}
@@ -0,0 +1,113 @@
+// services/mongoLease.go
+//
+// Paquete services: leases (locks con expiración) almacenados en MongoDB.
+//
+// Convenciones:
+//   - Cada lease es un documento {_id: name, owner, expiresAt} en la colección "leases".
+//   - Adquirir es atómico: un upsert que sólo coincide si el lease expiró o ya es nuestro;
+//     si otro dueño lo tiene vigente, el upsert choca con _id duplicado y se informa false.
+//   - Un lease expirado puede ser tomado por otra instancia, por lo que el dueño
+//     debe renovarlo (Acquire de nuevo) antes de que venza si su trabajo es largo.
+//   - mongoLease implementa Lease (ver lease.go).
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// mongoLease representa un lease con nombre tomado por owner durante ttl.
+type mongoLease struct {
+	col   *mongo.Collection
+	name  string
+	owner string
+	ttl   time.Duration
+}
+
+// newMongoLease crea un lease sobre la colección "leases" de db con un owner único
+// para este proceso (hostname-pid-ObjectID).
+func newMongoLease(db *mongo.Database, name string, ttl time.Duration) *mongoLease {
+	return &mongoLease{
+		col:   db.Collection("leases"),
+		name:  name,
+		owner: leaseOwnerID(),
+		ttl:   ttl,
+	}
+}
+
+// Acquire toma o renueva el lease.
+//
+// Retornos:
+//   - true si el lease quedó a nombre de este owner hasta now+ttl.
+//   - false si otro owner lo tiene vigente.
+//   - error con sentinel ErrDB ante fallas del driver.
+func (l *mongoLease) Acquire(ctx context.Context) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	now := time.Now().UTC()
+	filter := bson.M{
+		"_id": l.name,
+		"$or": bson.A{
+			bson.M{"owner": l.owner},
+			bson.M{"expiresAt": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"owner": l.owner, "expiresAt": now.Add(l.ttl)}}
+
+	_, err := l.col.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, Wrap(err, ErrDB, "acquire lease "+l.name)
+	}
+	return true, nil
+}
+
+// acquireWait reintenta Acquire cada interval hasta obtener el lease o agotar ctx.
+//
+// Errores:
+//   - ErrConflict si ctx vence sin obtener el lease.
+//   - ErrDB ante fallas del driver.
+func (l *mongoLease) acquireWait(ctx context.Context, interval time.Duration) error {
+	for {
+		ok, err := l.Acquire(ctx)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return Wrap(ctx.Err(), ErrConflict, "lease "+l.name+" held by another instance")
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Release libera el lease si sigue a nombre de este owner.
+func (l *mongoLease) Release(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	_, err := l.col.DeleteOne(ctx, bson.M{"_id": l.name, "owner": l.owner})
+	return Wrap(err, ErrDB, "release lease "+l.name)
+}
+
+// leaseOwnerID identifica a este proceso de forma única entre instancias.
+func leaseOwnerID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), primitive.NewObjectID().Hex())
+}
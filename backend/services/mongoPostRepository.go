@@ -0,0 +1,630 @@
+// services/mongoPostRepository.go
+//
+// Paquete services: implementación de PostRepository sobre MongoDB.
+//
+// Convenciones:
+//   - Todas las operaciones aplican el timeout configurado (DB_TIMEOUT; defaultTimeout si es 0).
+//   - Los errores del driver se envuelven con sentinelas; mongo.ErrNoDocuments → ErrNotFound.
+//   - Cada llamada al driver se reporta al OpObserver (duración + sentinel) con el
+//     nombre de la operación Mongo: insert, find, findOneAndUpdate, delete, aggregate, count.
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// defaultTimeout es el tiempo máximo de espera por operación cuando no se configura
+// otro; también lo usan las operaciones de infraestructura (leases, migraciones).
+const defaultTimeout = 5 * time.Second
+
+// MongoPostRepository persiste posts en la colección "posts".
+type MongoPostRepository struct {
+	col     *mongo.Collection
+	timeout time.Duration
+	observe OpObserver
+}
+
+// NewMongoPostRepository crea un repositorio sobre la colección "posts" de db.
+// timeout limita cada operación (<=0 usa defaultTimeout); observe puede ser nil.
+func NewMongoPostRepository(db *mongo.Database, timeout time.Duration, observe OpObserver) *MongoPostRepository {
+	return &MongoPostRepository{col: db.Collection("posts"), timeout: orDefaultTimeout(timeout), observe: observe}
+}
+
+// orDefaultTimeout retorna d, o defaultTimeout si d no es positivo.
+func orDefaultTimeout(d time.Duration) time.Duration {
+	if d <= 0 {
+		return defaultTimeout
+	}
+	return d
+}
+
+// Create inserta p y retorna el ObjectID generado.
+//
+// Errores:
+//   - ErrConflict: clave duplicada (slug en uso, índice idx_slug).
+//   - ErrDB: error del driver o id insertado con tipo inesperado.
+func (r *MongoPostRepository) Create(ctx context.Context, p models.Post) (_ primitive.ObjectID, err error) {
+	defer r.observe.track("insert", time.Now(), &err)
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	res, err := r.col.InsertOne(ctx, p)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return primitive.NilObjectID, Wrap(err, ErrConflict, "insert post")
+		}
+		return primitive.NilObjectID, Wrap(err, ErrDB, "insert post")
+	}
+	oid, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return primitive.NilObjectID, Wrap(errors.New("inserted id not an ObjectID"), ErrDB, "cast inserted id")
+	}
+	return oid, nil
+}
+
+// Get recupera un post por ObjectID.
+//
+// Errores:
+//   - ErrNotFound si no existe; ErrDB si falla el driver.
+func (r *MongoPostRepository) Get(ctx context.Context, id primitive.ObjectID) (_ models.Post, err error) {
+	defer r.observe.track("find", time.Now(), &err)
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var out models.Post
+	if err := r.col.FindOne(ctx, bson.M{"_id": id}).Decode(&out); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return models.Post{}, Wrap(err, ErrNotFound, "post not found")
+		}
+		return models.Post{}, Wrap(err, ErrDB, "find post")
+	}
+	return out, nil
+}
+
+// GetBySlug busca por slug vigente (índice idx_slug) y, si no hay, en oldSlugs
+// (índice idx_oldSlugs); ver migración 6.
+//
+// Errores:
+//   - ErrNotFound si ningún post tiene ni tuvo ese slug; ErrDB si falla el driver.
+func (r *MongoPostRepository) GetBySlug(ctx context.Context, slug string) (_ models.Post, err error) {
+	defer r.observe.track("find", time.Now(), &err)
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	for _, filter := range []bson.M{{"slug": slug}, {"oldSlugs": slug}} {
+		var out models.Post
+		err := r.col.FindOne(ctx, filter).Decode(&out)
+		if err == nil {
+			return out, nil
+		}
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return models.Post{}, Wrap(err, ErrDB, "find post by slug")
+		}
+	}
+	return models.Post{}, Wrap(mongo.ErrNoDocuments, ErrNotFound, "post not found")
+}
+
+// Update aplica los campos editables de p con FindOneAndUpdate ($set + $inc version)
+// y retorna el estado After. Con expectedVersion > 0 el filtro incluye la versión,
+// de modo que la comparación y la escritura son atómicas.
+//
+// Errores:
+//   - ErrNotFound si el documento desapareció; ErrConflict si la versión cambió o el
+//     slug está en uso; ErrDB si falla el driver.
+func (r *MongoPostRepository) Update(ctx context.Context, id primitive.ObjectID, p models.Post, expectedVersion int64) (_ models.Post, err error) {
+	defer r.observe.track("findOneAndUpdate", time.Now(), &err)
+
+	set := bson.M{
+		"title":     p.Title,
+		"slug":      p.Slug,
+		"oldSlugs":  p.OldSlugs,
+		"author":    p.Author,
+		"content":   p.Content,
+		"tags":      p.Tags,
+		"published": p.Published,
+		"updatedAt": p.UpdatedAt,
+	}
+	if p.PublishedAt != nil {
+		set["publishedAt"] = p.PublishedAt
+	}
+	if p.Status != "" {
+		set["status"] = p.Status
+	}
+	unset := bson.M{}
+	if p.AuthorID.IsZero() {
+		unset["authorId"] = ""
+	} else {
+		set["authorId"] = p.AuthorID
+	}
+	for field, at := range map[string]*time.Time{"publishAt": p.PublishAt, "unpublishAt": p.UnpublishAt} {
+		if at != nil {
+			set[field] = at
+		} else {
+			unset[field] = ""
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := versionFilter(id, expectedVersion)
+	update := bson.M{"$set": set, "$inc": bson.M{"version": 1}}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var updated models.Post
+	if err := r.col.
+		FindOneAndUpdate(ctx, filter, update, opts).
+		Decode(&updated); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return models.Post{}, r.missOrConflict(ctx, id, expectedVersion, "post not found after update")
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			return models.Post{}, Wrap(err, ErrConflict, "findOneAndUpdate post")
+		}
+		return models.Post{}, Wrap(err, ErrDB, "findOneAndUpdate post")
+	}
+	return updated, nil
+}
+
+// versionFilter arma {_id} o {_id, version} según expectedVersion.
+func versionFilter(id primitive.ObjectID, expectedVersion int64) bson.M {
+	filter := bson.M{"_id": id}
+	if expectedVersion > 0 {
+		filter["version"] = expectedVersion
+	}
+	return filter
+}
+
+// missOrConflict distingue, tras una escritura condicionada que no encontró documento,
+// si el post no existe (ErrNotFound) o si cambió de versión (ErrConflict).
+func (r *MongoPostRepository) missOrConflict(ctx context.Context, id primitive.ObjectID, expectedVersion int64, msg string) error {
+	if expectedVersion <= 0 {
+		return Wrap(mongo.ErrNoDocuments, ErrNotFound, msg)
+	}
+	n, err := r.count(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return Wrap(mongo.ErrNoDocuments, ErrNotFound, msg)
+	}
+	return Wrap(errVersionMismatch, ErrVersionMismatch, fmt.Sprintf("expected version %d", expectedVersion))
+}
+
+// UpdateTags agrega etiquetas con $addToSet ($each) y quita con $pull ($in), junto con
+// $set updatedAt y $inc version, en un único FindOneAndUpdate: dos ediciones de tags
+// concurrentes se aplican ambas en lugar de pisarse.
+//
+// Notas:
+//   - $addToSet falla si tags es null; PostService sólo usa esta vía cuando el post ya
+//     tiene etiquetas.
+//
+// Errores:
+//   - ErrNotFound si el documento desapareció; ErrConflict si la versión cambió;
+//     ErrDB si falla el driver.
+func (r *MongoPostRepository) UpdateTags(ctx context.Context, id primitive.ObjectID, change TagChange, updatedAt time.Time, expectedVersion int64) (_ models.Post, err error) {
+	defer r.observe.track("findOneAndUpdate", time.Now(), &err)
+
+	update := bson.M{
+		"$set": bson.M{"updatedAt": updatedAt},
+		"$inc": bson.M{"version": 1},
+	}
+	if len(change.Add) > 0 {
+		update["$addToSet"] = bson.M{"tags": bson.M{"$each": change.Add}}
+	}
+	if len(change.Remove) > 0 {
+		update["$pull"] = bson.M{"tags": bson.M{"$in": change.Remove}}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var updated models.Post
+	if err := r.col.
+		FindOneAndUpdate(ctx, versionFilter(id, expectedVersion), update, opts).
+		Decode(&updated); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return models.Post{}, r.missOrConflict(ctx, id, expectedVersion, "post not found after update")
+		}
+		return models.Post{}, Wrap(err, ErrDB, "findOneAndUpdate post tags")
+	}
+	return updated, nil
+}
+
+// SetDeleted fija deletedAt/deletedBy ($set) o, con deletedAt nil, los quita ($unset);
+// en ambos casos incrementa version en el mismo FindOneAndUpdate.
+//
+// Errores:
+//   - ErrNotFound si el documento desapareció; ErrConflict si la versión cambió;
+//     ErrDB si falla el driver.
+func (r *MongoPostRepository) SetDeleted(ctx context.Context, id primitive.ObjectID, deletedAt *time.Time, deletedBy string, expectedVersion int64) (_ models.Post, err error) {
+	defer r.observe.track("findOneAndUpdate", time.Now(), &err)
+
+	update := bson.M{"$inc": bson.M{"version": 1}}
+	if deletedAt != nil {
+		update["$set"] = bson.M{"deletedAt": deletedAt, "deletedBy": deletedBy}
+	} else {
+		update["$unset"] = bson.M{"deletedAt": "", "deletedBy": ""}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var updated models.Post
+	if err := r.col.
+		FindOneAndUpdate(ctx, versionFilter(id, expectedVersion), update, opts).
+		Decode(&updated); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return models.Post{}, r.missOrConflict(ctx, id, expectedVersion, "post not found after update")
+		}
+		return models.Post{}, Wrap(err, ErrDB, "findOneAndUpdate post deleted")
+	}
+	return updated, nil
+}
+
+// PurgeDeleted busca los ids de los posts con deletedAt < before (índice idx_deletedAt,
+// migración 4) y los borra con DeleteMany repitiendo el filtro de fecha, de modo que
+// un post restaurado entre ambas operaciones no se elimina.
+//
+// Errores:
+//   - ErrDB si falla el driver.
+func (r *MongoPostRepository) PurgeDeleted(ctx context.Context, before time.Time) (_ []primitive.ObjectID, err error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{"deletedAt": bson.M{"$lt": before}}
+	expired, err := r.find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}), 0)
+	if err != nil || len(expired) == 0 {
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, len(expired))
+	for i, post := range expired {
+		ids[i] = post.ID
+	}
+
+	defer r.observe.track("delete", time.Now(), &err)
+	filter["_id"] = bson.M{"$in": ids}
+	if _, err := r.col.DeleteMany(ctx, filter); err != nil {
+		return nil, Wrap(err, ErrDB, "purge deleted posts")
+	}
+	return ids, nil
+}
+
+// Delete elimina un post por ObjectID; con expectedVersion > 0 sólo si la versión coincide.
+//
+// Errores:
+//   - ErrNotFound si no existía; ErrConflict si la versión cambió; ErrDB si falla el driver.
+func (r *MongoPostRepository) Delete(ctx context.Context, id primitive.ObjectID, expectedVersion int64) (err error) {
+	defer r.observe.track("delete", time.Now(), &err)
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	res, err := r.col.DeleteOne(ctx, versionFilter(id, expectedVersion))
+	if err != nil {
+		return Wrap(err, ErrDB, "delete post")
+	}
+	if res.DeletedCount == 0 {
+		return r.missOrConflict(ctx, id, expectedVersion, "post not found")
+	}
+	return nil
+}
+
+// List traduce ListPostsParams a filtro/orden de Mongo y retorna la página y el total.
+//
+// Notas:
+//   - Q usa $text (requiere índice text_title_content).
+//   - Índice {published:1, publishedAt:-1} para listados.
+//   - {deletedAt: null} coincide con documentos sin el campo (no eliminados).
+//   - Scheduled usa los índices idx_publishAt / idx_unpublishAt (migración 7) y ordena
+//     con un pipeline (ver listScheduled).
+func (r *MongoPostRepository) List(ctx context.Context, p ListPostsParams) ([]models.Post, int64, error) {
+	filter := bson.M{"deletedAt": nil}
+	if p.Deleted {
+		filter["deletedAt"] = bson.M{"$ne": nil}
+	}
+	if p.Scheduled {
+		filter["$or"] = bson.A{
+			bson.M{"publishAt": bson.M{"$ne": nil}},
+			bson.M{"unpublishAt": bson.M{"$ne": nil}},
+		}
+	}
+	if p.Q != "" {
+		filter["$text"] = bson.M{"$search": p.Q}
+	}
+	if p.Tag != "" {
+		filter["tags"] = p.Tag
+	}
+	if p.Published != nil {
+		filter["published"] = *p.Published
+	}
+	if p.Status != "" {
+		filter["status"] = p.Status
+	}
+	if !p.AuthorID.IsZero() {
+		filter["authorId"] = p.AuthorID
+	}
+
+	var sort bson.D
+	switch {
+	case p.Deleted:
+		sort = bson.D{{Key: "deletedAt", Value: -1}, {Key: "_id", Value: 1}}
+	case p.SortField == "publishedAt":
+		sort = bson.D{{Key: "publishedAt", Value: 1}}
+	default:
+		sort = bson.D{{Key: "publishedAt", Value: -1}}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	total, err := r.count(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	if p.Scheduled {
+		items, err := r.listScheduled(ctx, filter, p)
+		return items, total, err
+	}
+
+	opts := options.Find().
+		SetSort(sort).
+		SetSkip(int64((p.Page - 1) * p.Limit)).
+		SetLimit(int64(p.Limit))
+
+	items, err := r.find(ctx, filter, opts, p.Limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// listScheduled pagina los posts de filter ordenados por la fecha programada más
+// próxima: find no puede ordenar por una expresión, así que $min (que ignora campos
+// ausentes) la calcula en un campo temporal.
+func (r *MongoPostRepository) listScheduled(ctx context.Context, filter bson.M, p ListPostsParams) (_ []models.Post, err error) {
+	defer r.observe.track("aggregate", time.Now(), &err)
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$addFields", Value: bson.M{"nextScheduledAt": bson.M{"$min": bson.A{"$publishAt", "$unpublishAt"}}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "nextScheduledAt", Value: 1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$skip", Value: int64((p.Page - 1) * p.Limit)}},
+		{{Key: "$limit", Value: int64(p.Limit)}},
+		{{Key: "$project", Value: bson.M{"nextScheduledAt": 0}}},
+	}
+
+	cur, err := r.col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, Wrap(err, ErrDB, "aggregate scheduled posts")
+	}
+	defer cur.Close(ctx)
+
+	items := make([]models.Post, 0, p.Limit)
+	if err := cur.All(ctx, &items); err != nil {
+		return nil, Wrap(err, ErrDB, "decode scheduled posts")
+	}
+	return items, nil
+}
+
+// count ejecuta CountDocuments(filter) como operación "count".
+func (r *MongoPostRepository) count(ctx context.Context, filter bson.M) (_ int64, err error) {
+	defer r.observe.track("count", time.Now(), &err)
+
+	total, err := r.col.CountDocuments(ctx, filter)
+	if err != nil {
+		return 0, Wrap(err, ErrDB, "count posts")
+	}
+	return total, nil
+}
+
+// find ejecuta Find(filter, opts) y decodifica el cursor completo como operación "find".
+func (r *MongoPostRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions, capacity int) (_ []models.Post, err error) {
+	defer r.observe.track("find", time.Now(), &err)
+
+	cur, err := r.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, Wrap(err, ErrDB, "find posts")
+	}
+	defer cur.Close(ctx)
+
+	items := make([]models.Post, 0, capacity)
+	for cur.Next(ctx) {
+		var post models.Post
+		if err := cur.Decode(&post); err != nil {
+			return nil, Wrap(err, ErrDB, "decode post")
+		}
+		items = append(items, post)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, Wrap(err, ErrDB, "cursor error")
+	}
+	return items, nil
+}
+
+// AggregateByTag ejecuta el pipeline $unwind/$group sobre tags.
+//
+// Errores:
+//   - ErrDB ante errores del pipeline/cursor.
+func (r *MongoPostRepository) AggregateByTag(ctx context.Context, limit int, onlyPublished *bool, status string) (_ []TagMetric, err error) {
+	defer r.observe.track("aggregate", time.Now(), &err)
+
+	match := bson.M{"tags": bson.M{"$type": "string"}, "deletedAt": nil}
+	if onlyPublished != nil {
+		match["published"] = *onlyPublished
+	}
+	if status != "" {
+		match["status"] = status
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$unwind", Value: "$tags"}},
+		{{Key: "$match", Value: bson.M{"tags": bson.M{"$ne": ""}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$tags", "count": bson.M{"$sum": 1}}}},
+		{{Key: "$sort", Value: bson.M{"count": -1}}},
+		{{Key: "$limit", Value: limit}},
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	cur, err := r.col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, Wrap(err, ErrDB, "aggregate by-tag")
+	}
+	defer cur.Close(ctx)
+
+	out := make([]TagMetric, 0, limit)
+	for cur.Next(ctx) {
+		var m TagMetric
+		if err := cur.Decode(&m); err != nil {
+			return nil, Wrap(err, ErrDB, "decode metric row")
+		}
+		out = append(out, m)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, Wrap(err, ErrDB, "cursor error")
+	}
+	return out, nil
+}
+
+// CountByAuthor cuenta los posts de authorID (índice idx_authorId), incluidos los de la
+// papelera.
+//
+// Errores:
+//   - ErrDB si falla el driver.
+func (r *MongoPostRepository) CountByAuthor(ctx context.Context, authorID primitive.ObjectID) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	return r.count(ctx, bson.M{"authorId": authorID})
+}
+
+// SetAuthorName copia name en los posts de authorID que tienen otro nombre con un solo
+// UpdateMany.
+//
+// Errores:
+//   - ErrDB si falla el driver.
+func (r *MongoPostRepository) SetAuthorName(ctx context.Context, authorID primitive.ObjectID, name string, updatedAt time.Time) (_ int64, err error) {
+	defer r.observe.track("update", time.Now(), &err)
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	res, err := r.col.UpdateMany(ctx,
+		bson.M{"authorId": authorID, "author": bson.M{"$ne": name}},
+		bson.M{"$set": bson.M{"author": name, "updatedAt": updatedAt}, "$inc": bson.M{"version": 1}})
+	if err != nil {
+		return 0, Wrap(err, ErrDB, "update author name")
+	}
+	return res.ModifiedCount, nil
+}
+
+// ReplaceTags reescribe las etiquetas de los posts que tienen alguna de from con un
+// único UpdateMany por pipeline: $map reemplaza from por to y $reduce descarta las
+// repetidas conservando la primera posición.
+//
+// Errores:
+//   - ErrDB si falla el driver.
+func (r *MongoPostRepository) ReplaceTags(ctx context.Context, from []string, to string, updatedAt time.Time) (_ int64, err error) {
+	defer r.observe.track("update", time.Now(), &err)
+
+	replaced := bson.M{"$map": bson.M{
+		"input": "$tags",
+		"in":    bson.M{"$cond": bson.A{bson.M{"$in": bson.A{"$$this", from}}, to, "$$this"}},
+	}}
+	deduped := bson.M{"$reduce": bson.M{
+		"input":        replaced,
+		"initialValue": bson.A{},
+		"in": bson.M{"$cond": bson.A{
+			bson.M{"$in": bson.A{"$$this", "$$value"}},
+			"$$value",
+			bson.M{"$concatArrays": bson.A{"$$value", bson.A{"$$this"}}},
+		}},
+	}}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	res, err := r.col.UpdateMany(ctx,
+		bson.M{"tags": bson.M{"$in": from}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{
+			"tags":      deduped,
+			"updatedAt": updatedAt,
+			"version":   bson.M{"$add": bson.A{"$version", 1}},
+		}}}})
+	if err != nil {
+		return 0, Wrap(err, ErrDB, "replace tags")
+	}
+	return res.ModifiedCount, nil
+}
+
+// CountTags agrupa por etiqueta y estado los posts fuera de la papelera; con tags no
+// nil el primer $match usa el índice de tags.
+//
+// Errores:
+//   - ErrDB si falla el driver.
+func (r *MongoPostRepository) CountTags(ctx context.Context, tags []string) (_ []models.TagCount, err error) {
+	defer r.observe.track("aggregate", time.Now(), &err)
+
+	match := bson.M{"tags": bson.M{"$type": "string"}, "deletedAt": nil}
+	unwound := bson.M{"tags": bson.M{"$ne": ""}}
+	if tags != nil {
+		match["tags"] = bson.M{"$in": tags}
+		unwound["tags"] = bson.M{"$in": tags}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$unwind", Value: "$tags"}},
+		{{Key: "$match", Value: unwound}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"tag": "$tags", "status": "$status"},
+			"count": bson.M{"$sum": 1},
+		}}},
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	cur, err := r.col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, Wrap(err, ErrDB, "aggregate tag counts")
+	}
+	defer cur.Close(ctx)
+
+	var set tagCountSet
+	for cur.Next(ctx) {
+		var row struct {
+			ID struct {
+				Tag    string `bson:"tag"`
+				Status string `bson:"status"`
+			} `bson:"_id"`
+			Count int64 `bson:"count"`
+		}
+		if err := cur.Decode(&row); err != nil {
+			return nil, Wrap(err, ErrDB, "decode tag count")
+		}
+		set.add(row.ID.Tag, row.ID.Status, row.Count)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, Wrap(err, ErrDB, "cursor error")
+	}
+	return set.counts(), nil
+}
@@ -0,0 +1,276 @@
+// services/mongoReactionRepository.go
+//
+// Paquete services: implementación de ReactionRepository sobre MongoDB.
+//
+// Convenciones:
+//   - Colección "reactions" con índice único idx_post_kind_client {postId, kind,
+//     clientId}: la inserción duplicada falla y por eso reaccionar es idempotente.
+//   - Colección "reaction_counts" (_id = postId) con {counts.<kind>, total}, actualizada
+//     con $inc (upsert al sumar); el ranking general usa idx_total. Ambos índices los
+//     crea la migración 13.
+//   - Sin transacciones (el despliegue es standalone) marca y contador son dos escrituras:
+//     la marca lleva state "adding"/"removing" mientras su $inc no está confirmado, y cada
+//     $inc registra su opId (_id de la marca + "+" o "-") en ops del contador y se filtra
+//     por ops $ne opId. Un reintento tras un fallo a mitad de camino repite el $inc sin
+//     contarlo dos veces y termina la transición. Una marca sin state está contada.
+//   - changed=true sólo lo reporta quien hizo la transición: el Add cuyo insert creó la
+//     marca y el Remove que la pasó a "removing". Quien encuentra la marca a medias (un
+//     Add o Remove concurrente, o el reintento de uno que falló) termina lo pendiente y
+//     reporta false, así dos Add simultáneos del mismo cliente no cuentan dos cambios.
+//   - ops conserva los últimos maxReactionOps opIds; un reintento posterior a esa ventana
+//     podría contarse de nuevo.
+//   - Mismo manejo de timeout, errores y OpObserver que MongoPostRepository.
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoReactionRepository persiste reacciones en "reactions" y sus contadores en
+// "reaction_counts".
+type MongoReactionRepository struct {
+	col     *mongo.Collection
+	counts  *mongo.Collection
+	timeout time.Duration
+	observe OpObserver
+}
+
+// NewMongoReactionRepository crea un repositorio sobre las colecciones "reactions" y
+// "reaction_counts" de db. timeout limita cada operación (<=0 usa defaultTimeout);
+// observe puede ser nil.
+func NewMongoReactionRepository(db *mongo.Database, timeout time.Duration, observe OpObserver) *MongoReactionRepository {
+	return &MongoReactionRepository{
+		col:     db.Collection("reactions"),
+		counts:  db.Collection("reaction_counts"),
+		timeout: orDefaultTimeout(timeout),
+		observe: observe,
+	}
+}
+
+// Estados de una marca en "reactions"; sin state la marca ya está contada.
+const (
+	reactionAdding   = "adding"
+	reactionRemoving = "removing"
+)
+
+// maxReactionOps acota cuántos opIds recuerda cada contador para deduplicar reintentos.
+const maxReactionOps = 1000
+
+// mongoReaction es la marca tal como se guarda en "reactions".
+type mongoReaction struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	PostID    primitive.ObjectID `bson:"postId"`
+	Kind      string             `bson:"kind"`
+	ClientID  string             `bson:"clientId"`
+	CreatedAt time.Time          `bson:"createdAt"`
+	State     string             `bson:"state,omitempty"`
+}
+
+// Add inserta la marca en estado "adding", suma 1 con $inc y la da por contada. Si la
+// marca ya existía contada no hace nada; si está en "adding" (un Add concurrente o un
+// intento anterior que falló) completa el $inc pendiente sin reportar el cambio, que
+// es de quien la insertó.
+//
+// Errores:
+//   - ErrDB: error del driver.
+func (r *MongoReactionRepository) Add(ctx context.Context, postID primitive.ObjectID, kind, clientID string, at time.Time) (_ bool, err error) {
+	defer r.observe.track("insert", time.Now(), &err)
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	m := mongoReaction{ID: primitive.NewObjectID(), PostID: postID, Kind: kind, ClientID: clientID, CreatedAt: at, State: reactionAdding}
+	_, err = r.col.InsertOne(ctx, m)
+	if mongo.IsDuplicateKeyError(err) {
+		if err := r.col.FindOne(ctx, reactionKey(postID, kind, clientID)).Decode(&m); err != nil {
+			return false, Wrap(err, ErrDB, "find reaction")
+		}
+		switch m.State {
+		case reactionAdding:
+			return false, r.finishAdd(ctx, m)
+		case reactionRemoving:
+			// Un Remove quedó a medias: se completa y la reacción vuelve a crearse.
+			if err := r.finishRemove(ctx, m); err != nil {
+				return false, err
+			}
+			return r.Add(ctx, postID, kind, clientID, at)
+		default:
+			return false, nil
+		}
+	} else if err != nil {
+		return false, Wrap(err, ErrDB, "insert reaction")
+	}
+	if err := r.finishAdd(ctx, m); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Remove pasa la marca a "removing", resta 1 con $inc y la borra. Si no existía no
+// hace nada; si ya estaba en "removing" (un Remove concurrente o un intento anterior
+// que falló) completa lo pendiente sin reportar el cambio.
+//
+// Errores:
+//   - ErrDB: error del driver.
+func (r *MongoReactionRepository) Remove(ctx context.Context, postID primitive.ObjectID, kind, clientID string) (_ bool, err error) {
+	defer r.observe.track("delete", time.Now(), &err)
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var m mongoReaction
+	err = r.col.FindOneAndUpdate(ctx, reactionKey(postID, kind, clientID),
+		bson.M{"$set": bson.M{"state": reactionRemoving}}).Decode(&m)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return false, nil
+	}
+	if err != nil {
+		return false, Wrap(err, ErrDB, "mark reaction removing")
+	}
+	switch m.State {
+	case reactionRemoving:
+		return false, r.finishRemove(ctx, m)
+	case reactionAdding:
+		// El Add anterior no confirmó su $inc: se aplica antes de restar.
+		if err := r.inc(ctx, m, 1); err != nil {
+			return false, err
+		}
+	}
+	if err := r.finishRemove(ctx, m); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// finishAdd aplica el $inc de la marca m y la da por contada.
+func (r *MongoReactionRepository) finishAdd(ctx context.Context, m mongoReaction) error {
+	if err := r.inc(ctx, m, 1); err != nil {
+		return err
+	}
+	_, err := r.col.UpdateOne(ctx, bson.M{"_id": m.ID, "state": reactionAdding}, bson.M{"$unset": bson.M{"state": ""}})
+	return Wrap(err, ErrDB, "confirm reaction")
+}
+
+// finishRemove aplica el $inc negativo de la marca m y la borra.
+func (r *MongoReactionRepository) finishRemove(ctx context.Context, m mongoReaction) error {
+	if err := r.inc(ctx, m, -1); err != nil {
+		return err
+	}
+	_, err := r.col.DeleteOne(ctx, bson.M{"_id": m.ID})
+	return Wrap(err, ErrDB, "delete reaction")
+}
+
+// inc suma delta a counts.<kind> y a total del contador del post de m (lo crea si
+// falta al sumar) una sola vez por marca y signo: el opId queda en ops y un contador
+// que ya lo tiene no coincide con el filtro. Al sumar, esa no coincidencia intenta el
+// upsert y falla por clave duplicada en _id, que aquí significa "ya aplicado".
+func (r *MongoReactionRepository) inc(ctx context.Context, m mongoReaction, delta int64) error {
+	opID := m.ID.Hex() + "+"
+	if delta < 0 {
+		opID = m.ID.Hex() + "-"
+	}
+	update := bson.M{
+		"$inc":  bson.M{"counts." + m.Kind: delta, "total": delta},
+		"$push": bson.M{"ops": bson.M{"$each": bson.A{opID}, "$slice": -maxReactionOps}},
+	}
+	_, err := r.counts.UpdateOne(ctx, bson.M{"_id": m.PostID, "ops": bson.M{"$ne": opID}}, update,
+		options.Update().SetUpsert(delta > 0))
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return Wrap(err, ErrDB, "update reaction count")
+	}
+	return nil
+}
+
+// reactionKey filtra la marca de clientID para postID y kind (idx_post_kind_client).
+func reactionKey(postID primitive.ObjectID, kind, clientID string) bson.M {
+	return bson.M{"postId": postID, "kind": kind, "clientId": clientID}
+}
+
+// Counts lee los contadores de postIDs.
+//
+// Errores:
+//   - ErrDB: error del driver o del cursor.
+func (r *MongoReactionRepository) Counts(ctx context.Context, postIDs []primitive.ObjectID) (_ map[primitive.ObjectID]map[string]int64, err error) {
+	out := map[primitive.ObjectID]map[string]int64{}
+	if len(postIDs) == 0 {
+		return out, nil
+	}
+	items, err := r.find(ctx, bson.M{"_id": bson.M{"$in": postIDs}}, options.Find())
+	if err != nil {
+		return nil, err
+	}
+	for _, rc := range items {
+		out[rc.PostID] = rc.Counts
+	}
+	return out, nil
+}
+
+// Top ordena los contadores por counts.<kind> (o total) descendente y luego por _id.
+// El orden por total usa idx_total; el orden por un tipo no tiene índice propio.
+//
+// Errores:
+//   - ErrDB: error del driver o del cursor.
+func (r *MongoReactionRepository) Top(ctx context.Context, kind string, skip, limit int) ([]models.ReactionCount, error) {
+	field := "total"
+	if kind != "" {
+		field = "counts." + kind
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: field, Value: -1}, {Key: "_id", Value: 1}}).
+		SetSkip(int64(skip))
+	if limit > 0 {
+		opts.SetLimit(int64(limit))
+	}
+	return r.find(ctx, bson.M{field: bson.M{"$gt": 0}}, opts)
+}
+
+// find ejecuta la consulta sobre "reaction_counts" y decodifica todos los contadores.
+func (r *MongoReactionRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) (_ []models.ReactionCount, err error) {
+	defer r.observe.track("find", time.Now(), &err)
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	cur, err := r.counts.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, Wrap(err, ErrDB, "find reaction counts")
+	}
+	defer cur.Close(ctx)
+
+	out := []models.ReactionCount{}
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, Wrap(err, ErrDB, "decode reaction counts")
+	}
+	return out, nil
+}
+
+// DeleteByPosts borra las marcas y los contadores de postIDs.
+//
+// Errores:
+//   - ErrDB: error del driver.
+func (r *MongoReactionRepository) DeleteByPosts(ctx context.Context, postIDs []primitive.ObjectID) (_ int64, err error) {
+	if len(postIDs) == 0 {
+		return 0, nil
+	}
+	defer r.observe.track("delete", time.Now(), &err)
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	res, err := r.col.DeleteMany(ctx, bson.M{"postId": bson.M{"$in": postIDs}})
+	if err != nil {
+		return 0, Wrap(err, ErrDB, "delete reactions")
+	}
+	if _, err := r.counts.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": postIDs}}); err != nil {
+		return 0, Wrap(err, ErrDB, "delete reaction counts")
+	}
+	return res.DeletedCount, nil
+}
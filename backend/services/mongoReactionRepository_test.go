@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// newTestMongoDB abre una base descartable en MONGODB_URI con las migraciones
+// aplicadas y la borra al terminar; sin MONGODB_URI el test se salta.
+func newTestMongoDB(t *testing.T) *mongo.Database {
+	t.Helper()
+	uri := os.Getenv("MONGODB_URI")
+	if uri == "" {
+		t.Skip("MONGODB_URI vacío: sin servidor Mongo")
+	}
+	db, err := ConnectMongo(uri, "blog_test_"+primitive.NewObjectID().Hex())
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = db.Drop(context.Background())
+		_ = db.Client().Disconnect(context.Background())
+	})
+	migrator, err := NewMigrator(db, MongoMigrations(nil))
+	if err != nil {
+		t.Fatalf("migrator: %v", err)
+	}
+	if _, err := migrator.Up(context.Background(), 0); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+func TestMongoReactionRepositoryRetriesPendingMarks(t *testing.T) {
+	db := newTestMongoDB(t)
+	r := NewMongoReactionRepository(db, 0, nil)
+	ctx := context.Background()
+	postID := primitive.NewObjectID()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	count := func() int64 {
+		t.Helper()
+		counts, err := r.Counts(ctx, []primitive.ObjectID{postID})
+		if err != nil {
+			t.Fatalf("counts: %v", err)
+		}
+		return counts[postID]["like"]
+	}
+	mark := func(state string) mongoReaction {
+		t.Helper()
+		m := mongoReaction{ID: primitive.NewObjectID(), PostID: postID, Kind: "like", ClientID: "c1", CreatedAt: now, State: state}
+		if _, err := r.col.InsertOne(ctx, m); err != nil {
+			t.Fatalf("insert mark: %v", err)
+		}
+		return m
+	}
+
+	// Un Add que insertó la marca y falló antes del $inc: el reintento lo completa
+	// sin reportar el cambio.
+	m := mark(reactionAdding)
+	if changed, err := r.Add(ctx, postID, "like", "c1", now); err != nil || changed {
+		t.Fatalf("reintento de add: changed %v %v, want false", changed, err)
+	}
+	if n := count(); n != 1 {
+		t.Fatalf("count tras reintento: %d, want 1", n)
+	}
+	var got mongoReaction
+	if err := r.col.FindOne(ctx, bson.M{"_id": m.ID}).Decode(&got); err != nil || got.State != "" {
+		t.Fatalf("marca tras reintento: %+v %v", got, err)
+	}
+
+	// Un Add que aplicó el $inc pero no confirmó la marca: el reintento no cuenta dos veces.
+	if _, err := r.col.UpdateOne(ctx, bson.M{"_id": m.ID}, bson.M{"$set": bson.M{"state": reactionAdding}}); err != nil {
+		t.Fatalf("reset state: %v", err)
+	}
+	if changed, err := r.Add(ctx, postID, "like", "c1", now); err != nil || changed {
+		t.Fatalf("reintento tras $inc: changed %v %v, want false", changed, err)
+	}
+	if n := count(); n != 1 {
+		t.Fatalf("count tras reintento con $inc aplicado: %d, want 1", n)
+	}
+
+	// Un Remove que marcó "removing" y falló: el reintento borra y resta una sola vez.
+	if _, err := r.col.UpdateOne(ctx, bson.M{"_id": m.ID}, bson.M{"$set": bson.M{"state": reactionRemoving}}); err != nil {
+		t.Fatalf("set removing: %v", err)
+	}
+	if changed, err := r.Remove(ctx, postID, "like", "c1"); err != nil || changed {
+		t.Fatalf("reintento de remove: changed %v %v, want false", changed, err)
+	}
+	if n := count(); n != 0 {
+		t.Fatalf("count tras remove: %d, want 0", n)
+	}
+	if n, err := r.col.CountDocuments(ctx, bson.M{"_id": m.ID}); err != nil || n != 0 {
+		t.Fatalf("marca tras remove: %d %v", n, err)
+	}
+
+	// Un Add sobre un Remove a medias lo completa y vuelve a crear la reacción.
+	mark(reactionRemoving)
+	if _, err := r.counts.UpdateOne(ctx, bson.M{"_id": postID}, bson.M{"$inc": bson.M{"counts.like": 1, "total": 1}}); err != nil {
+		t.Fatalf("count de la marca: %v", err)
+	}
+	if changed, err := r.Add(ctx, postID, "like", "c1", now); err != nil || !changed {
+		t.Fatalf("add sobre remove a medias: changed %v %v, want true", changed, err)
+	}
+	if n := count(); n != 1 {
+		t.Fatalf("count tras add sobre remove a medias: %d, want 1", n)
+	}
+}
+
+func TestMongoReactionRepositoryConcurrentAdd(t *testing.T) {
+	db := newTestMongoDB(t)
+	testConcurrentReactions(t, NewMongoReactionRepository(db, 0, nil), primitive.NewObjectID())
+}
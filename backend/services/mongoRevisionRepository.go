@@ -0,0 +1,167 @@
+// services/mongoRevisionRepository.go
+//
+// Paquete services: implementación de RevisionRepository sobre MongoDB.
+//
+// Convenciones:
+//   - Colección "post_revisions" con índice único {postId:1, revision:-1} (migración 5).
+//   - Mismo manejo de timeout, errores y OpObserver que MongoPostRepository.
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRevisionRepository persiste revisiones en la colección "post_revisions".
+type MongoRevisionRepository struct {
+	col     *mongo.Collection
+	timeout time.Duration
+	observe OpObserver
+}
+
+// NewMongoRevisionRepository crea un repositorio sobre la colección "post_revisions" de db.
+// timeout limita cada operación (<=0 usa defaultTimeout); observe puede ser nil.
+func NewMongoRevisionRepository(db *mongo.Database, timeout time.Duration, observe OpObserver) *MongoRevisionRepository {
+	return &MongoRevisionRepository{col: db.Collection("post_revisions"), timeout: orDefaultTimeout(timeout), observe: observe}
+}
+
+// Create inserta rev; un _id o (postId, revision) duplicado se ignora.
+//
+// Errores:
+//   - ErrDB: error del driver.
+func (r *MongoRevisionRepository) Create(ctx context.Context, rev models.PostRevision) (err error) {
+	defer r.observe.track("insert", time.Now(), &err)
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	if _, err := r.col.InsertOne(ctx, rev); err != nil && !mongo.IsDuplicateKeyError(err) {
+		return Wrap(err, ErrDB, "insert revision")
+	}
+	return nil
+}
+
+// List retorna las revisiones de postID ordenadas por revision descendente.
+//
+// Errores:
+//   - ErrDB: error del driver o del cursor.
+func (r *MongoRevisionRepository) List(ctx context.Context, postID primitive.ObjectID) (_ []models.PostRevision, err error) {
+	defer r.observe.track("find", time.Now(), &err)
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "revision", Value: -1}})
+	cur, err := r.col.Find(ctx, bson.M{"postId": postID}, opts)
+	if err != nil {
+		return nil, Wrap(err, ErrDB, "find revisions")
+	}
+	defer cur.Close(ctx)
+
+	out := []models.PostRevision{}
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, Wrap(err, ErrDB, "decode revisions")
+	}
+	return out, nil
+}
+
+// Get recupera la revisión indicada.
+//
+// Errores:
+//   - ErrNotFound si no existe; ErrDB si falla el driver.
+func (r *MongoRevisionRepository) Get(ctx context.Context, postID primitive.ObjectID, revision int64) (_ models.PostRevision, err error) {
+	defer r.observe.track("find", time.Now(), &err)
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var out models.PostRevision
+	if err := r.col.FindOne(ctx, bson.M{"postId": postID, "revision": revision}).Decode(&out); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return models.PostRevision{}, Wrap(err, ErrNotFound, "revision not found")
+		}
+		return models.PostRevision{}, Wrap(err, ErrDB, "find revision")
+	}
+	return out, nil
+}
+
+// Prune borra con un único DeleteMany las revisiones que exceden keep (a partir de la
+// keep+1-ésima más nueva) o que son anteriores a before.
+//
+// Errores:
+//   - ErrDB: error del driver.
+func (r *MongoRevisionRepository) Prune(ctx context.Context, postID primitive.ObjectID, keep int, before time.Time) (_ int64, err error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var or bson.A
+	if keep > 0 {
+		cutoff, found, err := r.revisionAt(ctx, postID, keep)
+		if err != nil {
+			return 0, err
+		}
+		if found {
+			or = append(or, bson.M{"revision": bson.M{"$lte": cutoff}})
+		}
+	}
+	if !before.IsZero() {
+		or = append(or, bson.M{"createdAt": bson.M{"$lt": before}})
+	}
+	if len(or) == 0 {
+		return 0, nil
+	}
+
+	defer r.observe.track("delete", time.Now(), &err)
+	res, err := r.col.DeleteMany(ctx, bson.M{"postId": postID, "$or": or})
+	if err != nil {
+		return 0, Wrap(err, ErrDB, "prune revisions")
+	}
+	return res.DeletedCount, nil
+}
+
+// revisionAt retorna el número de la revisión en la posición skip (0 = la más nueva).
+func (r *MongoRevisionRepository) revisionAt(ctx context.Context, postID primitive.ObjectID, skip int) (_ int64, _ bool, err error) {
+	defer r.observe.track("find", time.Now(), &err)
+
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: "revision", Value: -1}}).
+		SetSkip(int64(skip)).
+		SetProjection(bson.M{"revision": 1})
+	var doc struct {
+		Revision int64 `bson:"revision"`
+	}
+	if err := r.col.FindOne(ctx, bson.M{"postId": postID}, opts).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return 0, false, nil
+		}
+		return 0, false, Wrap(err, ErrDB, "find revision cutoff")
+	}
+	return doc.Revision, true, nil
+}
+
+// DeleteByPosts borra las revisiones de postIDs.
+//
+// Errores:
+//   - ErrDB: error del driver.
+func (r *MongoRevisionRepository) DeleteByPosts(ctx context.Context, postIDs []primitive.ObjectID) (_ int64, err error) {
+	if len(postIDs) == 0 {
+		return 0, nil
+	}
+	defer r.observe.track("delete", time.Now(), &err)
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	res, err := r.col.DeleteMany(ctx, bson.M{"postId": bson.M{"$in": postIDs}})
+	if err != nil {
+		return 0, Wrap(err, ErrDB, "delete revisions")
+	}
+	return res.DeletedCount, nil
+}
@@ -3,67 +3,68 @@
 // Paquete services: inicialización de la conexión a MongoDB y configuración de índices.
 //
 // Convenciones:
-//   - La variable global DB se inicializa al arrancar la aplicación mediante ConnectMongo.
+//   - ConnectMongo retorna la *mongo.Database; main la inyecta en los repositorios (sin globales).
 //   - Se validan URI y nombre de la base de datos antes de intentar conectar.
 //   - Se aplica un timeout de 10s en la conexión y ping.
-//   - Al establecer la conexión, se crean índices obligatorios en la colección "posts".
+//   - Los índices ya no se crean al conectar: son la migración 1 (ver migrations.go),
+//     que OpenStore aplica al arrancar junto con el resto de migraciones pendientes.
 package services
 
 import (
 	"context"
-	"log"
+	"errors"
+	"log/slog"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
 )
 
-// DB es la referencia global a la base de datos Mongo inicializada en ConnectMongo.
-// Debe ser usada por los servicios para acceder a colecciones.
-var DB *mongo.Database
-
-// ConnectMongo establece la conexión a MongoDB y guarda la referencia global DB.
+// ConnectMongo establece la conexión a MongoDB y retorna la base de datos a utilizar.
 //
 // Parámetros:
 //   - uri: cadena de conexión a MongoDB (ej. "mongodb://localhost:27017").
 //   - dbName: nombre de la base de datos a usar.
 //
 // Comportamiento:
-//   - Valida que uri y dbName no sean cadenas vacías (termina la app con log.Fatal si lo son).
+//   - Valida que uri y dbName no sean cadenas vacías.
 //   - Crea un cliente Mongo con timeout de 10s y realiza un Ping para verificar la conexión.
-//   - Inicializa DB con la base especificada.
-//   - Llama a ensureIndexes para asegurar la existencia de índices requeridos en la colección "posts".
+//   - Instala el monitor de comandos de OpenTelemetry (otelmongo).
+//   - El cliente queda accesible vía db.Client() para desconectarlo al apagar.
 //
 // Errores:
-//   - Si la conexión o el ping fallan, la aplicación termina con log.Fatal.
-//   - Si la creación de índices falla, la aplicación también termina con log.Fatal.
-func ConnectMongo(uri, dbName string) {
+//   - ErrInvalidInput si uri o dbName están vacíos.
+//   - ErrDB si la conexión o el ping fallan.
+func ConnectMongo(uri, dbName string) (*mongo.Database, error) {
 	if uri == "" || dbName == "" {
-		log.Fatal("❌ Mongo config inválida (MONGODB_URI o MONGODB_DB vacíos)")
+		return nil, Wrap(errors.New("MONGODB_URI o MONGODB_DB vacíos"), ErrInvalidInput, "mongo config")
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	// El monitor de comandos crea un span por comando (find, insert, aggregate...)
+	// como hijo del span activo en el ctx de la operación.
+	clientOpts := options.Client().ApplyURI(uri).SetMonitor(otelmongo.NewMonitor())
+	client, err := mongo.Connect(ctx, clientOpts)
 	if err != nil {
-		log.Fatal("❌ Error conectando a Mongo:", err)
+		return nil, Wrap(err, ErrDB, "connect mongo")
 	}
 
 	if err := client.Ping(ctx, nil); err != nil {
-		log.Fatal("❌ Mongo no responde:", err)
+		_ = client.Disconnect(context.Background())
+		return nil, Wrap(err, ErrDB, "ping mongo")
 	}
 
-	DB = client.Database(dbName)
-	log.Println("✅ Conectado a Mongo:", dbName)
-
-	if err := ensureIndexes(DB.Collection("posts")); err != nil {
-		log.Fatal("❌ Error creando índices:", err)
-	}
+	db := client.Database(dbName)
+	slog.Info("conectado a Mongo", slog.String("database", dbName))
+	return db, nil
 }
 
 // ensureIndexes crea los índices necesarios en la colección de posts.
+// Es el Up de la migración 1; CreateMany es idempotente si los índices ya existen.
 //
 // Índices definidos:
 //   - text_title_content: índice de texto en {title, content} para búsquedas con $text.
@@ -72,8 +73,8 @@ func ConnectMongo(uri, dbName string) {
 //
 // Retorna:
 //   - error en caso de fallo en la creación de índices; nil si todo fue correcto.
-func ensureIndexes(col *mongo.Collection) error {
-	_, err := col.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+func ensureIndexes(ctx context.Context, col *mongo.Collection) error {
+	_, err := col.Indexes().CreateMany(ctx, []mongo.IndexModel{
 		{
 			Keys: bson.D{
 				{Key: "title", Value: "text"},
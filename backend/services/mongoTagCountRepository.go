@@ -0,0 +1,98 @@
+// services/mongoTagCountRepository.go
+//
+// Paquete services: implementación de TagCountRepository sobre MongoDB.
+//
+// Convenciones:
+//   - Colección "tag_counts" (_id = etiqueta) con índice idx_folded (migración 11):
+//     Suggest usa una regex anclada al inicio, que recorre sólo ese rango del índice.
+//   - Mismo manejo de timeout, errores y OpObserver que MongoPostRepository.
+package services
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoTagCountRepository persiste contadores en la colección "tag_counts".
+type MongoTagCountRepository struct {
+	col     *mongo.Collection
+	timeout time.Duration
+	observe OpObserver
+}
+
+// NewMongoTagCountRepository crea un repositorio sobre la colección "tag_counts" de db.
+// timeout limita cada operación (<=0 usa defaultTimeout); observe puede ser nil.
+func NewMongoTagCountRepository(db *mongo.Database, timeout time.Duration, observe OpObserver) *MongoTagCountRepository {
+	return &MongoTagCountRepository{col: db.Collection("tag_counts"), timeout: orDefaultTimeout(timeout), observe: observe}
+}
+
+// List retorna todos los contadores.
+//
+// Errores:
+//   - ErrDB si falla el driver.
+func (r *MongoTagCountRepository) List(ctx context.Context) ([]models.TagCount, error) {
+	return r.find(ctx, bson.M{}, options.Find())
+}
+
+// Suggest retorna los contadores cuyo folded empieza con prefix (idx_folded).
+//
+// Errores:
+//   - ErrDB si falla el driver.
+func (r *MongoTagCountRepository) Suggest(ctx context.Context, prefix string, limit int) ([]models.TagCount, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "total", Value: -1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit))
+	return r.find(ctx, bson.M{"folded": primitive.Regex{Pattern: "^" + regexp.QuoteMeta(prefix)}}, opts)
+}
+
+// find ejecuta la consulta y decodifica todos los contadores.
+func (r *MongoTagCountRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) (_ []models.TagCount, err error) {
+	defer r.observe.track("find", time.Now(), &err)
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	cur, err := r.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, Wrap(err, ErrDB, "find tag counts")
+	}
+	out := []models.TagCount{}
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, Wrap(err, ErrDB, "decode tag counts")
+	}
+	return out, nil
+}
+
+// Replace reemplaza los contadores con un único BulkWrite: borra los de tags (todos si
+// tags es nil) y reinserta counts con upsert.
+//
+// Errores:
+//   - ErrDB si falla el driver.
+func (r *MongoTagCountRepository) Replace(ctx context.Context, tags []string, counts []models.TagCount) (err error) {
+	defer r.observe.track("bulkWrite", time.Now(), &err)
+
+	filter := bson.M{}
+	if tags != nil {
+		filter["_id"] = bson.M{"$in": tags}
+	}
+	writes := []mongo.WriteModel{mongo.NewDeleteManyModel().SetFilter(filter)}
+	for _, tc := range counts {
+		writes = append(writes, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": tc.Tag}).SetReplacement(tc).SetUpsert(true))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	if _, err := r.col.BulkWrite(ctx, writes); err != nil {
+		return Wrap(err, ErrDB, "replace tag counts")
+	}
+	return nil
+}
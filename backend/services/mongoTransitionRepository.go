@@ -0,0 +1,93 @@
+// services/mongoTransitionRepository.go
+//
+// Paquete services: implementación de TransitionRepository sobre MongoDB.
+//
+// Convenciones:
+//   - Colección "post_transitions" con índice {postId:1, createdAt:-1} (migración 8).
+//   - Mismo manejo de timeout, errores y OpObserver que MongoPostRepository.
+package services
+
+import (
+	"context"
+	"time"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoTransitionRepository persiste transiciones en la colección "post_transitions".
+type MongoTransitionRepository struct {
+	col     *mongo.Collection
+	timeout time.Duration
+	observe OpObserver
+}
+
+// NewMongoTransitionRepository crea un repositorio sobre la colección "post_transitions"
+// de db. timeout limita cada operación (<=0 usa defaultTimeout); observe puede ser nil.
+func NewMongoTransitionRepository(db *mongo.Database, timeout time.Duration, observe OpObserver) *MongoTransitionRepository {
+	return &MongoTransitionRepository{col: db.Collection("post_transitions"), timeout: orDefaultTimeout(timeout), observe: observe}
+}
+
+// Create inserta t.
+//
+// Errores:
+//   - ErrDB: error del driver.
+func (r *MongoTransitionRepository) Create(ctx context.Context, t models.PostTransition) (err error) {
+	defer r.observe.track("insert", time.Now(), &err)
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	if _, err := r.col.InsertOne(ctx, t); err != nil {
+		return Wrap(err, ErrDB, "insert transition")
+	}
+	return nil
+}
+
+// List retorna las transiciones de postID ordenadas por createdAt descendente
+// (a igual fecha, por _id, que crece con el tiempo de inserción).
+//
+// Errores:
+//   - ErrDB: error del driver o del cursor.
+func (r *MongoTransitionRepository) List(ctx context.Context, postID primitive.ObjectID) (_ []models.PostTransition, err error) {
+	defer r.observe.track("find", time.Now(), &err)
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}})
+	cur, err := r.col.Find(ctx, bson.M{"postId": postID}, opts)
+	if err != nil {
+		return nil, Wrap(err, ErrDB, "find transitions")
+	}
+	defer cur.Close(ctx)
+
+	out := []models.PostTransition{}
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, Wrap(err, ErrDB, "decode transitions")
+	}
+	return out, nil
+}
+
+// DeleteByPosts borra las transiciones de postIDs.
+//
+// Errores:
+//   - ErrDB: error del driver.
+func (r *MongoTransitionRepository) DeleteByPosts(ctx context.Context, postIDs []primitive.ObjectID) (_ int64, err error) {
+	if len(postIDs) == 0 {
+		return 0, nil
+	}
+	defer r.observe.track("delete", time.Now(), &err)
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	res, err := r.col.DeleteMany(ctx, bson.M{"postId": bson.M{"$in": postIDs}})
+	if err != nil {
+		return 0, Wrap(err, ErrDB, "delete transitions")
+	}
+	return res.DeletedCount, nil
+}
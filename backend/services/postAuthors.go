@@ -0,0 +1,170 @@
+// services/postAuthors.go
+//
+// Paquete services: referencia de los posts a su autor.
+//
+// Convenciones:
+//   - Todo post referencia un autor existente (AuthorID); Author es una copia de su
+//     nombre para listar sin consultar "authors" y se actualiza al renombrarlo.
+//   - Un cliente puede indicar el autor por id (authorId) o por nombre (author). El
+//     nombre se busca por su slug, de modo que "Ana García" y "ana garcia" resuelven
+//     al mismo autor; un nombre sin letras ni dígitos transliterables sólo puede
+//     indicarse por id.
+//   - Los posts anteriores a la colección de autores se migran con backfillAuthors.
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fallbackAuthorSlug se usa cuando el nombre no tiene letras ni dígitos transliterables.
+const fallbackAuthorSlug = "author"
+
+// errUnknownAuthor es la causa de un post que referencia un autor inexistente.
+var errUnknownAuthor = errors.New("unknown author")
+
+// authorSlug retorna el slug derivado de name; ok=false si name no tiene letras ni
+// dígitos transliterables (el slug es entonces fallbackAuthorSlug).
+func authorSlug(name string) (slug string, ok bool) {
+	words := slugWords(name)
+	if words == "" {
+		return fallbackAuthorSlug, false
+	}
+	return truncateSlug(words, maxSlugLen), true
+}
+
+// resolveAuthor valida el autor de p y completa AuthorID y Author (nombre vigente).
+//
+// Reglas:
+//   - Con AuthorID distinto de cero se usa ese autor, salvo que sea el de current y
+//     p traiga otro nombre no vacío: entonces se busca por nombre (cambio de autor por
+//     nombre).
+//   - Con AuthorID cero y el mismo nombre que current se conserva el autor actual.
+//   - En otro caso se busca el autor cuyo slug coincide con el derivado de p.Author.
+//
+// Errores:
+//   - ErrInvalidInput si el autor no existe; ErrDB si falla el almacenamiento.
+func (s *PostService) resolveAuthor(ctx context.Context, current models.Post, p *models.Post) error {
+	id := p.AuthorID
+	if id == current.AuthorID && p.Author != "" && p.Author != current.Author {
+		id = primitive.NilObjectID
+	}
+	if id.IsZero() && p.Author == current.Author {
+		id = current.AuthorID
+	}
+
+	var (
+		a   models.Author
+		err error
+	)
+	switch slug, ok := authorSlug(p.Author); {
+	case !id.IsZero():
+		a, err = s.authors.Get(ctx, id)
+	case ok:
+		a, err = s.authors.GetBySlug(ctx, slug)
+	default:
+		return Wrap(errUnknownAuthor, ErrInvalidInput, "author")
+	}
+	if errors.Is(err, ErrNotFound) {
+		return Wrap(errUnknownAuthor, ErrInvalidInput, "author")
+	}
+	if err != nil {
+		return logDBError(ctx, "get author", err)
+	}
+	p.AuthorID = a.ID
+	p.Author = a.Name
+	return nil
+}
+
+// authorGroup reúne los posts sin autor referenciado que comparten nombre normalizado.
+type authorGroup struct {
+	posts  []int
+	counts map[string]int
+	names  []string // variantes en orden de aparición
+}
+
+// backfillAuthors asigna autor a los posts de items que no referencian uno de
+// existing y retorna los autores a crear y los índices de items modificados.
+//
+// Reglas:
+//   - Los nombres se agrupan por slug ("Ana García" y "Ana Garcia" son el mismo autor);
+//     los que no tienen slug propio, por nombre en minúsculas.
+//   - Un grupo usa el autor existente con ese slug o, si no hay, crea uno con el nombre
+//     más usado del grupo (a igual uso, el que aparece primero en items).
+//   - Los autores creados reciben un slug libre (sufijo -2, -3... si hace falta).
+//   - Author de cada post modificado queda con el nombre del autor asignado.
+//   - Los posts sin nombre de autor quedan sin referencia.
+//
+// items debe venir ordenado del más viejo al más nuevo para que el resultado sea estable.
+func backfillAuthors(existing []models.Author, items []models.Post, now time.Time) ([]models.Author, []int) {
+	known := make(map[primitive.ObjectID]bool, len(existing))
+	bySlug := make(map[string]models.Author, len(existing))
+	taken := make(map[string]bool, len(existing))
+	for _, a := range existing {
+		known[a.ID] = true
+		bySlug[a.Slug] = a
+		taken[a.Slug] = true
+	}
+
+	groups := map[string]*authorGroup{}
+	var keys []string
+	for i, post := range items {
+		name := strings.TrimSpace(post.Author)
+		if name == "" || (!post.AuthorID.IsZero() && known[post.AuthorID]) {
+			continue
+		}
+		key, ok := authorSlug(name)
+		if !ok {
+			key = "\x00" + strings.ToLower(name)
+		}
+		g := groups[key]
+		if g == nil {
+			g = &authorGroup{counts: map[string]int{}}
+			groups[key] = g
+			keys = append(keys, key)
+		}
+		if g.counts[name] == 0 {
+			g.names = append(g.names, name)
+		}
+		g.counts[name]++
+		g.posts = append(g.posts, i)
+	}
+
+	var (
+		created []models.Author
+		changed []int
+	)
+	for _, key := range keys {
+		g := groups[key]
+		a, ok := bySlug[key]
+		if !ok {
+			name := g.names[0]
+			for _, n := range g.names[1:] {
+				if g.counts[n] > g.counts[name] {
+					name = n
+				}
+			}
+			base, _ := authorSlug(name)
+			slug := base
+			for n := 2; taken[slug]; n++ {
+				slug = slugCandidate(base, n)
+			}
+			taken[slug] = true
+			a = models.Author{ID: primitive.NewObjectID(), Name: name, Slug: slug, CreatedAt: now}
+			created = append(created, a)
+		}
+		for _, i := range g.posts {
+			items[i].AuthorID = a.ID
+			items[i].Author = a.Name
+			changed = append(changed, i)
+		}
+	}
+	sort.Ints(changed)
+	return created, changed
+}
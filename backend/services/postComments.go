@@ -0,0 +1,40 @@
+// services/postComments.go
+//
+// Paquete services: cantidad de comentarios en los listados de posts.
+//
+// Convenciones:
+//   - Post.CommentCount no se persiste: se calcula al listar con una sola consulta
+//     agrupada (CommentRepository.CountByPosts) para los posts de la página.
+//   - Sólo cuentan los comentarios aprobados, los mismos que muestra GET
+//     /api/posts/:id/comments.
+package services
+
+import (
+	"context"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// countComments completa CommentCount en items.
+//
+// Errores:
+//   - ErrDB si falla el almacenamiento.
+func (s *PostService) countComments(ctx context.Context, items []models.Post) error {
+	if len(items) == 0 {
+		return nil
+	}
+	ids := make([]primitive.ObjectID, len(items))
+	for i, p := range items {
+		ids[i] = p.ID
+	}
+	counts, err := s.comments.CountByPosts(ctx, ids, models.CommentApproved)
+	if err != nil {
+		return logDBError(ctx, "count comments", err)
+	}
+	for i := range items {
+		n := counts[items[i].ID]
+		items[i].CommentCount = &n
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+// services/postDiff.go
+//
+// Paquete services: diff entre dos versiones de un post (GET /api/posts/:id/diff).
+//
+// Convenciones:
+//   - Una versión es una revisión del historial o, si coincide con Post.Version, el post
+//     vigente; así se puede comparar cualquier revisión con el estado actual.
+//   - title se compara por palabras, content por líneas (hunks con contexto, con el
+//     detalle por palabras de cada línea reemplazada) y tags como conjuntos; author y
+//     published se informan como valor anterior/nuevo.
+//   - El formato unificado (PostDiff.Unified) trata cada campo como un archivo.
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// WordDiff es el diff por palabras de un campo de texto corto.
+//
+// Campos:
+//   - Changed: si el texto difiere.
+//   - Segments: texto completo en tramos equal/delete/insert (vacío si no cambió).
+type WordDiff struct {
+	Changed  bool          `json:"changed"`
+	Segments []DiffSegment `json:"segments,omitempty"`
+}
+
+// LineDiff es el diff por líneas de un campo de texto largo.
+//
+// Campos:
+//   - Changed: si el texto difiere.
+//   - Hunks: bloques de cambios con diffContextLines líneas de contexto; las líneas
+//     reemplazadas traen Words (ver addWordDiffs).
+type LineDiff struct {
+	Changed bool       `json:"changed"`
+	Hunks   []DiffHunk `json:"hunks,omitempty"`
+}
+
+// TagsDiff son las etiquetas agregadas y quitadas (en el orden en que aparecen).
+type TagsDiff struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+// ValueChange es el cambio de un campo escalar.
+type ValueChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// PostDiff es la diferencia estructurada entre dos versiones de un post.
+//
+// Campos:
+//   - PostID, From, To: post y versiones comparadas.
+//   - Title (por palabras), Content (por líneas), Tags (conjuntos).
+//   - Author, Published: presentes sólo si cambiaron.
+type PostDiff struct {
+	PostID    primitive.ObjectID `json:"postId"`
+	From      int64              `json:"from"`
+	To        int64              `json:"to"`
+	Title     WordDiff           `json:"title"`
+	Content   LineDiff           `json:"content"`
+	Tags      TagsDiff           `json:"tags"`
+	Author    *ValueChange       `json:"author,omitempty"`
+	Published *ValueChange       `json:"published,omitempty"`
+
+	before, after models.PostRevision
+}
+
+// DiffPost compara dos versiones de un Post.
+//
+// Parámetros:
+//   - ctx: contexto de cancelación/timeout.
+//   - idHex: ObjectID en hex del post.
+//   - from: versión de origen; <=0 = la revisión más nueva anterior a to.
+//   - to: versión de destino; <=0 = la versión vigente.
+//
+// Retornos:
+//   - PostDiff de from a to (from puede ser mayor que to: el diff resulta invertido).
+//   - error con sentinelas: ErrInvalidID, ErrNotFound (post inexistente o en la papelera,
+//     versión fuera del historial o sin revisión anterior a to), ErrDB.
+func (s *PostService) DiffPost(ctx context.Context, idHex string, from, to int64) (_ PostDiff, err error) {
+	ctx, span := startSpan(ctx, "PostService.DiffPost", attribute.String("post.id", idHex))
+	defer endSpan(span, &err)
+
+	current, err := s.getVisible(ctx, idHex)
+	if err != nil {
+		return PostDiff{}, err
+	}
+	if to <= 0 {
+		to = current.Version
+	}
+	if from <= 0 {
+		if from, err = s.previousRevision(ctx, current.ID, to); err != nil {
+			return PostDiff{}, err
+		}
+	}
+	span.SetAttributes(attribute.Int64("diff.from", from), attribute.Int64("diff.to", to))
+
+	before, err := s.postVersion(ctx, current, from)
+	if err != nil {
+		return PostDiff{}, err
+	}
+	after, err := s.postVersion(ctx, current, to)
+	if err != nil {
+		return PostDiff{}, err
+	}
+	return diffRevisions(before, after), nil
+}
+
+// previousRevision retorna el número de la revisión más nueva anterior a version.
+func (s *PostService) previousRevision(ctx context.Context, postID primitive.ObjectID, version int64) (int64, error) {
+	revs, err := s.revisions.List(ctx, postID)
+	if err != nil {
+		return 0, logDBError(ctx, "list revisions", err)
+	}
+	for _, rev := range revs {
+		if rev.Revision < version {
+			return rev.Revision, nil
+		}
+	}
+	return 0, Wrap(fmt.Errorf("no revision before %d", version), ErrNotFound, "revision not found")
+}
+
+// postVersion retorna el estado de current en version: el post vigente o una revisión.
+func (s *PostService) postVersion(ctx context.Context, current models.Post, version int64) (models.PostRevision, error) {
+	if version == current.Version {
+		return models.PostRevision{
+			PostID:      current.ID,
+			Revision:    current.Version,
+			Title:       current.Title,
+			Author:      current.Author,
+			AuthorID:    current.AuthorID,
+			Content:     current.Content,
+			Tags:        current.Tags,
+			Published:   current.Published,
+			PublishedAt: current.PublishedAt,
+		}, nil
+	}
+	rev, err := s.revisions.Get(ctx, current.ID, version)
+	return rev, logDBError(ctx, "get revision", err)
+}
+
+// diffRevisions arma el PostDiff entre dos estados.
+func diffRevisions(before, after models.PostRevision) PostDiff {
+	d := PostDiff{
+		PostID: before.PostID,
+		From:   before.Revision,
+		To:     after.Revision,
+		Tags:   TagsDiff{Added: subtractStrings(after.Tags, before.Tags), Removed: subtractStrings(before.Tags, after.Tags)},
+		before: before,
+		after:  after,
+	}
+	if segs := diffTokens(splitDiffWords(before.Title), splitDiffWords(after.Title)); changedScript(segs) {
+		d.Title = WordDiff{Changed: true, Segments: mergeSegments(segs)}
+	}
+	if hunks := buildHunks(diffTokens(splitLines(before.Content), splitLines(after.Content)), diffContextLines); len(hunks) > 0 {
+		addWordDiffs(hunks)
+		d.Content = LineDiff{Changed: true, Hunks: hunks}
+	}
+	if before.Author != after.Author {
+		d.Author = &ValueChange{From: before.Author, To: after.Author}
+	}
+	if before.Published != after.Published {
+		d.Published = &ValueChange{From: before.Published, To: after.Published}
+	}
+	return d
+}
+
+// changedScript indica si el script contiene alguna edición.
+func changedScript(segs []DiffSegment) bool {
+	for _, s := range segs {
+		if s.Op != DiffEqual {
+			return true
+		}
+	}
+	return false
+}
+
+// subtractStrings retorna los valores de a que no están en b, sin repetir.
+func subtractStrings(a, b []string) []string {
+	out := []string{}
+	for _, v := range a {
+		if !containsString(b, v) && !containsString(out, v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Unified retorna el diff en formato unificado: una sección por campo modificado
+// (title, author, content, tags una por línea y published), con encabezados
+// "--- <campo>@v<from>" / "+++ <campo>@v<to>". Vacío si no hay cambios.
+func (d PostDiff) Unified() string {
+	var sb strings.Builder
+	section := func(field string, before, after []string) {
+		writeUnified(&sb, field+"@v"+strconv.FormatInt(d.From, 10), field+"@v"+strconv.FormatInt(d.To, 10), before, after)
+	}
+	section("title", splitLines(d.before.Title), splitLines(d.after.Title))
+	section("author", splitLines(d.before.Author), splitLines(d.after.Author))
+	section("content", splitLines(d.before.Content), splitLines(d.after.Content))
+	section("tags", d.before.Tags, d.after.Tags)
+	section("published", []string{strconv.FormatBool(d.before.Published)}, []string{strconv.FormatBool(d.after.Published)})
+	return sb.String()
+}
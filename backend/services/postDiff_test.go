@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"blog-api/models"
+)
+
+func TestDiffPostContentByWords(t *testing.T) {
+	tp := newTestPosts(t)
+	ctx := context.Background()
+	oid, err := tp.svc.CreatePost(ctx, models.Post{
+		Title: "Introducción a MongoDB", Content: "Primera línea.\nLa migración usa índices.\nÚltima línea.",
+		AuthorID: tp.author.ID, Tags: []string{"go"},
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	id := oid.Hex()
+	if _, err := tp.svc.UpdatePostByID(ctx, id, models.Post{
+		Title: "Introducción a MongoDB con Go", Content: "Primera línea.\nLa migración crea índices.\nÚltima línea.",
+		AuthorID: tp.author.ID, Tags: []string{"go", "mongo"},
+	}, "ana", nil); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	d, err := tp.svc.DiffPost(ctx, id, 0, 0)
+	if err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	if d.From != 1 || d.To != 2 || !d.Title.Changed || !d.Content.Changed {
+		t.Fatalf("diff: from %d to %d title %v content %v", d.From, d.To, d.Title.Changed, d.Content.Changed)
+	}
+	if len(d.Tags.Added) != 1 || d.Tags.Added[0] != "mongo" || len(d.Tags.Removed) != 0 {
+		t.Fatalf("tags: %+v", d.Tags)
+	}
+
+	var deleted, inserted []string
+	for _, l := range d.Content.Hunks[0].Lines {
+		for _, w := range l.Words {
+			switch w.Op {
+			case DiffDelete:
+				deleted = append(deleted, w.Text)
+			case DiffInsert:
+				inserted = append(inserted, w.Text)
+			}
+		}
+	}
+	if strings.Join(deleted, "|") != "usa" || strings.Join(inserted, "|") != "crea" {
+		t.Fatalf("palabras: borradas %q insertadas %q", deleted, inserted)
+	}
+
+	want := "--- title@v1\n+++ title@v2\n@@ -1 +1 @@\n-Introducción a MongoDB\n+Introducción a MongoDB con Go\n" +
+		"--- content@v1\n+++ content@v2\n@@ -1,3 +1,3 @@\n Primera línea.\n-La migración usa índices.\n+La migración crea índices.\n Última línea.\n" +
+		"--- tags@v1\n+++ tags@v2\n@@ -1 +1,2 @@\n go\n+mongo\n"
+	if got := d.Unified(); got != want {
+		t.Fatalf("unified:\n%s\nwant:\n%s", got, want)
+	}
+}
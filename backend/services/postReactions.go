@@ -0,0 +1,48 @@
+// services/postReactions.go
+//
+// Paquete services: reacciones por tipo en las lecturas de posts.
+//
+// Convenciones:
+//   - Post.Reactions no se persiste: se completa en GET por id o slug y en los listados
+//     con una sola consulta (ReactionRepository.Counts) para los posts pedidos.
+//   - Se muestran todos los tipos configurados (PostServiceOptions.ReactionKinds), con 0
+//     si no hay reacciones; los tipos que ya no están configurados no se muestran.
+package services
+
+import (
+	"context"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// attachReactions completa Reactions en items.
+//
+// Errores:
+//   - ErrDB si falla el almacenamiento.
+func (s *PostService) attachReactions(ctx context.Context, items []models.Post) error {
+	if len(items) == 0 || len(s.opts.ReactionKinds) == 0 {
+		return nil
+	}
+	ids := make([]primitive.ObjectID, len(items))
+	for i, p := range items {
+		ids[i] = p.ID
+	}
+	counts, err := s.reactions.Counts(ctx, ids)
+	if err != nil {
+		return logDBError(ctx, "count reactions", err)
+	}
+	for i := range items {
+		items[i].Reactions = reactionsByKind(s.opts.ReactionKinds, counts[items[i].ID])
+	}
+	return nil
+}
+
+// reactionsByKind retorna counts restringido a kinds, con 0 en los tipos sin reacciones.
+func reactionsByKind(kinds []string, counts map[string]int64) map[string]int64 {
+	out := make(map[string]int64, len(kinds))
+	for _, kind := range kinds {
+		out[kind] = counts[kind]
+	}
+	return out
+}
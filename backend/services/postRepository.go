@@ -0,0 +1,118 @@
+// services/postRepository.go
+//
+// Paquete services: contrato de persistencia para la entidad Post.
+//
+// Convenciones:
+//   - PostService depende únicamente de PostRepository; nunca de un driver concreto.
+//   - Las implementaciones envuelven sus errores con sentinelas (ErrDB, ErrNotFound, ...).
+//   - Las reglas de negocio (timestamps, PublishedAt, paginación) viven en PostService,
+//     no en los repositorios.
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// errVersionMismatch es la causa usada cuando expectedVersion no coincide con la almacenada.
+var errVersionMismatch = errors.New("version mismatch")
+
+// PostRepository define las operaciones de almacenamiento de posts.
+//
+// Implementaciones:
+//   - MongoPostRepository: colección "posts" en MongoDB.
+//   - MemoryPostRepository: mapa en memoria (tests y desarrollo sin Mongo).
+//
+// Errores esperados:
+//   - ErrNotFound si el documento no existe (Get, Update, SetDeleted, Delete).
+//   - ErrVersionMismatch (un ErrConflict) si expectedVersion no coincide con la versión
+//     almacenada (Update, Delete).
+//   - ErrConflict si el slug ya pertenece a otro post (Create, Update; índice único).
+//   - ErrDB ante fallas del almacenamiento.
+//
+// Papelera:
+//   - Get, GetBySlug y Update no distinguen posts eliminados (deletedAt != nil); PostService decide.
+//   - List, AggregateByTag y CountTags excluyen los eliminados; List con Deleted=true
+//     lista sólo éstos.
+//
+// Concurrencia:
+//   - Update, UpdateTags y Delete comparan la versión y escriben en una sola operación
+//     atómica; expectedVersion 0 omite la comparación.
+//   - UpdateTags modifica las etiquetas sin reescribir el arreglo completo ($addToSet /
+//     $pull en Mongo), de modo que ediciones concurrentes de tags no se pisan.
+type PostRepository interface {
+	// Create persiste p tal cual (timestamps ya fijados) y retorna su ObjectID.
+	Create(ctx context.Context, p models.Post) (primitive.ObjectID, error)
+	// Get recupera un post por id.
+	Get(ctx context.Context, id primitive.ObjectID) (models.Post, error)
+	// GetBySlug recupera el post cuyo slug vigente es slug o, si no hay, el que lo tuvo
+	// antes (oldSlugs).
+	GetBySlug(ctx context.Context, slug string) (models.Post, error)
+	// Update aplica title, slug, oldSlugs, author, authorId, content, tags, published,
+	// publishAt, unpublishAt (nil los borra), updatedAt y, si viene definido, publishedAt;
+	// incrementa version y retorna el documento resultante.
+	Update(ctx context.Context, id primitive.ObjectID, p models.Post, expectedVersion int64) (models.Post, error)
+	// UpdateTags agrega (sin duplicar) y/o quita etiquetas, fija updatedAt e incrementa
+	// version; retorna el documento resultante.
+	UpdateTags(ctx context.Context, id primitive.ObjectID, change TagChange, updatedAt time.Time, expectedVersion int64) (models.Post, error)
+	// SetDeleted envía el post a la papelera (deletedAt/deletedBy) o, con deletedAt nil,
+	// lo restaura; incrementa version y retorna el documento resultante.
+	SetDeleted(ctx context.Context, id primitive.ObjectID, deletedAt *time.Time, deletedBy string, expectedVersion int64) (models.Post, error)
+	// PurgeDeleted elimina definitivamente los posts con deletedAt anterior a before
+	// y retorna sus ids (para borrar en cascada sus datos asociados).
+	PurgeDeleted(ctx context.Context, before time.Time) ([]primitive.ObjectID, error)
+	// Delete elimina un post por id.
+	Delete(ctx context.Context, id primitive.ObjectID, expectedVersion int64) error
+	// List retorna la página solicitada y el total de documentos que cumplen el filtro.
+	// p llega normalizado (Page/Limit válidos) desde PostService.
+	List(ctx context.Context, p ListPostsParams) ([]models.Post, int64, error)
+	// AggregateByTag retorna el top-N de etiquetas por cantidad de posts; status vacío
+	// no filtra por estado editorial.
+	AggregateByTag(ctx context.Context, limit int, onlyPublished *bool, status string) ([]TagMetric, error)
+	// CountByAuthor cuenta los posts que referencian authorID, incluidos los de la papelera.
+	CountByAuthor(ctx context.Context, authorID primitive.ObjectID) (int64, error)
+	// SetAuthorName copia name en los posts de authorID que tienen otro nombre, fija
+	// updatedAt e incrementa su version; retorna cuántos cambió.
+	SetAuthorName(ctx context.Context, authorID primitive.ObjectID, name string, updatedAt time.Time) (int64, error)
+	// ReplaceTags reemplaza las etiquetas from por to en todos los posts que tengan
+	// alguna (incluidos los de la papelera), sin duplicar to; fija updatedAt, incrementa
+	// version y retorna cuántos posts cambió.
+	ReplaceTags(ctx context.Context, from []string, to string, updatedAt time.Time) (int64, error)
+	// CountTags cuenta, por etiqueta y estado editorial, los posts fuera de la papelera
+	// que tienen alguna de tags (todas las etiquetas si tags es nil); retorna sólo Tag y
+	// Statuses de cada contador.
+	CountTags(ctx context.Context, tags []string) ([]models.TagCount, error)
+}
+
+// TagChange describe una modificación incremental de las etiquetas de un post.
+//
+// Campos:
+//   - Add: etiquetas a agregar al final si no están presentes.
+//   - Remove: etiquetas a quitar (todas sus apariciones).
+//
+// Mongo no admite $addToSet y $pull sobre el mismo campo en una sola actualización:
+// PostService nunca combina ambos en un mismo TagChange.
+type TagChange struct {
+	Add    []string
+	Remove []string
+}
+
+// apply retorna tags con el cambio aplicado, con la misma semántica que $pull + $addToSet.
+func (ch TagChange) apply(tags []string) []string {
+	out := make([]string, 0, len(tags)+len(ch.Add))
+	for _, t := range tags {
+		if !containsString(ch.Remove, t) {
+			out = append(out, t)
+		}
+	}
+	for _, t := range ch.Add {
+		if !containsString(out, t) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
@@ -0,0 +1,388 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// repoFixture es un Store de un driver con un autor creado, para correr el mismo caso
+// sobre cada implementación de los repositorios.
+type repoFixture struct {
+	store  *Store
+	author models.Author
+	now    time.Time
+}
+
+// forEachStore corre fn como subtest sobre un Store "memory" y uno "sqlite" en memoria
+// (Mongo necesita un servidor y no se cubre aquí).
+func forEachStore(t *testing.T, fn func(t *testing.T, f *repoFixture)) {
+	t.Helper()
+	for _, driver := range []string{DriverMemory, DriverSQLite} {
+		t.Run(driver, func(t *testing.T) {
+			opts := StoreOptions{Driver: driver}
+			if driver == DriverSQLite {
+				opts.SQLitePath = ":memory:"
+			}
+			store, err := OpenStore(opts)
+			if err != nil {
+				t.Fatalf("open %s: %v", driver, err)
+			}
+			t.Cleanup(func() { _ = store.Close(context.Background()) })
+
+			now := time.Now().UTC().Truncate(time.Second)
+			author := models.Author{Name: "Ana García", Slug: "ana-garcia", CreatedAt: now}
+			if author.ID, err = store.Authors.Create(context.Background(), author); err != nil {
+				t.Fatalf("create author: %v", err)
+			}
+			fn(t, &repoFixture{store: store, author: author, now: now})
+		})
+	}
+}
+
+// post retorna un post del autor del fixture listo para Create.
+func (f *repoFixture) post(title, slug, content string, published bool, tags ...string) models.Post {
+	return models.Post{
+		Title: title, Slug: slug, Author: f.author.Name, AuthorID: f.author.ID, Content: content,
+		Tags: tags, Published: published, Status: statusFromPublished(published), CreatedAt: f.now, Version: 1,
+	}
+}
+
+// create persiste p y retorna su id.
+func (f *repoFixture) create(t *testing.T, p models.Post) primitive.ObjectID {
+	t.Helper()
+	id, err := f.store.Posts.Create(context.Background(), p)
+	if err != nil {
+		t.Fatalf("create %q: %v", p.Title, err)
+	}
+	return id
+}
+
+func TestPostRepositoryCreateGet(t *testing.T) {
+	forEachStore(t, func(t *testing.T, f *repoFixture) {
+		ctx := context.Background()
+		id := f.create(t, f.post("Introducción a MongoDB", "introduccion-a-mongodb", "contenido", true, "mongo", "go"))
+
+		got, err := f.store.Posts.Get(ctx, id)
+		if err != nil {
+			t.Fatalf("get: %v", err)
+		}
+		if got.Title != "Introducción a MongoDB" || got.Slug != "introduccion-a-mongodb" || got.Version != 1 ||
+			got.AuthorID != f.author.ID || got.Status != models.StatusPublished || !got.CreatedAt.Equal(f.now) {
+			t.Fatalf("get: %+v", got)
+		}
+		if len(got.Tags) != 2 || got.Tags[0] != "mongo" || got.Tags[1] != "go" {
+			t.Fatalf("tags en orden: %v", got.Tags)
+		}
+
+		bySlug, err := f.store.Posts.GetBySlug(ctx, "introduccion-a-mongodb")
+		if err != nil || bySlug.ID != id {
+			t.Fatalf("get by slug: %v %v", bySlug.ID, err)
+		}
+		if _, err := f.store.Posts.Get(ctx, primitive.NewObjectID()); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("get inexistente: %v, want ErrNotFound", err)
+		}
+		if _, err := f.store.Posts.GetBySlug(ctx, "no-existe"); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("get by slug inexistente: %v, want ErrNotFound", err)
+		}
+
+		_, err = f.store.Posts.Create(ctx, f.post("Otro", "introduccion-a-mongodb", "contenido", false))
+		if !errors.Is(err, ErrConflict) || errors.Is(err, ErrVersionMismatch) {
+			t.Fatalf("slug duplicado: %v, want ErrConflict sin ErrVersionMismatch", err)
+		}
+	})
+}
+
+func TestPostRepositoryUpdateVersion(t *testing.T) {
+	forEachStore(t, func(t *testing.T, f *repoFixture) {
+		ctx := context.Background()
+		id := f.create(t, f.post("Primer post", "primer-post", "uno", false))
+		f.create(t, f.post("Segundo post", "segundo-post", "dos", false))
+
+		p := f.post("Primer post editado", "primer-post-editado", "uno bis", false, "go")
+		p.OldSlugs = []string{"primer-post"}
+		updatedAt := f.now.Add(time.Minute)
+		p.UpdatedAt = &updatedAt
+		got, err := f.store.Posts.Update(ctx, id, p, 1)
+		if err != nil {
+			t.Fatalf("update: %v", err)
+		}
+		if got.Version != 2 || got.Slug != "primer-post-editado" || got.Content != "uno bis" || len(got.Tags) != 1 {
+			t.Fatalf("update: %+v", got)
+		}
+		if old, err := f.store.Posts.GetBySlug(ctx, "primer-post"); err != nil || old.ID != id {
+			t.Fatalf("slug anterior: %v %v", old.ID, err)
+		}
+
+		if _, err := f.store.Posts.Update(ctx, id, p, 1); !errors.Is(err, ErrVersionMismatch) {
+			t.Fatalf("versión vencida: %v, want ErrVersionMismatch", err)
+		}
+		if _, err := f.store.Posts.Update(ctx, primitive.NewObjectID(), p, 1); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("post inexistente: %v, want ErrNotFound", err)
+		}
+		p.Slug = "segundo-post"
+		if _, err := f.store.Posts.Update(ctx, id, p, 2); !errors.Is(err, ErrConflict) || errors.Is(err, ErrVersionMismatch) {
+			t.Fatalf("slug de otro post: %v, want ErrConflict sin ErrVersionMismatch", err)
+		}
+		p.Slug = "primer-post-editado"
+		if got, err := f.store.Posts.Update(ctx, id, p, 0); err != nil || got.Version != 3 {
+			t.Fatalf("sin precondición: version %d, %v", got.Version, err)
+		}
+	})
+}
+
+func TestPostRepositoryUpdateTags(t *testing.T) {
+	forEachStore(t, func(t *testing.T, f *repoFixture) {
+		ctx := context.Background()
+		id := f.create(t, f.post("Primer post", "primer-post", "uno", false, "go", "mongo"))
+
+		got, err := f.store.Posts.UpdateTags(ctx, id, TagChange{Add: []string{"sqlite", "go"}}, f.now, 1)
+		if err != nil {
+			t.Fatalf("add: %v", err)
+		}
+		if got.Version != 2 || len(got.Tags) != 3 || got.Tags[2] != "sqlite" {
+			t.Fatalf("add: version %d tags %v", got.Version, got.Tags)
+		}
+		got, err = f.store.Posts.UpdateTags(ctx, id, TagChange{Remove: []string{"go", "no-está"}}, f.now, 2)
+		if err != nil {
+			t.Fatalf("remove: %v", err)
+		}
+		if len(got.Tags) != 2 || got.Tags[0] != "mongo" || got.Tags[1] != "sqlite" {
+			t.Fatalf("remove: tags %v", got.Tags)
+		}
+		if _, err := f.store.Posts.UpdateTags(ctx, id, TagChange{Add: []string{"x"}}, f.now, 2); !errors.Is(err, ErrVersionMismatch) {
+			t.Fatalf("versión vencida: %v, want ErrVersionMismatch", err)
+		}
+		if _, err := f.store.Posts.UpdateTags(ctx, primitive.NewObjectID(), TagChange{Add: []string{"x"}}, f.now, 1); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("post inexistente: %v, want ErrNotFound", err)
+		}
+	})
+}
+
+func TestPostRepositoryDelete(t *testing.T) {
+	forEachStore(t, func(t *testing.T, f *repoFixture) {
+		ctx := context.Background()
+		id := f.create(t, f.post("Primer post", "primer-post", "uno", false))
+
+		if err := f.store.Posts.Delete(ctx, id, 3); !errors.Is(err, ErrVersionMismatch) {
+			t.Fatalf("versión vencida: %v, want ErrVersionMismatch", err)
+		}
+		if err := f.store.Posts.Delete(ctx, primitive.NewObjectID(), 1); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("post inexistente: %v, want ErrNotFound", err)
+		}
+		if err := f.store.Posts.Delete(ctx, id, 1); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+		if _, err := f.store.Posts.Get(ctx, id); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("get tras delete: %v, want ErrNotFound", err)
+		}
+	})
+}
+
+func TestPostRepositoryTrashAndPurge(t *testing.T) {
+	forEachStore(t, func(t *testing.T, f *repoFixture) {
+		ctx := context.Background()
+		old := f.create(t, f.post("Viejo", "viejo", "uno", true, "go"))
+		recent := f.create(t, f.post("Reciente", "reciente", "dos", true, "go"))
+		kept := f.create(t, f.post("Vigente", "vigente", "tres", true, "go"))
+
+		oldAt, recentAt := f.now.Add(-48*time.Hour), f.now.Add(-time.Hour)
+		deleted, err := f.store.Posts.SetDeleted(ctx, old, &oldAt, "ana", 1)
+		if err != nil {
+			t.Fatalf("set deleted: %v", err)
+		}
+		if deleted.Version != 2 || deleted.DeletedAt == nil || deleted.DeletedBy != "ana" {
+			t.Fatalf("set deleted: %+v", deleted)
+		}
+		if _, err := f.store.Posts.SetDeleted(ctx, recent, &recentAt, "ana", 7); !errors.Is(err, ErrVersionMismatch) {
+			t.Fatalf("versión vencida: %v, want ErrVersionMismatch", err)
+		}
+		if _, err := f.store.Posts.SetDeleted(ctx, recent, &recentAt, "ana", 1); err != nil {
+			t.Fatalf("set deleted: %v", err)
+		}
+
+		items, total, err := f.store.Posts.List(ctx, ListPostsParams{Page: 1, Limit: 10})
+		if err != nil || total != 1 || len(items) != 1 || items[0].ID != kept {
+			t.Fatalf("list sin papelera: total %d items %d: %v", total, len(items), err)
+		}
+		items, total, err = f.store.Posts.List(ctx, ListPostsParams{Page: 1, Limit: 10, Deleted: true})
+		if err != nil || total != 2 || items[0].ID != recent || items[1].ID != old {
+			t.Fatalf("papelera (más reciente primero): total %d: %v", total, err)
+		}
+		metrics, err := f.store.Posts.AggregateByTag(ctx, 10, nil, "")
+		if err != nil || len(metrics) != 1 || metrics[0].Count != 1 {
+			t.Fatalf("métricas sin papelera: %v %v", metrics, err)
+		}
+
+		ids, err := f.store.Posts.PurgeDeleted(ctx, f.now.Add(-24*time.Hour))
+		if err != nil {
+			t.Fatalf("purge: %v", err)
+		}
+		if len(ids) != 1 || ids[0] != old {
+			t.Fatalf("purgados: %v, want [%s]", ids, old.Hex())
+		}
+		if _, err := f.store.Posts.Get(ctx, old); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("get purgado: %v, want ErrNotFound", err)
+		}
+		if _, err := f.store.Posts.Get(ctx, recent); err != nil {
+			t.Fatalf("get en papelera: %v", err)
+		}
+		if ids, err := f.store.Posts.PurgeDeleted(ctx, f.now.Add(-24*time.Hour)); err != nil || len(ids) != 0 {
+			t.Fatalf("purge repetido: %v %v", ids, err)
+		}
+
+		restored, err := f.store.Posts.SetDeleted(ctx, recent, nil, "", 2)
+		if err != nil || restored.DeletedAt != nil || restored.DeletedBy != "" {
+			t.Fatalf("restaurar: %+v %v", restored, err)
+		}
+	})
+}
+
+func TestPostRepositoryListTextSearch(t *testing.T) {
+	forEachStore(t, func(t *testing.T, f *repoFixture) {
+		ctx := context.Background()
+		f.create(t, f.post("Introducción a MongoDB", "introduccion-a-mongodb", "Índices y consultas en Mongo.", true))
+		f.create(t, f.post("Go y SQLite", "go-y-sqlite", "Consultas con FTS5 y Go.", true))
+		f.create(t, f.post("Notas sueltas", "notas-sueltas", `El editor dijo "hola mundo" y se fue.`, true))
+
+		tests := []struct {
+			q    string
+			want []string
+		}{
+			{"mongodb", []string{"introduccion-a-mongodb"}},
+			{"INTRODUCCION", []string{"introduccion-a-mongodb"}},
+			{"índices", []string{"introduccion-a-mongodb"}},
+			{"mongodb sqlite", []string{"go-y-sqlite", "introduccion-a-mongodb"}},
+			{`"indices y consultas"`, []string{"introduccion-a-mongodb"}},
+			{`"consultas y indices"`, nil},
+			{`"hola mundo" editor`, []string{"notas-sueltas"}},
+			{`"hola mundo" "se fue"`, []string{"notas-sueltas"}},
+			{`"hola mundo" "no esta"`, nil},
+			{"consultas -mongo", []string{"go-y-sqlite"}},
+			{"-mongo", nil},
+			{"-mongo -sqlite", nil},
+			{`"hola`, []string{"notas-sueltas"}},
+			{`hola"mundo`, []string{"notas-sueltas"}},
+			{`""`, nil},
+			{"fts5", []string{"go-y-sqlite"}},
+		}
+		for _, tt := range tests {
+			items, total, err := f.store.Posts.List(ctx, ListPostsParams{Q: tt.q, Page: 1, Limit: 10})
+			if err != nil {
+				t.Fatalf("q=%s: %v", tt.q, err)
+			}
+			var got []string
+			for _, p := range items {
+				got = append(got, p.Slug)
+			}
+			sort.Strings(got)
+			if int(total) != len(tt.want) || !equalStrings(got, tt.want) {
+				t.Fatalf("q=%s: total %d %v, want %v", tt.q, total, got, tt.want)
+			}
+		}
+	})
+}
+
+func TestPostRepositoryListFiltersAndPages(t *testing.T) {
+	forEachStore(t, func(t *testing.T, f *repoFixture) {
+		ctx := context.Background()
+		for i, title := range []string{"Uno", "Dos", "Tres", "Cuatro", "Cinco"} {
+			p := f.post("Post "+title, "post-"+title, "contenido", i%2 == 0, "go")
+			at := f.now.Add(time.Duration(i) * time.Hour)
+			if p.Published {
+				p.PublishedAt = &at
+			}
+			f.create(t, p)
+		}
+		published := true
+
+		items, total, err := f.store.Posts.List(ctx, ListPostsParams{Published: &published, Page: 1, Limit: 2, SortField: "-publishedAt"})
+		if err != nil || total != 3 || len(items) != 2 || items[0].Title != "Post Cinco" || items[1].Title != "Post Tres" {
+			t.Fatalf("publicados, página 1: total %d %v: %v", total, titles(items), err)
+		}
+		items, _, err = f.store.Posts.List(ctx, ListPostsParams{Published: &published, Page: 2, Limit: 2, SortField: "-publishedAt"})
+		if err != nil || len(items) != 1 || items[0].Title != "Post Uno" {
+			t.Fatalf("publicados, página 2: %v: %v", titles(items), err)
+		}
+		items, total, err = f.store.Posts.List(ctx, ListPostsParams{Status: models.StatusDraft, Tag: "go", Page: 1, Limit: 10})
+		if err != nil || total != 2 || len(items) != 2 {
+			t.Fatalf("borradores con tag go: total %d: %v", total, err)
+		}
+		if _, total, err = f.store.Posts.List(ctx, ListPostsParams{AuthorID: primitive.NewObjectID(), Page: 1, Limit: 10}); err != nil || total != 0 {
+			t.Fatalf("autor sin posts: total %d: %v", total, err)
+		}
+		if n, err := f.store.Posts.CountByAuthor(ctx, f.author.ID); err != nil || n != 5 {
+			t.Fatalf("count by author: %d %v", n, err)
+		}
+	})
+}
+
+func TestPostRepositoryTagsMaintenance(t *testing.T) {
+	forEachStore(t, func(t *testing.T, f *repoFixture) {
+		ctx := context.Background()
+		a := f.create(t, f.post("Primer post", "primer-post", "uno", true, "golang", "mongo"))
+		b := f.create(t, f.post("Segundo post", "segundo-post", "dos", false, "go", "golang"))
+		f.create(t, f.post("Tercer post", "tercer-post", "tres", false, "sqlite"))
+
+		n, err := f.store.Posts.ReplaceTags(ctx, []string{"golang"}, "go", f.now)
+		if err != nil || n != 2 {
+			t.Fatalf("replace tags: %d %v", n, err)
+		}
+		for id, want := range map[primitive.ObjectID][]string{a: {"go", "mongo"}, b: {"go"}} {
+			got, err := f.store.Posts.Get(ctx, id)
+			if err != nil || !equalStrings(got.Tags, want) || got.Version != 2 {
+				t.Fatalf("tags de %s: %v version %d, want %v: %v", id.Hex(), got.Tags, got.Version, want, err)
+			}
+		}
+
+		metrics, err := f.store.Posts.AggregateByTag(ctx, 10, nil, "")
+		if err != nil || len(metrics) != 3 || metrics[0] != (TagMetric{Tag: "go", Count: 2}) {
+			t.Fatalf("métricas: %v %v", metrics, err)
+		}
+		unpublished := false
+		metrics, err = f.store.Posts.AggregateByTag(ctx, 10, &unpublished, models.StatusDraft)
+		if err != nil || len(metrics) != 2 {
+			t.Fatalf("métricas de borradores: %v %v", metrics, err)
+		}
+
+		counts, err := f.store.Posts.CountTags(ctx, []string{"go"})
+		if err != nil || len(counts) != 1 || counts[0].Statuses[models.StatusDraft] != 1 || counts[0].Statuses[models.StatusPublished] != 1 {
+			t.Fatalf("count tags: %+v %v", counts, err)
+		}
+
+		renamed, err := f.store.Posts.SetAuthorName(ctx, f.author.ID, "Ana G.", f.now)
+		if err != nil || renamed != 3 {
+			t.Fatalf("set author name: %d %v", renamed, err)
+		}
+		if renamed, err = f.store.Posts.SetAuthorName(ctx, f.author.ID, "Ana G.", f.now); err != nil || renamed != 0 {
+			t.Fatalf("set author name repetido: %d %v", renamed, err)
+		}
+	})
+}
+
+// titles retorna los títulos de items (mensajes de error).
+func titles(items []models.Post) []string {
+	out := make([]string, 0, len(items))
+	for _, p := range items {
+		out = append(out, p.Title)
+	}
+	return out
+}
+
+// equalStrings compara dos slices (nil y vacío son iguales).
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
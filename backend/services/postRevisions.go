@@ -0,0 +1,158 @@
+// services/postRevisions.go
+//
+// Paquete services: historial de revisiones de posts (listar, ver y restaurar).
+//
+// Convenciones:
+//   - Toda edición de contenido (PUT, PATCH, restauración de revisión) guarda el estado
+//     previo con recordRevision; enviar a la papelera o restaurar desde ella no lo hace.
+//   - El número de revisión es la versión que tenía el post, por lo que el historial
+//     puede tener huecos (versiones consumidas por la papelera).
+//   - Guardar o podar el historial nunca hace fallar la edición: los errores se registran.
+//   - La retención (PostServiceOptions.RevisionMaxCount/RevisionMaxAge) se aplica en cada
+//     edición sobre el post editado.
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"blog-api/models"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// errRevisionNumber es la causa de un número de revisión no positivo.
+var errRevisionNumber = errors.New("revision must be a positive integer")
+
+// recordRevision guarda current como revisión (número = current.Version) y aplica la
+// retención configurada. Se llama después de persistir la edición.
+func (s *PostService) recordRevision(ctx context.Context, current models.Post, editor string) {
+	now := time.Now().UTC()
+	rev := models.PostRevision{
+		PostID:      current.ID,
+		Revision:    current.Version,
+		Title:       current.Title,
+		Author:      current.Author,
+		AuthorID:    current.AuthorID,
+		Content:     current.Content,
+		Tags:        current.Tags,
+		Published:   current.Published,
+		PublishedAt: current.PublishedAt,
+		CreatedAt:   now,
+		CreatedBy:   editor,
+	}
+	if err := s.revisions.Create(ctx, rev); err != nil {
+		slog.WarnContext(ctx, "no se pudo guardar la revisión",
+			slog.String("postId", current.ID.Hex()), slog.Int64("revision", rev.Revision), slog.String("error", err.Error()))
+		return
+	}
+
+	var before time.Time
+	if s.opts.RevisionMaxAge > 0 {
+		before = now.Add(-s.opts.RevisionMaxAge)
+	}
+	if s.opts.RevisionMaxCount <= 0 && before.IsZero() {
+		return
+	}
+	if _, err := s.revisions.Prune(ctx, current.ID, s.opts.RevisionMaxCount, before); err != nil {
+		slog.WarnContext(ctx, "no se pudo podar el historial",
+			slog.String("postId", current.ID.Hex()), slog.String("error", err.Error()))
+	}
+}
+
+// ListRevisions retorna el historial de un Post, de la revisión más nueva a la más vieja.
+//
+// Parámetros:
+//   - ctx: contexto de cancelación/timeout.
+//   - idHex: ObjectID en hex del post.
+//
+// Retornos:
+//   - revisiones (vacío si el post nunca se editó o venció su retención).
+//   - error con sentinelas: ErrInvalidID, ErrNotFound (post inexistente o en la
+//     papelera), ErrDB.
+func (s *PostService) ListRevisions(ctx context.Context, idHex string) (_ []models.PostRevision, err error) {
+	ctx, span := startSpan(ctx, "PostService.ListRevisions", attribute.String("post.id", idHex))
+	defer endSpan(span, &err)
+
+	post, err := s.getVisible(ctx, idHex)
+	if err != nil {
+		return nil, err
+	}
+	revs, err := s.revisions.List(ctx, post.ID)
+	span.SetAttributes(attribute.Int("revisions", len(revs)))
+	return revs, logDBError(ctx, "list revisions", err)
+}
+
+// GetRevision recupera una revisión de un Post.
+//
+// Parámetros:
+//   - ctx: contexto de cancelación/timeout.
+//   - idHex: ObjectID en hex del post.
+//   - revision: número de revisión (versión del post en ese estado).
+//
+// Retornos:
+//   - revisión encontrada.
+//   - error con sentinelas: ErrInvalidID, ErrInvalidInput (revision <= 0), ErrNotFound
+//     (post inexistente o en la papelera, o revisión inexistente), ErrDB.
+func (s *PostService) GetRevision(ctx context.Context, idHex string, revision int64) (_ models.PostRevision, err error) {
+	ctx, span := startSpan(ctx, "PostService.GetRevision",
+		attribute.String("post.id", idHex), attribute.Int64("post.revision", revision))
+	defer endSpan(span, &err)
+
+	_, rev, err := s.getRevision(ctx, idHex, revision)
+	return rev, err
+}
+
+// getRevision valida el número y retorna el post vigente junto con la revisión pedida.
+func (s *PostService) getRevision(ctx context.Context, idHex string, revision int64) (models.Post, models.PostRevision, error) {
+	if revision <= 0 {
+		return models.Post{}, models.PostRevision{}, Wrap(errRevisionNumber, ErrInvalidInput, "revision")
+	}
+	post, err := s.getVisible(ctx, idHex)
+	if err != nil {
+		return models.Post{}, models.PostRevision{}, err
+	}
+	rev, err := s.revisions.Get(ctx, post.ID, revision)
+	return post, rev, logDBError(ctx, "get revision", err)
+}
+
+// RestoreRevision vuelve el contenido de un Post al de una revisión anterior.
+//
+// Reglas:
+//   - Se restauran title, author, content y tags; published/publishedAt conservan su
+//     estado actual (publicar o despublicar es una decisión aparte).
+//   - El autor de la revisión debe seguir existiendo (ErrInvalidInput si se eliminó).
+//   - Es una edición más: el estado vigente queda guardado como revisión y la versión
+//     del post se incrementa, de modo que la restauración también puede deshacerse.
+//
+// Parámetros:
+//   - ctx: contexto de cancelación/timeout.
+//   - idHex: ObjectID en hex del post.
+//   - revision: número de revisión a restaurar.
+//   - editor: quién restaura (se registra en la revisión).
+//   - ifVersions: versiones aceptadas (If-Match); nil = sin precondición.
+//
+// Retornos:
+//   - Post actualizado (estado After).
+//   - error con sentinelas: ErrInvalidID, ErrInvalidInput, ErrNotFound, ErrConflict, ErrDB.
+func (s *PostService) RestoreRevision(ctx context.Context, idHex string, revision int64, editor string, ifVersions []int64) (_ models.Post, err error) {
+	ctx, span := startSpan(ctx, "PostService.RestoreRevision",
+		attribute.String("post.id", idHex), attribute.Int64("post.revision", revision))
+	defer endSpan(span, &err)
+
+	current, rev, err := s.getRevision(ctx, idHex, revision)
+	if err != nil {
+		return models.Post{}, err
+	}
+	return s.save(ctx, current, models.Post{
+		Title:       rev.Title,
+		Author:      rev.Author,
+		AuthorID:    rev.AuthorID,
+		Content:     rev.Content,
+		Tags:        rev.Tags,
+		Published:   current.Published,
+		PublishAt:   current.PublishAt,
+		UnpublishAt: current.UnpublishAt,
+	}, editor, "", ifVersions)
+}
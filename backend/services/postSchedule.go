@@ -0,0 +1,232 @@
+// services/postSchedule.go
+//
+// Paquete services: publicación y despublicación programadas (publishAt/unpublishAt).
+//
+// Convenciones:
+//   - Una programación con fecha ya vencida se aplica al guardar el post; las futuras
+//     las aplica RunScheduler. En ambos casos el resultado es el mismo: publicar fija
+//     Published=true, PublishedAt=publishAt y Status="published"; despublicar fija
+//     Published=false y Status="archived". El campo aplicado se borra.
+//   - Ambos cambios respetan allowedTransitions: un publishAt pendiente exige un estado
+//     que pueda pasar a "published" (approved o archived). Si al vencer el post ya no
+//     admite el cambio (se movió de estado después de programarse), el scheduler
+//     cancela la programación vencida sin cambiar el estado y lo registra en el log.
+//   - Cada cambio del scheduler es una actualización normal (versión, updatedAt,
+//     revisión y transición a nombre de "scheduler"); si el post se editó entre la lectura y la
+//     escritura, el conflicto se reintenta en el ciclo siguiente.
+//   - Con varias instancias, sólo aplica programaciones la que tiene el Lease.
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"blog-api/models"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// schedulerEditor figura como autor de las revisiones creadas por el scheduler.
+	schedulerEditor = "scheduler"
+	// schedulerBatch es la cantidad de posts leídos por consulta en ApplySchedules.
+	schedulerBatch = 100
+)
+
+var (
+	// errScheduleOrder es la causa cuando unpublishAt no es posterior a publishAt.
+	errScheduleOrder = errors.New("unpublishAt must be after publishAt")
+	// errScheduledPublished es la causa cuando un post publicado trae un publishAt futuro.
+	errScheduledPublished = errors.New("a post with a future publishAt must have published=false")
+	// errUnpublishNothing es la causa cuando unpublishAt no tiene publicación que terminar.
+	errUnpublishNothing = errors.New("unpublishAt requires published=true or a publishAt")
+)
+
+// ScheduleResult resume un ciclo de ApplySchedules.
+//
+// Campos:
+//   - Published: posts publicados por vencer publishAt.
+//   - Unpublished: posts despublicados por vencer unpublishAt.
+//   - Canceled: programaciones vencidas descartadas porque el estado del post ya no
+//     admite el cambio.
+//   - Skipped: posts editados durante el ciclo (se reintentan en el siguiente).
+type ScheduleResult struct {
+	Published   int
+	Unpublished int
+	Canceled    int
+	Skipped     int
+}
+
+// ApplySchedules aplica los publishAt/unpublishAt vencidos de los posts fuera de la
+// papelera, del más antiguo al más reciente.
+//
+// Retornos:
+//   - ScheduleResult con lo aplicado.
+//   - error con sentinel ErrDB si falla el almacenamiento (lo aplicado hasta entonces
+//     queda guardado).
+func (s *PostService) ApplySchedules(ctx context.Context) (_ ScheduleResult, err error) {
+	ctx, span := startSpan(ctx, "PostService.ApplySchedules")
+	defer endSpan(span, &err)
+
+	var res ScheduleResult
+	now := time.Now().UTC()
+	for {
+		items, _, err := s.repo.List(ctx, ListPostsParams{Scheduled: true, Page: 1, Limit: schedulerBatch})
+		if err != nil {
+			return res, logDBError(ctx, "list scheduled posts", err)
+		}
+
+		applied := 0
+		for _, current := range items {
+			if nextScheduledAt(current).After(now) {
+				// La lista está ordenada: lo que sigue tampoco venció.
+				return res, nil
+			}
+			p := current
+			publish, unpublish := current.PublishAt != nil && !current.PublishAt.After(now),
+				current.UnpublishAt != nil && !current.UnpublishAt.After(now)
+			path := applyDueSchedule(&p, now)
+			canceled := checkTransition(postStatus(current), path...) != nil
+			if canceled {
+				p = current
+				if publish {
+					p.PublishAt = nil
+				}
+				if unpublish {
+					p.UnpublishAt = nil
+				}
+			}
+			p.UpdatedAt = &now
+
+			updated, err := s.repo.Update(ctx, current.ID, p, current.Version)
+			if err != nil {
+				if errors.Is(err, ErrConflict) || errors.Is(err, ErrNotFound) {
+					res.Skipped++
+					continue
+				}
+				return res, logDBError(ctx, "apply post schedule", err)
+			}
+			s.recordRevision(ctx, current, schedulerEditor)
+			applied++
+			if canceled {
+				res.Canceled++
+				slog.WarnContext(ctx, "programación cancelada: el estado del post no admite el cambio",
+					slog.String("postId", current.ID.Hex()), slog.String("status", postStatus(current)))
+				continue
+			}
+			reason := "publishAt"
+			if publish {
+				res.Published++
+			}
+			if unpublish {
+				res.Unpublished++
+				reason = "unpublishAt"
+			}
+			s.recordTransition(ctx, current, updated, schedulerEditor, reason)
+			s.refreshTagCounts(ctx, current, updated)
+		}
+		span.SetAttributes(attribute.Int("schedule.published", res.Published),
+			attribute.Int("schedule.unpublished", res.Unpublished), attribute.Int("schedule.canceled", res.Canceled))
+
+		// Los aplicados salen de la lista; si nada avanzó, reintentar sería un bucle.
+		if len(items) < schedulerBatch || applied == 0 {
+			return res, nil
+		}
+	}
+}
+
+// RunScheduler ejecuta ApplySchedules al iniciar y luego cada interval mientras esta
+// instancia tenga lease, hasta que ctx se cancele; al salir libera el lease. Pensado
+// para correr como worker de fondo; los errores sólo se registran.
+func (s *PostService) RunScheduler(ctx context.Context, lease Lease, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	defer func() {
+		if err := lease.Release(context.Background()); err != nil {
+			slog.Warn("no se pudo liberar el lease del scheduler", slog.String("error", err.Error()))
+		}
+	}()
+
+	for {
+		if ok, err := lease.Acquire(ctx); err != nil {
+			slog.WarnContext(ctx, "no se pudo tomar el lease del scheduler", slog.String("error", err.Error()))
+		} else if ok {
+			res, err := s.ApplySchedules(ctx)
+			if err != nil {
+				slog.WarnContext(ctx, "publicación programada incompleta", slog.String("error", err.Error()))
+			}
+			if res.Published > 0 || res.Unpublished > 0 || res.Canceled > 0 {
+				slog.InfoContext(ctx, "publicación programada aplicada",
+					slog.Int("published", res.Published), slog.Int("unpublished", res.Unpublished),
+					slog.Int("canceled", res.Canceled), slog.Int("skipped", res.Skipped))
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// prepareSchedule valida publishAt/unpublishAt de p (que se guardará en now), aplica
+// los que ya vencieron y retorna los estados que eso recorre (ver applyDueSchedule).
+//
+// Errores:
+//   - ErrInvalidInput: unpublishAt no posterior a publishAt, publishAt futuro en un post
+//     publicado o unpublishAt sin publicación actual ni programada.
+func prepareSchedule(p *models.Post, now time.Time) ([]string, error) {
+	if p.PublishAt != nil {
+		at := p.PublishAt.UTC()
+		p.PublishAt = &at
+	}
+	if p.UnpublishAt != nil {
+		at := p.UnpublishAt.UTC()
+		p.UnpublishAt = &at
+	}
+
+	switch {
+	case p.PublishAt != nil && p.UnpublishAt != nil && !p.UnpublishAt.After(*p.PublishAt):
+		return nil, Wrap(errScheduleOrder, ErrInvalidInput, "unpublishAt")
+	case p.PublishAt != nil && p.PublishAt.After(now) && p.Published:
+		return nil, Wrap(errScheduledPublished, ErrInvalidInput, "publishAt")
+	case p.UnpublishAt != nil && p.PublishAt == nil && !p.Published:
+		return nil, Wrap(errUnpublishNothing, ErrInvalidInput, "unpublishAt")
+	}
+	return applyDueSchedule(p, now), nil
+}
+
+// applyDueSchedule aplica sobre p las programaciones con fecha <= now, las borra y
+// retorna los estados por los que pasa p en orden ("published", "archived" o ambos;
+// vacío si nada venció), para validarlos con checkTransition.
+func applyDueSchedule(p *models.Post, now time.Time) []string {
+	var path []string
+	if p.PublishAt != nil && !p.PublishAt.After(now) {
+		at := *p.PublishAt
+		p.Published = true
+		p.PublishedAt = &at
+		p.PublishAt = nil
+		p.Status = models.StatusPublished
+		path = append(path, p.Status)
+	}
+	if p.UnpublishAt != nil && !p.UnpublishAt.After(now) {
+		p.Published = false
+		p.UnpublishAt = nil
+		p.Status = models.StatusArchived
+		path = append(path, p.Status)
+	}
+	return path
+}
+
+// nextScheduledAt retorna la fecha programada más próxima de post (nil si no tiene).
+func nextScheduledAt(post models.Post) *time.Time {
+	switch {
+	case post.PublishAt == nil:
+		return post.UnpublishAt
+	case post.UnpublishAt == nil || post.PublishAt.Before(*post.UnpublishAt):
+		return post.PublishAt
+	default:
+		return post.UnpublishAt
+	}
+}
@@ -1,36 +1,95 @@
 // services/postService.go
 //
-// Paquete services: lógica de negocio y acceso a datos para la entidad Post.
+// Paquete services: lógica de negocio para la entidad Post.
 // Convenciones:
-//   - Todas las funciones que interactúan con MongoDB aplican un timeout fijo (defaultTimeout).
-//   - Todos los errores del driver/infra se envuelven con sentinelas (ErrDB, ErrNotFound, ErrInvalidID, etc.).
+//   - PostService recibe sus repositorios por constructor; no existen handles globales.
+//   - Las reglas de negocio (timestamps, PublishedAt, paginación, ids) viven aquí;
+//     el acceso a datos queda encapsulado en el repositorio.
+//   - Todos los errores llegan envueltos con sentinelas (ErrDB, ErrNotFound, ErrInvalidID, etc.).
 //   - No se exponen errores del driver a capas superiores; use errors.Is(err, services.ErrX) en controladores.
+//   - Los ErrDB se registran aquí con logDBError, usando el ctx del request (incluye el request ID).
+//   - Cada método abre un span (startSpan/endSpan, ver tracing.go).
 package services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"blog-api/models"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/attribute"
 )
 
-const (
-	// defaultTimeout define el tiempo máximo de espera por operación a MongoDB.
-	defaultTimeout = 5 * time.Second
-	// maxPageLimit limita la cantidad de documentos por página para listados.
-	maxPageLimit = 100
-)
+// defaultMaxPageLimit es el tope de documentos por página si no se configura otro.
+const defaultMaxPageLimit = 100
+
+// PostServiceOptions agrupa los parámetros configurables de PostService.
+//
+// Campos:
+//   - MaxPageLimit: tope de Limit en ListPosts (<=0 usa defaultMaxPageLimit).
+//   - RevisionMaxCount: revisiones conservadas por post (<=0 = sin límite).
+//   - RevisionMaxAge: antigüedad máxima de una revisión (<=0 = sin límite).
+//   - TagAliases: etiqueta alias → canónica, aplicado al normalizar (ver postTags.go).
+//   - ReactionKinds: tipos de reacción que se muestran en cada post (ver postReactions.go).
+type PostServiceOptions struct {
+	MaxPageLimit     int
+	RevisionMaxCount int
+	RevisionMaxAge   time.Duration
+	TagAliases       map[string]string
+	ReactionKinds    []string
+}
+
+// PostService implementa los casos de uso de posts sobre un PostRepository,
+// guarda el historial de ediciones en un RevisionRepository, los cambios de estado
+// editorial en un TransitionRepository, resuelve el autor contra un AuthorRepository,
+// mantiene los contadores de etiquetas de un TagCountRepository, cuenta (y purga) los
+// comentarios de un CommentRepository y muestra (y purga) las reacciones de un
+// ReactionRepository.
+type PostService struct {
+	repo        PostRepository
+	revisions   RevisionRepository
+	transitions TransitionRepository
+	authors     AuthorRepository
+	tagCounts   TagCountRepository
+	comments    CommentRepository
+	reactions   ReactionRepository
+	tags        tagNormalizer
+	opts        PostServiceOptions
+}
+
+// NewPostService crea el servicio de posts usando repo como almacenamiento,
+// revisions para el historial, transitions para el flujo editorial, authors para
+// validar el autor de cada post, tagCounts para los contadores de etiquetas, comments
+// para los comentarios y reactions para las reacciones.
+func NewPostService(repo PostRepository, revisions RevisionRepository, transitions TransitionRepository, authors AuthorRepository, tagCounts TagCountRepository, comments CommentRepository, reactions ReactionRepository, opts PostServiceOptions) *PostService {
+	if opts.MaxPageLimit <= 0 {
+		opts.MaxPageLimit = defaultMaxPageLimit
+	}
+	return &PostService{repo: repo, revisions: revisions, transitions: transitions, authors: authors,
+		tagCounts: tagCounts, comments: comments, reactions: reactions, tags: newTagNormalizer(opts.TagAliases), opts: opts}
+}
 
 // CreatePost inserta un nuevo Post.
 //
 // Reglas:
-//   - Estampa CreatedAt=now.
+//   - Estampa CreatedAt=now y Version=1.
 //   - Si p.Published==true y p.PublishedAt==nil, fija PublishedAt=now.
+//   - Si p.Slug está vacío lo genera del título (con sufijo -2, -3... si está en uso);
+//     si viene definido debe tener formato válido y estar libre.
+//   - PublishAt/UnpublishAt programan la publicación (ver postSchedule.go); una fecha
+//     ya vencida se aplica en el acto.
+//   - Status se deriva de Published: "published" o "draft" (ver postWorkflow.go); un
+//     publishAt futuro lo crea "approved", listo para que el scheduler lo publique.
+//     Crear no es una transición: las ediciones posteriores sí pasan por el flujo.
+//   - El autor debe existir: se toma p.AuthorID o, si es cero, el autor cuyo slug
+//     coincide con el de p.Author; Author queda con su nombre (ver resolveAuthor).
+//   - Las etiquetas se normalizan: minúsculas, sin espacios sobrantes, alias aplicados
+//     y sin repetidas (ver postTags.go).
 //
 // Parámetros:
 //   - ctx: contexto de cancelación/timeout.
@@ -38,30 +97,47 @@ const (
 //
 // Retornos:
 //   - ObjectID del documento insertado.
-//   - error con sentinelas: ErrDB si el driver falla.
+//   - error con sentinelas: ErrDB si el almacenamiento falla.
 //
 // Errores:
+//   - ErrInvalidInput: slug con formato inválido, programación inconsistente o autor
+//     inexistente.
+//   - ErrConflict: slug explícito en uso por otro post.
 //   - ErrDB: error del driver o de infraestructura.
 //   - (No valida campos de dominio; esas validaciones están en DTO/controlador).
-func CreatePost(ctx context.Context, p models.Post) (primitive.ObjectID, error) {
-	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
-	defer cancel()
+func (s *PostService) CreatePost(ctx context.Context, p models.Post) (_ primitive.ObjectID, err error) {
+	ctx, span := startSpan(ctx, "PostService.CreatePost")
+	defer endSpan(span, &err)
 
+	if err := s.resolveAuthor(ctx, models.Post{}, &p); err != nil {
+		return primitive.NilObjectID, err
+	}
 	now := time.Now().UTC()
+	p.Tags = s.tags.tags(p.Tags)
 	p.CreatedAt = now
+	p.Version = 1
+	p.Status = ""
+	if _, err := prepareSchedule(&p, now); err != nil {
+		return primitive.NilObjectID, err
+	}
+	switch {
+	case p.Status != "":
+	case !p.Published && p.PublishAt != nil:
+		p.Status = models.StatusApproved
+	default:
+		p.Status = statusFromPublished(p.Published)
+	}
 	if p.Published && p.PublishedAt == nil {
 		p.PublishedAt = &now
 	}
-
-	res, err := DB.Collection("posts").InsertOne(ctx, p)
+	p.OldSlugs = nil
+	id, err := s.createWithSlug(ctx, p)
 	if err != nil {
-		return primitive.NilObjectID, Wrap(err, ErrDB, "insert post")
-	}
-	oid, ok := res.InsertedID.(primitive.ObjectID)
-	if !ok {
-		return primitive.NilObjectID, Wrap(errors.New("inserted id not an ObjectID"), ErrDB, "cast inserted id")
+		return primitive.NilObjectID, err
 	}
-	return oid, nil
+	p.ID = id
+	s.refreshTagCounts(ctx, models.Post{}, p)
+	return id, nil
 }
 
 // GetPostByID recupera un Post por su ObjectID (hexadecimal).
@@ -71,25 +147,45 @@ func CreatePost(ctx context.Context, p models.Post) (primitive.ObjectID, error)
 //   - idHex: cadena hexadecimal de 24 caracteres correspondiente al ObjectID.
 //
 // Retornos:
-//   - Post encontrado.
-//   - error con sentinelas: ErrInvalidID si el id es inválido; ErrNotFound si no existe; ErrDB si falla el driver.
-func GetPostByID(ctx context.Context, idHex string) (models.Post, error) {
-	oid, err := primitive.ObjectIDFromHex(idHex)
+//   - Post encontrado, con sus reacciones por tipo (ver postReactions.go).
+//   - error con sentinelas: ErrInvalidID si el id es inválido; ErrNotFound si no existe
+//     o está en la papelera; ErrDB si falla el driver.
+func (s *PostService) GetPostByID(ctx context.Context, idHex string) (_ models.Post, err error) {
+	ctx, span := startSpan(ctx, "PostService.GetPostByID", attribute.String("post.id", idHex))
+	defer endSpan(span, &err)
+
+	post, err := s.getVisible(ctx, idHex)
 	if err != nil {
-		return models.Post{}, Wrap(err, ErrInvalidID, "parse objectid")
+		return models.Post{}, err
 	}
+	items := []models.Post{post}
+	if err := s.attachReactions(ctx, items); err != nil {
+		return models.Post{}, err
+	}
+	return items[0], nil
+}
 
-	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
-	defer cancel()
+// getVisible recupera un Post por id; los de la papelera cuentan como inexistentes. Lo
+// usan las escrituras, que no necesitan las reacciones.
+func (s *PostService) getVisible(ctx context.Context, idHex string) (models.Post, error) {
+	post, err := s.getIncludingDeleted(ctx, idHex)
+	if err != nil {
+		return models.Post{}, err
+	}
+	if post.DeletedAt != nil {
+		return models.Post{}, Wrap(errPostDeleted, ErrNotFound, "post not found")
+	}
+	return post, nil
+}
 
-	var out models.Post
-	if err := DB.Collection("posts").FindOne(ctx, bson.M{"_id": oid}).Decode(&out); err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			return models.Post{}, Wrap(err, ErrNotFound, "post not found")
-		}
-		return models.Post{}, Wrap(err, ErrDB, "find post")
+// getIncludingDeleted recupera un Post por id sin excluir la papelera.
+func (s *PostService) getIncludingDeleted(ctx context.Context, idHex string) (models.Post, error) {
+	oid, err := primitive.ObjectIDFromHex(idHex)
+	if err != nil {
+		return models.Post{}, Wrap(err, ErrInvalidID, "parse objectid")
 	}
-	return out, nil
+	post, err := s.repo.Get(ctx, oid)
+	return post, logDBError(ctx, "get post", err)
 }
 
 // UpdatePostByID actualiza campos de un Post y retorna el documento resultante.
@@ -97,86 +193,465 @@ func GetPostByID(ctx context.Context, idHex string) (models.Post, error) {
 // Reglas:
 //   - Si el Post actual no está publicado y el nuevo estado Published pasa a true,
 //     y PublishedAt es nil, se fija PublishedAt=now.
-//   - Actualiza: title, author, content, tags, published; updatedAt=now.
+//   - Actualiza: title, author, content, tags, published; updatedAt=now. Las etiquetas
+//     se normalizan como en CreatePost.
 //   - publishedAt sólo se actualiza si viene definido o si aplica la regla anterior.
+//   - Slug: uno distinto del vigente se valida y debe estar libre (ErrConflict); el
+//     vigente se conserva aunque el título cambie. Vacío lo conserva, salvo que el
+//     título cambie, en cuyo caso se regenera. El slug
+//     reemplazado queda en OldSlugs (redirección 301 en GET by-slug).
+//   - PublishAt/UnpublishAt reemplazan la programación (nil la cancela); una fecha ya
+//     vencida se aplica en el acto.
+//   - Cambiar Published mueve el estado editorial ("published" o "draft") y registra
+//     la transición, que debe estar en allowedTransitions (ErrConflict si no: un
+//     borrador pasa por in_review y approved antes de publicarse). Un publishAt nuevo
+//     exige un estado que pueda publicarse (approved o archived), igual que uno vencido.
+//   - El autor debe existir (ErrInvalidInput); un AuthorID cero con el mismo nombre
+//     conserva el autor actual (ver resolveAuthor).
+//   - Concurrencia optimista: la escritura se condiciona a la versión leída, por lo que
+//     una edición concurrente entre la lectura y la escritura produce ErrConflict en
+//     lugar de pisarse (lost update). Version se incrementa en 1.
+//   - El estado anterior se guarda como revisión (ver recordRevision).
 //
 // Parámetros:
 //   - ctx: contexto de cancelación/timeout.
 //   - idHex: ObjectID en hex del documento a actualizar.
 //   - p: valores a aplicar.
+//   - editor: quién edita (se registra en la revisión).
+//   - ifVersions: versiones aceptadas (If-Match); nil = sin precondición.
 //
 // Retornos:
 //   - Post actualizado (estado After).
-//   - error con sentinelas: ErrInvalidID, ErrNotFound, ErrDB.
-func UpdatePostByID(ctx context.Context, idHex string, p models.Post) (models.Post, error) {
-	current, err := GetPostByID(ctx, idHex)
+//   - error con sentinelas: ErrInvalidID, ErrNotFound, ErrConflict (versión no aceptada
+//     o modificada concurrentemente), ErrDB.
+func (s *PostService) UpdatePostByID(ctx context.Context, idHex string, p models.Post, editor string, ifVersions []int64) (_ models.Post, err error) {
+	ctx, span := startSpan(ctx, "PostService.UpdatePostByID", attribute.String("post.id", idHex))
+	defer endSpan(span, &err)
+
+	current, err := s.getVisible(ctx, idHex)
 	if err != nil {
 		return models.Post{}, err
 	}
+	return s.save(ctx, current, p, editor, "", ifVersions)
+}
 
-	if !current.Published && p.Published && p.PublishedAt == nil {
-		now := time.Now().UTC()
-		p.PublishedAt = &now
+// PostPatch es una actualización parcial: sólo se aplican los campos no nil.
+//
+// Campos:
+//   - Title, Slug, Author, AuthorID, Content, Published: nuevo valor.
+//     Cambiar Author sin AuthorID busca el autor por nombre.
+//   - Tags: reemplaza el arreglo completo; un puntero a slice nil elimina las etiquetas.
+//   - PublishAt / UnpublishAt: nueva programación; un puntero a nil la cancela.
+type PostPatch struct {
+	Title       *string
+	Slug        *string
+	Author      *string
+	AuthorID    *primitive.ObjectID
+	Content     *string
+	Tags        *[]string
+	Published   *bool
+	PublishAt   **time.Time
+	UnpublishAt **time.Time
+}
+
+// apply retorna los campos editables de current con el patch aplicado.
+// PublishedAt queda nil para que save aplique la misma regla que un PUT; Slug queda
+// vacío si el patch no lo incluye, para que save decida si el título lo cambia.
+func (pp PostPatch) apply(current models.Post) models.Post {
+	out := models.Post{
+		Title:       current.Title,
+		Author:      current.Author,
+		AuthorID:    current.AuthorID,
+		Content:     current.Content,
+		Tags:        current.Tags,
+		Published:   current.Published,
+		PublishAt:   current.PublishAt,
+		UnpublishAt: current.UnpublishAt,
 	}
+	if pp.Title != nil {
+		out.Title = *pp.Title
+	}
+	if pp.Slug != nil {
+		out.Slug = *pp.Slug
+	}
+	if pp.Author != nil {
+		out.Author = *pp.Author
+	}
+	if pp.AuthorID != nil {
+		out.AuthorID = *pp.AuthorID
+	}
+	if pp.Content != nil {
+		out.Content = *pp.Content
+	}
+	if pp.Tags != nil {
+		out.Tags = *pp.Tags
+	}
+	if pp.Published != nil {
+		out.Published = *pp.Published
+	}
+	if pp.PublishAt != nil {
+		out.PublishAt = *pp.PublishAt
+	}
+	if pp.UnpublishAt != nil {
+		out.UnpublishAt = *pp.UnpublishAt
+	}
+	return out
+}
 
-	set := bson.M{
-		"title":     p.Title,
-		"author":    p.Author,
-		"content":   p.Content,
-		"tags":      p.Tags,
-		"published": p.Published,
-		"updatedAt": time.Now().UTC(),
+// PatchPostByID aplica una actualización parcial (JSON Merge Patch) a un Post.
+//
+// Reglas:
+//   - Los campos ausentes del patch conservan su valor actual.
+//   - Misma regla de PublishedAt, updatedAt, control de versión y revisiones que UpdatePostByID.
+//
+// Parámetros:
+//   - ctx: contexto de cancelación/timeout.
+//   - idHex: ObjectID en hex del documento a actualizar.
+//   - patch: campos a modificar (ya validados por el controlador).
+//   - editor: quién edita (se registra en la revisión).
+//   - ifVersions: versiones aceptadas (If-Match); nil = sin precondición.
+//
+// Retornos:
+//   - Post actualizado (estado After).
+//   - error con sentinelas: ErrInvalidID, ErrNotFound, ErrConflict, ErrDB.
+func (s *PostService) PatchPostByID(ctx context.Context, idHex string, patch PostPatch, editor string, ifVersions []int64) (_ models.Post, err error) {
+	ctx, span := startSpan(ctx, "PostService.PatchPostByID", attribute.String("post.id", idHex))
+	defer endSpan(span, &err)
+
+	current, err := s.getVisible(ctx, idHex)
+	if err != nil {
+		return models.Post{}, err
 	}
-	if p.PublishedAt != nil {
-		set["publishedAt"] = p.PublishedAt
+	return s.save(ctx, current, patch.apply(current), editor, "", ifVersions)
+}
+
+// PostValidator valida el estado de un Post resultante de un JSON Patch con las mismas
+// reglas de entrada que PUT (las define la capa HTTP); retorna ErrInvalidInput si falla.
+type PostValidator func(models.Post) error
+
+// editablePostFields son los miembros del Post que un JSON Patch puede modificar;
+// el resto sólo admite "test".
+var editablePostFields = map[string]bool{
+	"title":       true,
+	"slug":        true,
+	"author":      true,
+	"authorId":    true,
+	"content":     true,
+	"tags":        true,
+	"published":   true,
+	"publishAt":   true,
+	"unpublishAt": true,
+}
+
+// JSONPatchPostByID aplica un JSON Patch (RFC 6902) a un Post.
+//
+// Reglas:
+//   - Las operaciones se aplican sobre la representación JSON del post actual y el
+//     resultado se valida con validate antes de persistir.
+//   - Sólo title, slug, author, authorId, content, tags, published y la programación
+//     son modificables; "test" admite
+//     cualquier ruta (ej. {"op":"test","path":"/version","value":3}).
+//   - Un "test" fallido retorna ErrConflict, incluido el de /version.
+//   - Si el patch sólo agrega al final de tags ("add /tags/-") o sólo quita etiquetas
+//     ("remove /tags/N"), además de "test /version", se persiste con UpdateTags
+//     ($addToSet/$pull): ediciones de tags concurrentes no se pisan. Las etiquetas
+//     agregadas se normalizan y las ya presentes no se duplican.
+//   - En otro caso se persiste como un PUT (ver save), condicionado a la versión leída.
+//
+// Parámetros:
+//   - ctx: contexto de cancelación/timeout.
+//   - idHex: ObjectID en hex del documento a actualizar.
+//   - ops: operaciones en orden.
+//   - validate: reglas de validación del resultado.
+//   - editor: quién edita (se registra en la revisión).
+//   - ifVersions: versiones aceptadas (If-Match); nil = sin precondición.
+//
+// Retornos:
+//   - Post actualizado (estado After).
+//   - error con sentinelas: ErrInvalidID, ErrInvalidInput, ErrNotFound, ErrConflict, ErrDB.
+func (s *PostService) JSONPatchPostByID(ctx context.Context, idHex string, ops []PatchOperation, validate PostValidator, editor string, ifVersions []int64) (_ models.Post, err error) {
+	ctx, span := startSpan(ctx, "PostService.JSONPatchPostByID",
+		attribute.String("post.id", idHex), attribute.Int("patch.operations", len(ops)))
+	defer endSpan(span, &err)
+
+	if err := checkPatchTargets(ops); err != nil {
+		return models.Post{}, err
+	}
+	current, err := s.getVisible(ctx, idHex)
+	if err != nil {
+		return models.Post{}, err
+	}
+	if err := checkVersion(current, ifVersions); err != nil {
+		return models.Post{}, err
+	}
+
+	p, err := patchedPost(current, ops)
+	if err != nil {
+		return models.Post{}, err
+	}
+	if err := validate(p); err != nil {
+		return models.Post{}, err
 	}
 
-	oid, _ := primitive.ObjectIDFromHex(idHex) // validado por GetPostByID
-	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
-	defer cancel()
+	change, testsVersion, ok := atomicTagChange(current, ops)
+	if ok && len(change.Add) > 0 {
+		// Si ninguna etiqueta agregada sobrevive a la normalización se persiste como un PUT.
+		change.Add = s.tags.tags(change.Add)
+		ok = len(change.Add) > 0
+	}
+	if !ok {
+		return s.save(ctx, current, p, editor, "", ifVersions)
+	}
+	span.SetAttributes(attribute.Bool("patch.atomic", true))
+	var expected int64
+	if testsVersion || ifVersions != nil {
+		expected = current.Version
+	}
+	updated, err := s.repo.UpdateTags(ctx, current.ID, change, time.Now().UTC(), expected)
+	if err != nil {
+		return models.Post{}, logDBError(ctx, "update post tags", err)
+	}
+	s.recordRevision(ctx, current, editor)
+	s.refreshTagCounts(ctx, current, updated)
+	return updated, nil
+}
 
-	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
-	var updated models.Post
-	if err := DB.Collection("posts").
-		FindOneAndUpdate(ctx, bson.M{"_id": oid}, bson.M{"$set": set}, opts).
-		Decode(&updated); err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			return models.Post{}, Wrap(err, ErrNotFound, "post not found after update")
+// checkPatchTargets rechaza operaciones que modifican campos no editables.
+func checkPatchTargets(ops []PatchOperation) error {
+	for _, op := range ops {
+		targets := []string{op.Path}
+		if op.Op == "move" {
+			targets = append(targets, op.From)
+		}
+		if op.Op == "test" {
+			continue
+		}
+		for _, target := range targets {
+			field, _, _ := strings.Cut(strings.TrimPrefix(target, "/"), "/")
+			if !strings.HasPrefix(target, "/") || !editablePostFields[field] {
+				return Wrap(errors.New("field is not editable"), ErrInvalidInput, op.Op+" "+target)
+			}
 		}
-		return models.Post{}, Wrap(err, ErrDB, "findOneAndUpdate post")
 	}
+	return nil
+}
+
+// patchedPost aplica ops sobre la representación JSON de current y retorna sus
+// campos editables (como PostPatch.apply, PublishedAt queda nil y Slug vacío si ninguna
+// operación toca /slug).
+func patchedPost(current models.Post, ops []PatchOperation) (models.Post, error) {
+	raw, err := json.Marshal(current)
+	if err != nil {
+		return models.Post{}, Wrap(err, ErrInvalidInput, "encode post")
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return models.Post{}, Wrap(err, ErrInvalidInput, "encode post")
+	}
+	if _, ok := doc["tags"]; !ok {
+		// tags se omite del JSON cuando está vacío; "add /tags/-" debe poder usarse igual.
+		doc["tags"] = []any{}
+	}
+
+	result, err := applyJSONPatch(doc, ops)
+	if err != nil {
+		return models.Post{}, err
+	}
+	if raw, err = json.Marshal(result); err != nil {
+		return models.Post{}, Wrap(err, ErrInvalidInput, "decode patched post")
+	}
+	var patched struct {
+		Title       *string            `json:"title"`
+		Slug        string             `json:"slug"`
+		Author      *string            `json:"author"`
+		AuthorID    primitive.ObjectID `json:"authorId"`
+		Content     *string            `json:"content"`
+		Tags        []string           `json:"tags"`
+		Published   *bool              `json:"published"`
+		PublishAt   *time.Time         `json:"publishAt"`
+		UnpublishAt *time.Time         `json:"unpublishAt"`
+	}
+	if err := json.Unmarshal(raw, &patched); err != nil {
+		return models.Post{}, Wrap(err, ErrInvalidInput, "decode patched post")
+	}
+	if patched.Title == nil || patched.Author == nil || patched.Content == nil || patched.Published == nil {
+		return models.Post{}, Wrap(errors.New("required field removed"), ErrInvalidInput, "decode patched post")
+	}
+	if !patchTargets(ops, "slug") {
+		// El documento trae el slug vigente; sin una operación sobre /slug el cliente no
+		// lo envió y save decide si el título lo cambia (como PostPatch.apply).
+		patched.Slug = ""
+	}
+	return models.Post{
+		Title:       *patched.Title,
+		Slug:        patched.Slug,
+		Author:      *patched.Author,
+		AuthorID:    patched.AuthorID,
+		Content:     *patched.Content,
+		Tags:        patched.Tags,
+		Published:   *patched.Published,
+		PublishAt:   patched.PublishAt,
+		UnpublishAt: patched.UnpublishAt,
+	}, nil
+}
+
+// patchTargets indica si alguna operación de ops, salvo "test", escribe o mueve field.
+func patchTargets(ops []PatchOperation, field string) bool {
+	for _, op := range ops {
+		if op.Op == "test" {
+			continue
+		}
+		for _, target := range []string{op.Path, op.From} {
+			if f, _, _ := strings.Cut(strings.TrimPrefix(target, "/"), "/"); f == field {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// atomicTagChange traduce ops a un TagChange si sólo agregan o sólo quitan etiquetas
+// (más "test /version"); ok=false si el patch requiere reescribir el documento.
+// Los "remove /tags/N" se resuelven al valor en esa posición de current; se descarta
+// la vía atómica si ese valor aparece repetido ($pull quitaría todas sus apariciones).
+func atomicTagChange(current models.Post, ops []PatchOperation) (change TagChange, testsVersion bool, ok bool) {
+	tags := append([]string(nil), current.Tags...)
+	for _, op := range ops {
+		switch {
+		case op.Op == "test" && op.Path == "/version":
+			testsVersion = true
+		case op.Op == "add" && op.Path == "/tags/-" && current.Tags != nil:
+			var tag string
+			if err := json.Unmarshal(op.Value, &tag); err != nil {
+				return TagChange{}, false, false
+			}
+			change.Add = append(change.Add, tag)
+			tags = append(tags, tag)
+		case op.Op == "remove" && strings.HasPrefix(op.Path, "/tags/"):
+			i, err := strconv.Atoi(strings.TrimPrefix(op.Path, "/tags/"))
+			if err != nil || i < 0 || i >= len(tags) || countString(current.Tags, tags[i]) != 1 {
+				return TagChange{}, false, false
+			}
+			change.Remove = append(change.Remove, tags[i])
+			tags = append(tags[:i], tags[i+1:]...)
+		default:
+			return TagChange{}, false, false
+		}
+	}
+	if (len(change.Add) == 0) == (len(change.Remove) == 0) {
+		return TagChange{}, false, false
+	}
+	return change, testsVersion, true
+}
+
+// countString cuenta las apariciones de v en s.
+func countString(s []string, v string) int {
+	n := 0
+	for _, x := range s {
+		if x == v {
+			n++
+		}
+	}
+	return n
+}
+
+// save persiste p sobre current aplicando las reglas comunes de actualización:
+// precondición de versión, autor, slug, etiquetas normalizadas, programación, estado
+// editorial, PublishedAt false→true, updatedAt, escritura condicionada a current.Version, revisión del estado
+// anterior y, si cambió el estado, la transición (con reason).
+func (s *PostService) save(ctx context.Context, current, p models.Post, editor, reason string, ifVersions []int64) (models.Post, error) {
+	if err := checkVersion(current, ifVersions); err != nil {
+		return models.Post{}, err
+	}
+	if err := s.resolveAuthor(ctx, current, &p); err != nil {
+		return models.Post{}, err
+	}
+
+	var err error
+	if p.Slug, p.OldSlugs, err = s.nextSlug(ctx, current, p); err != nil {
+		return models.Post{}, err
+	}
+	p.Tags = s.tags.tags(p.Tags)
+
+	now := time.Now().UTC()
+	schedule, err := prepareSchedule(&p, now)
+	if err != nil {
+		return models.Post{}, err
+	}
+	if p.Status, err = nextStatus(current, p, schedule); err != nil {
+		return models.Post{}, err
+	}
+	// Una programación nueva (o un cambio de estado con una pendiente) debe poder
+	// aplicarse; las anteriores que ya no pueden las cancela el scheduler al vencer.
+	scheduleChanged := !samePublishedAt(p.PublishAt, current.PublishAt) || p.Status != postStatus(current)
+	if p.PublishAt != nil && scheduleChanged && !canPublish(p.Status) {
+		return models.Post{}, Wrap(errIllegalTransition, ErrConflict, p.Status+" -> published (publishAt)")
+	}
+	if !current.Published && p.Published && p.PublishedAt == nil {
+		p.PublishedAt = &now
+	}
+	p.UpdatedAt = &now
+
+	updated, err := s.repo.Update(ctx, current.ID, p, current.Version)
+	if err != nil {
+		return models.Post{}, logDBError(ctx, "update post", err)
+	}
+	s.recordRevision(ctx, current, editor)
+	s.recordTransition(ctx, current, updated, editor, reason)
+	s.refreshTagCounts(ctx, current, updated)
 	return updated, nil
 }
 
-// DeletePostByID elimina un Post por id.
+// DeletePostByID envía un Post a la papelera (soft delete).
+//
+// Reglas:
+//   - Fija deletedAt=now y deletedBy; el post deja de aparecer en lecturas y listados
+//     hasta que se restaure (RestorePostByID) o se purgue.
 //
 // Parámetros:
 //   - ctx: contexto de cancelación/timeout.
 //   - idHex: ObjectID en hex.
+//   - deletedBy: quién elimina (se guarda tal cual).
+//   - ifVersions: versiones aceptadas (If-Match); nil = sin precondición.
 //
 // Retornos:
 //   - nil si elimina correctamente.
 //   - error con sentinelas: ErrInvalidID si el id es inválido;
-//     ErrNotFound si no existía un documento con ese id; ErrDB en fallas del driver.
-func DeletePostByID(ctx context.Context, idHex string) error {
-	oid, err := primitive.ObjectIDFromHex(idHex)
-	if err != nil {
-		return Wrap(err, ErrInvalidID, "parse objectid")
-	}
+//     ErrNotFound si no existe o ya estaba en la papelera; ErrConflict si la versión
+//     no es aceptada; ErrDB en fallas del driver.
+func (s *PostService) DeletePostByID(ctx context.Context, idHex, deletedBy string, ifVersions []int64) (err error) {
+	ctx, span := startSpan(ctx, "PostService.DeletePostByID", attribute.String("post.id", idHex))
+	defer endSpan(span, &err)
 
-	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
-	defer cancel()
-
-	res, err := DB.Collection("posts").DeleteOne(ctx, bson.M{"_id": oid})
+	current, err := s.getVisible(ctx, idHex)
 	if err != nil {
-		return Wrap(err, ErrDB, "delete post")
+		return err
+	}
+	if err := checkVersion(current, ifVersions); err != nil {
+		return err
 	}
-	if res.DeletedCount == 0 {
-		return Wrap(mongo.ErrNoDocuments, ErrNotFound, "post not found")
+	now := time.Now().UTC()
+	deleted, err := s.repo.SetDeleted(ctx, current.ID, &now, deletedBy, current.Version)
+	if err != nil {
+		return logDBError(ctx, "delete post", err)
 	}
+	s.refreshTagCounts(ctx, current, deleted)
 	return nil
 }
 
+// checkVersion retorna ErrVersionMismatch si ifVersions no es nil y no contiene la versión de post.
+func checkVersion(post models.Post, ifVersions []int64) error {
+	if ifVersions == nil {
+		return nil
+	}
+	for _, v := range ifVersions {
+		if v == post.Version {
+			return nil
+		}
+	}
+	return Wrap(errVersionMismatch, ErrVersionMismatch, fmt.Sprintf("current version %d", post.Version))
+}
+
 // TagMetric representa la métrica de cantidad de posts por etiqueta.
 type TagMetric struct {
 	Tag   string `bson:"_id"  json:"tag"`
@@ -189,69 +664,54 @@ type TagMetric struct {
 //   - ctx: contexto de cancelación/timeout.
 //   - limit: máximo de filas a retornar (si <=0 se usa 10; si >100 se trunca a 100).
 //   - onlyPublished: si no es nil, filtra por published==*onlyPublished.
+//   - status: si no está vacío, filtra por estado editorial.
 //
 // Retornos:
 //   - slice ordenado descendentemente por Count.
-//   - error con sentinelas: ErrDB ante errores del pipeline/cursor.
-func GetPostsMetricsByTag(ctx context.Context, limit int, onlyPublished *bool) ([]TagMetric, error) {
+//   - error con sentinelas: ErrInvalidInput si status no es un estado del flujo;
+//     ErrDB ante errores del pipeline/cursor.
+func (s *PostService) GetPostsMetricsByTag(ctx context.Context, limit int, onlyPublished *bool, status string) (_ []TagMetric, err error) {
+	ctx, span := startSpan(ctx, "PostService.GetPostsMetricsByTag")
+	defer endSpan(span, &err)
+
+	if status != "" && !ValidStatus(status) {
+		return nil, Wrap(errUnknownStatus, ErrInvalidInput, "status")
+	}
+
 	if limit <= 0 {
 		limit = 10
 	}
 	if limit > 100 {
 		limit = 100
 	}
-
-	match := bson.M{"tags": bson.M{"$type": "string"}}
-	if onlyPublished != nil {
-		match["published"] = *onlyPublished
-	}
-
-	pipeline := mongo.Pipeline{
-		{{Key: "$match", Value: match}},
-		{{Key: "$unwind", Value: "$tags"}},
-		{{Key: "$match", Value: bson.M{"tags": bson.M{"$ne": ""}}}},
-		{{Key: "$group", Value: bson.M{"_id": "$tags", "count": bson.M{"$sum": 1}}}},
-		{{Key: "$sort", Value: bson.M{"count": -1}}},
-		{{Key: "$limit", Value: limit}},
-	}
-
-	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
-	defer cancel()
-
-	cur, err := DB.Collection("posts").Aggregate(ctx, pipeline)
-	if err != nil {
-		return nil, Wrap(err, ErrDB, "aggregate by-tag")
-	}
-	defer cur.Close(ctx)
-
-	out := make([]TagMetric, 0, limit)
-	for cur.Next(ctx) {
-		var m TagMetric
-		if err := cur.Decode(&m); err != nil {
-			return nil, Wrap(err, ErrDB, "decode metric row")
-		}
-		out = append(out, m)
-	}
-	if err := cur.Err(); err != nil {
-		return nil, Wrap(err, ErrDB, "cursor error")
-	}
-	return out, nil
+	metrics, err := s.repo.AggregateByTag(ctx, limit, onlyPublished, status)
+	return metrics, logDBError(ctx, "aggregate by tag", err)
 }
 
 // ListPostsParams define filtros de búsqueda/orden paginada.
 type ListPostsParams struct {
 	// Q aplica búsqueda de texto (requiere índice en {title: "text", content: "text"}).
 	Q string
-	// Tag filtra posts que contengan exactamente esa etiqueta.
+	// Tag filtra posts que contengan esa etiqueta (se normaliza como al escribir).
 	Tag string
 	// Published: nil = no filtra; true/false = filtra por estado de publicación.
 	Published *bool
+	// Status: "" = no filtra; si no, sólo posts en ese estado editorial (ver ValidStatus).
+	Status string
+	// AuthorID: cero = no filtra; si no, sólo posts de ese autor.
+	AuthorID primitive.ObjectID
 	// Page: número de página 1-based.
 	Page int
-	// Limit: tamaño de página (se trunca a maxPageLimit).
+	// Limit: tamaño de página (se trunca a PostServiceOptions.MaxPageLimit).
 	Limit int
 	// SortField: "publishedAt" | "-publishedAt". Default: "-publishedAt".
 	SortField string
+	// Deleted: false = excluye la papelera; true = sólo posts eliminados,
+	// ordenados por deletedAt descendente (SortField se ignora).
+	Deleted bool
+	// Scheduled: true = sólo posts con publishAt o unpublishAt pendiente, ordenados
+	// por la fecha programada más próxima (SortField se ignora).
+	Scheduled bool
 }
 
 // ListPostsResult contiene los ítems y metadatos de paginación.
@@ -271,71 +731,43 @@ type ListPostsResult struct {
 //
 // Retornos:
 //   - Listado paginado + total y totalPages.
-//   - error con sentinelas: ErrDB ante fallas del driver.
+//   - error con sentinelas: ErrInvalidInput si Status no es un estado del flujo;
+//     ErrDB ante fallas del driver.
 //
 // Notas:
-//   - Índices recomendados: {title:"text", content:"text"} para Q; {published:1, publishedAt:-1} para listados.
-func ListPosts(ctx context.Context, p ListPostsParams) (ListPostsResult, error) {
-	if p.Page <= 0 {
-		p.Page = 1
-	}
-	if p.Limit <= 0 {
-		p.Limit = 10
-	}
-	if p.Limit > maxPageLimit {
-		p.Limit = maxPageLimit
+//   - Page/Limit y Tag se normalizan aquí antes de delegar en el repositorio.
+//   - Cada item trae CommentCount (comentarios aprobados) y Reactions, con una consulta
+//     de cada uno por página.
+func (s *PostService) ListPosts(ctx context.Context, p ListPostsParams) (_ ListPostsResult, err error) {
+	ctx, span := startSpan(ctx, "PostService.ListPosts")
+	defer endSpan(span, &err)
+
+	if p.Status != "" && !ValidStatus(p.Status) {
+		return ListPostsResult{}, Wrap(errUnknownStatus, ErrInvalidInput, "status")
 	}
 
-	filter := bson.M{}
-	if p.Q != "" {
-		filter["$text"] = bson.M{"$search": p.Q}
-	}
 	if p.Tag != "" {
-		filter["tags"] = p.Tag
+		p.Tag = s.tags.tag(p.Tag)
 	}
-	if p.Published != nil {
-		filter["published"] = *p.Published
+	if p.Page <= 0 {
+		p.Page = 1
 	}
-
-	var sort bson.D
-	switch p.SortField {
-	case "publishedAt":
-		sort = bson.D{{Key: "publishedAt", Value: 1}}
-	case "-publishedAt":
-		sort = bson.D{{Key: "publishedAt", Value: -1}}
-	default:
-		sort = bson.D{{Key: "publishedAt", Value: -1}}
+	if p.Limit <= 0 {
+		p.Limit = 10
 	}
-
-	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
-	defer cancel()
-
-	total, err := DB.Collection("posts").CountDocuments(ctx, filter)
-	if err != nil {
-		return ListPostsResult{}, Wrap(err, ErrDB, "count posts")
+	if p.Limit > s.opts.MaxPageLimit {
+		p.Limit = s.opts.MaxPageLimit
 	}
 
-	opts := options.Find().
-		SetSort(sort).
-		SetSkip(int64((p.Page-1)*p.Limit)).
-		SetLimit(int64(p.Limit))
-
-	cur, err := DB.Collection("posts").Find(ctx, filter, opts)
+	items, total, err := s.repo.List(ctx, p)
 	if err != nil {
-		return ListPostsResult{}, Wrap(err, ErrDB, "find posts")
+		return ListPostsResult{}, logDBError(ctx, "list posts", err)
 	}
-	defer cur.Close(ctx)
-
-	items := make([]models.Post, 0, p.Limit)
-	for cur.Next(ctx) {
-		var post models.Post
-		if err := cur.Decode(&post); err != nil {
-			return ListPostsResult{}, Wrap(err, ErrDB, "decode post")
-		}
-		items = append(items, post)
+	if err := s.countComments(ctx, items); err != nil {
+		return ListPostsResult{}, err
 	}
-	if err := cur.Err(); err != nil {
-		return ListPostsResult{}, Wrap(err, ErrDB, "cursor error")
+	if err := s.attachReactions(ctx, items); err != nil {
+		return ListPostsResult{}, err
 	}
 
 	totalPages := (total + int64(p.Limit) - 1) / int64(p.Limit)
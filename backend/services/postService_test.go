@@ -0,0 +1,290 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"blog-api/models"
+)
+
+// testPosts arma un PostService sobre repositorios en memoria con un autor creado.
+type testPosts struct {
+	svc    *PostService
+	repo   *MemoryPostRepository
+	author models.Author
+}
+
+func newTestPosts(t *testing.T) *testPosts {
+	t.Helper()
+	repo := NewMemoryPostRepository()
+	authors := NewMemoryAuthorRepository()
+	author, err := NewAuthorService(authors, repo).CreateAuthor(context.Background(), models.Author{Name: "Ana García"})
+	if err != nil {
+		t.Fatalf("create author: %v", err)
+	}
+	svc := NewPostService(repo, NewMemoryRevisionRepository(), NewMemoryTransitionRepository(), authors,
+		NewMemoryTagCountRepository(), NewMemoryCommentRepository(), NewMemoryReactionRepository(), PostServiceOptions{})
+	return &testPosts{svc: svc, repo: repo, author: author}
+}
+
+// create crea un post de author con title y tags y retorna su id en hex.
+func (tp *testPosts) create(t *testing.T, title string, published bool, tags ...string) string {
+	t.Helper()
+	id, err := tp.svc.CreatePost(context.Background(), models.Post{
+		Title: title, Content: "contenido", AuthorID: tp.author.ID, Tags: tags, Published: published,
+	})
+	if err != nil {
+		t.Fatalf("create %q: %v", title, err)
+	}
+	return id.Hex()
+}
+
+func TestCreateAndGetPost(t *testing.T) {
+	tp := newTestPosts(t)
+	id := tp.create(t, "Primer post", true, " Go ", "go", "Mongo")
+
+	got, err := tp.svc.GetPostByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Slug != "primer-post" || got.Version != 1 || got.Author != "Ana García" {
+		t.Fatalf("post creado: slug %q version %d author %q", got.Slug, got.Version, got.Author)
+	}
+	if got.Status != "published" || got.PublishedAt == nil {
+		t.Fatalf("publicado: status %q publishedAt %v", got.Status, got.PublishedAt)
+	}
+	if len(got.Tags) != 2 || got.Tags[0] != "go" || got.Tags[1] != "mongo" {
+		t.Fatalf("tags normalizadas: %v", got.Tags)
+	}
+
+	if _, err := tp.svc.GetPostByID(context.Background(), "no-es-un-id"); !errors.Is(err, ErrInvalidID) {
+		t.Fatalf("id inválido: %v, want ErrInvalidID", err)
+	}
+}
+
+func TestCreatePostSlugs(t *testing.T) {
+	tp := newTestPosts(t)
+	tp.create(t, "Primer post", false)
+
+	second := tp.create(t, "Primer post", false)
+	got, err := tp.svc.GetPostByID(context.Background(), second)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Slug != "primer-post-2" {
+		t.Fatalf("slug generado en uso: %q, want primer-post-2", got.Slug)
+	}
+
+	_, err = tp.svc.CreatePost(context.Background(), models.Post{
+		Title: "Otro post", Slug: "primer-post", Content: "contenido", AuthorID: tp.author.ID,
+	})
+	if !errors.Is(err, ErrConflict) || errors.Is(err, ErrVersionMismatch) {
+		t.Fatalf("slug explícito en uso: %v, want ErrConflict sin ErrVersionMismatch", err)
+	}
+}
+
+func TestUpdatePost(t *testing.T) {
+	tp := newTestPosts(t)
+	id := tp.create(t, "Primer post", false, "go")
+
+	got, err := tp.svc.UpdatePostByID(context.Background(), id, models.Post{
+		Title: "Primer post editado", Content: "nuevo", AuthorID: tp.author.ID, Tags: []string{"Mongo"},
+	}, "ana", []int64{1})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if got.Version != 2 || got.Content != "nuevo" || got.Slug != "primer-post-editado" {
+		t.Fatalf("update: version %d content %q slug %q", got.Version, got.Content, got.Slug)
+	}
+	if len(got.OldSlugs) != 1 || got.OldSlugs[0] != "primer-post" {
+		t.Fatalf("slug anterior: %v", got.OldSlugs)
+	}
+
+	title := "Primer post corregido"
+	got, err = tp.svc.PatchPostByID(context.Background(), id, PostPatch{Title: &title}, "ana", nil)
+	if err != nil {
+		t.Fatalf("patch: %v", err)
+	}
+	if got.Version != 3 || got.Title != title || got.Content != "nuevo" {
+		t.Fatalf("patch: version %d title %q content %q", got.Version, got.Title, got.Content)
+	}
+}
+
+func TestUpdatePostStaleVersionIsVersionMismatch(t *testing.T) {
+	tp := newTestPosts(t)
+	id := tp.create(t, "Primer post", false)
+	content := "nuevo"
+
+	_, err := tp.svc.UpdatePostByID(context.Background(), id, models.Post{
+		Title: "Primer post", Content: content, AuthorID: tp.author.ID,
+	}, "ana", []int64{7})
+	if !errors.Is(err, ErrVersionMismatch) {
+		t.Fatalf("update con versión vencida: %v, want ErrVersionMismatch", err)
+	}
+	if _, err := tp.svc.PatchPostByID(context.Background(), id, PostPatch{Content: &content}, "ana", []int64{7}); !errors.Is(err, ErrVersionMismatch) {
+		t.Fatalf("patch con versión vencida: %v, want ErrVersionMismatch", err)
+	}
+	if err := tp.svc.DeletePostByID(context.Background(), id, "ana", []int64{7}); !errors.Is(err, ErrVersionMismatch) {
+		t.Fatalf("delete con versión vencida: %v, want ErrVersionMismatch", err)
+	}
+
+	got, err := tp.svc.PatchPostByID(context.Background(), id, PostPatch{Content: &content}, "ana", []int64{7, 1})
+	if err != nil {
+		t.Fatalf("patch con una de las versiones vigente: %v", err)
+	}
+	if got.Version != 2 {
+		t.Fatalf("version %d, want 2", got.Version)
+	}
+}
+
+func TestConcurrentWriteIsVersionMismatch(t *testing.T) {
+	tp := newTestPosts(t)
+	id := tp.create(t, "Primer post", false)
+	before, err := tp.svc.GetPostByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	content := "nuevo"
+	if _, err := tp.svc.PatchPostByID(context.Background(), id, PostPatch{Content: &content}, "ana", nil); err != nil {
+		t.Fatalf("patch: %v", err)
+	}
+
+	// Una escritura condicionada a la versión leída antes del patch pierde la carrera.
+	before.Content = "pisado"
+	if _, err := tp.repo.Update(context.Background(), before.ID, before, before.Version); !errors.Is(err, ErrVersionMismatch) {
+		t.Fatalf("update con versión leída antes: %v, want ErrVersionMismatch", err)
+	}
+}
+
+func TestUpdatePostTakenSlugIsConflictNotVersionMismatch(t *testing.T) {
+	tp := newTestPosts(t)
+	tp.create(t, "Primer post", false)
+	id := tp.create(t, "Segundo post", false)
+	slug := "primer-post"
+
+	_, err := tp.svc.PatchPostByID(context.Background(), id, PostPatch{Slug: &slug}, "ana", []int64{1})
+	if !errors.Is(err, ErrConflict) || errors.Is(err, ErrVersionMismatch) {
+		t.Fatalf("slug en uso con versión vigente: %v, want ErrConflict sin ErrVersionMismatch", err)
+	}
+}
+
+func TestDeletePost(t *testing.T) {
+	tp := newTestPosts(t)
+	id := tp.create(t, "Primer post", false)
+
+	if err := tp.svc.DeletePostByID(context.Background(), id, "ana", []int64{1}); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := tp.svc.GetPostByID(context.Background(), id); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("get tras delete: %v, want ErrNotFound", err)
+	}
+	if err := tp.svc.DeletePostByID(context.Background(), id, "ana", nil); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("delete repetido: %v, want ErrNotFound", err)
+	}
+}
+
+func TestListPosts(t *testing.T) {
+	tp := newTestPosts(t)
+	tp.create(t, "Primer post", true, "go")
+	tp.create(t, "Segundo post", false, "go", "mongo")
+	tp.create(t, "Tercer post", true, "mongo")
+	deleted := tp.create(t, "Cuarto post", true, "go")
+	if err := tp.svc.DeletePostByID(context.Background(), deleted, "ana", nil); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	res, err := tp.svc.ListPosts(context.Background(), ListPostsParams{Limit: 2})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if res.Total != 3 || res.TotalPages != 2 || len(res.Items) != 2 || res.Page != 1 {
+		t.Fatalf("list: total %d pages %d items %d page %d", res.Total, res.TotalPages, len(res.Items), res.Page)
+	}
+
+	published := true
+	res, err = tp.svc.ListPosts(context.Background(), ListPostsParams{Tag: " GO ", Published: &published})
+	if err != nil {
+		t.Fatalf("list by tag: %v", err)
+	}
+	if res.Total != 1 || res.Items[0].Title != "Primer post" {
+		t.Fatalf("list publicados con tag go: total %d items %v", res.Total, res.Items)
+	}
+
+	if _, err := tp.svc.ListPosts(context.Background(), ListPostsParams{Status: "borrado"}); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("status desconocido: %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestGetPostsMetricsByTag(t *testing.T) {
+	tp := newTestPosts(t)
+	tp.create(t, "Primer post", true, "go")
+	tp.create(t, "Segundo post", false, "go", "mongo")
+	tp.create(t, "Tercer post", true, "go", "sqlite")
+
+	metrics, err := tp.svc.GetPostsMetricsByTag(context.Background(), 0, nil, "")
+	if err != nil {
+		t.Fatalf("metrics: %v", err)
+	}
+	if len(metrics) != 3 || metrics[0] != (TagMetric{Tag: "go", Count: 3}) {
+		t.Fatalf("metrics: %v", metrics)
+	}
+
+	unpublished := false
+	metrics, err = tp.svc.GetPostsMetricsByTag(context.Background(), 1, &unpublished, "")
+	if err != nil {
+		t.Fatalf("metrics no publicados: %v", err)
+	}
+	if len(metrics) != 1 || metrics[0].Count != 1 {
+		t.Fatalf("metrics no publicados con limit 1: %v", metrics)
+	}
+
+	if _, err := tp.svc.GetPostsMetricsByTag(context.Background(), 10, nil, "borrado"); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("status desconocido: %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestUpdatePostExplicitCurrentSlugIsKept(t *testing.T) {
+	tp := newTestPosts(t)
+	id := tp.create(t, "Primer post", false)
+	title, slug := "Primer post renombrado", "primer-post"
+
+	got, err := tp.svc.PatchPostByID(context.Background(), id, PostPatch{Title: &title, Slug: &slug}, "ana", nil)
+	if err != nil {
+		t.Fatalf("patch: %v", err)
+	}
+	if got.Slug != "primer-post" || got.OldSlugs != nil {
+		t.Fatalf("slug vigente explícito: slug %q old %v", got.Slug, got.OldSlugs)
+	}
+	got, err = tp.svc.UpdatePostByID(context.Background(), id, models.Post{
+		Title: "Primer post otra vez", Slug: "primer-post", Content: "contenido", AuthorID: tp.author.ID,
+	}, "ana", nil)
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if got.Slug != "primer-post" {
+		t.Fatalf("PUT con slug vigente: %q", got.Slug)
+	}
+
+	// Por JSON Patch el documento trae el slug: sólo una operación sobre /slug lo fija.
+	got, err = tp.svc.JSONPatchPostByID(context.Background(), id, []PatchOperation{
+		{Op: "replace", Path: "/title", Value: json.RawMessage(`"Título nuevo"`)},
+	}, func(models.Post) error { return nil }, "ana", nil)
+	if err != nil {
+		t.Fatalf("json patch: %v", err)
+	}
+	if got.Slug != "titulo-nuevo" {
+		t.Fatalf("json patch sin /slug: %q, want titulo-nuevo", got.Slug)
+	}
+	got, err = tp.svc.JSONPatchPostByID(context.Background(), id, []PatchOperation{
+		{Op: "replace", Path: "/title", Value: json.RawMessage(`"Otro título"`)},
+		{Op: "replace", Path: "/slug", Value: json.RawMessage(`"titulo-nuevo"`)},
+	}, func(models.Post) error { return nil }, "ana", nil)
+	if err != nil {
+		t.Fatalf("json patch con /slug: %v", err)
+	}
+	if got.Slug != "titulo-nuevo" {
+		t.Fatalf("json patch con /slug vigente: %q", got.Slug)
+	}
+}
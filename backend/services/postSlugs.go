@@ -0,0 +1,157 @@
+// services/postSlugs.go
+//
+// Paquete services: asignación de slugs a posts y búsqueda por slug.
+//
+// Convenciones:
+//   - Al crear, el slug se genera del título salvo que el cliente envíe uno; un slug
+//     explícito en uso responde ErrConflict, uno generado prueba sufijos numéricos.
+//   - Al editar, el slug cambia si el cliente envía otro o si el título cambia de forma
+//     que su slug también lo haría; el anterior pasa a OldSlugs para redirigir.
+//   - El índice único del almacenamiento es la garantía final: una carrera entre dos
+//     altas con el mismo título se resuelve reintentando con el siguiente sufijo.
+package services
+
+import (
+	"context"
+	"errors"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GetPostBySlug recupera un Post por su slug vigente o por uno anterior.
+//
+// Parámetros:
+//   - ctx: contexto de cancelación/timeout.
+//   - slug: slug buscado.
+//
+// Retornos:
+//   - Post encontrado, con sus reacciones; si post.Slug != slug, slug es un slug
+//     anterior y el llamador debe redirigir al vigente.
+//   - error con sentinelas: ErrNotFound si ningún post tiene ni tuvo ese slug o si está
+//     en la papelera; ErrDB si falla el driver.
+func (s *PostService) GetPostBySlug(ctx context.Context, slug string) (_ models.Post, err error) {
+	ctx, span := startSpan(ctx, "PostService.GetPostBySlug", attribute.String("post.slug", slug))
+	defer endSpan(span, &err)
+
+	post, err := s.repo.GetBySlug(ctx, slug)
+	if err != nil {
+		return models.Post{}, logDBError(ctx, "get post by slug", err)
+	}
+	if post.DeletedAt != nil {
+		return models.Post{}, Wrap(errPostDeleted, ErrNotFound, "post not found")
+	}
+	items := []models.Post{post}
+	if err := s.attachReactions(ctx, items); err != nil {
+		return models.Post{}, err
+	}
+	return items[0], nil
+}
+
+// createWithSlug inserta p asignando su slug (ver CreatePost).
+func (s *PostService) createWithSlug(ctx context.Context, p models.Post) (primitive.ObjectID, error) {
+	if p.Slug != "" {
+		if err := s.checkExplicitSlug(ctx, p.Slug, primitive.NilObjectID); err != nil {
+			return primitive.NilObjectID, err
+		}
+		id, err := s.repo.Create(ctx, p)
+		return id, logDBError(ctx, "create post", err)
+	}
+
+	base := slugify(p.Title)
+	for n := 1; n <= maxSlugAttempts; {
+		slug, next, err := s.freeSlug(ctx, base, primitive.NilObjectID, n)
+		if err != nil {
+			return primitive.NilObjectID, err
+		}
+		p.Slug = slug
+		id, err := s.repo.Create(ctx, p)
+		if errors.Is(err, ErrConflict) {
+			// Otro alta tomó el mismo slug entre la consulta y la inserción.
+			n = next + 1
+			continue
+		}
+		return id, logDBError(ctx, "create post", err)
+	}
+	return primitive.NilObjectID, Wrap(errSlugTaken, ErrConflict, "generate slug")
+}
+
+// nextSlug decide el slug y los slugs anteriores que tendrá current al guardarse con
+// los valores de p. Un p.Slug no vacío es explícito y se respeta aunque sea el vigente
+// y cambie el título; sólo con p.Slug vacío se deriva del título si éste cambia.
+func (s *PostService) nextSlug(ctx context.Context, current, p models.Post) (string, []string, error) {
+	slug := current.Slug
+	switch {
+	case p.Slug != "":
+		if p.Slug != current.Slug {
+			if err := s.checkExplicitSlug(ctx, p.Slug, current.ID); err != nil {
+				return "", nil, err
+			}
+		}
+		slug = p.Slug
+	case current.Slug == "" || slugify(p.Title) != slugify(current.Title):
+		var err error
+		if slug, _, err = s.freeSlug(ctx, slugify(p.Title), current.ID, 1); err != nil {
+			return "", nil, err
+		}
+	}
+
+	old := make([]string, 0, len(current.OldSlugs)+1)
+	for _, o := range current.OldSlugs {
+		if o != slug {
+			old = append(old, o)
+		}
+	}
+	if current.Slug != "" && current.Slug != slug {
+		old = append(old, current.Slug)
+	}
+	if len(old) == 0 {
+		old = nil
+	}
+	return slug, old, nil
+}
+
+// checkExplicitSlug valida un slug enviado por el cliente y retorna ErrConflict si
+// otro post (distinto de self) lo tiene como vigente o anterior.
+func (s *PostService) checkExplicitSlug(ctx context.Context, slug string, self primitive.ObjectID) error {
+	if err := validateSlug(slug); err != nil {
+		return err
+	}
+	owner, taken, err := s.slugOwner(ctx, slug)
+	if err != nil {
+		return err
+	}
+	if taken && owner != self {
+		return Wrap(errSlugTaken, ErrConflict, "slug "+slug)
+	}
+	return nil
+}
+
+// freeSlug retorna el primer candidato de base desde el intento n que no pertenece a
+// otro post (distinto de self), junto con el número de intento usado.
+func (s *PostService) freeSlug(ctx context.Context, base string, self primitive.ObjectID, n int) (string, int, error) {
+	for ; n <= maxSlugAttempts; n++ {
+		candidate := slugCandidate(base, n)
+		owner, taken, err := s.slugOwner(ctx, candidate)
+		if err != nil {
+			return "", 0, err
+		}
+		if !taken || owner == self {
+			return candidate, n, nil
+		}
+	}
+	return "", 0, Wrap(errSlugTaken, ErrConflict, "generate slug")
+}
+
+// slugOwner retorna el post que tiene o tuvo slug (incluida la papelera).
+func (s *PostService) slugOwner(ctx context.Context, slug string) (primitive.ObjectID, bool, error) {
+	post, err := s.repo.GetBySlug(ctx, slug)
+	if errors.Is(err, ErrNotFound) {
+		return primitive.NilObjectID, false, nil
+	}
+	if err != nil {
+		return primitive.NilObjectID, false, logDBError(ctx, "get post by slug", err)
+	}
+	return post.ID, true, nil
+}
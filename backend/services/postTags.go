@@ -0,0 +1,93 @@
+// services/postTags.go
+//
+// Paquete services: normalización de etiquetas de posts.
+//
+// Convenciones:
+//   - Una etiqueta normalizada no tiene espacios en los extremos, está en minúsculas y
+//     sus espacios internos se reducen a uno ("  Go  Lang " → "go lang").
+//   - Los alias (configuración tags.aliases) se aplican después de normalizar: la
+//     etiqueta alias se guarda como su canónica ("golang" → "go").
+//   - Se normaliza al escribir (alta, edición, patch, restauración de revisiones); las
+//     etiquetas vacías se descartan y las repetidas conservan su primera posición.
+//   - Los posts anteriores se normalizan con los alias configurados (migración 10 en
+//     Mongo, una sola vez; al abrir la base con memory y sqlite); para aplicar en Mongo
+//     un alias agregado después de la migración se usa MergeTags.
+package services
+
+import "strings"
+
+// tagNormalizer mapea etiquetas alias a su canónica; ambas ya normalizadas. El valor
+// cero (nil) sólo normaliza.
+type tagNormalizer map[string]string
+
+// newTagNormalizer crea un tagNormalizer a partir de aliases (alias → canónica),
+// normalizando ambos lados y descartando los pares vacíos o que se apuntan a sí mismos.
+func newTagNormalizer(aliases map[string]string) tagNormalizer {
+	n := make(tagNormalizer, len(aliases))
+	for alias, canonical := range aliases {
+		alias, canonical = normalizeTag(alias), normalizeTag(canonical)
+		if alias != "" && canonical != "" && alias != canonical {
+			n[alias] = canonical
+		}
+	}
+	return n
+}
+
+// normalizeTag recorta, pasa a minúsculas y colapsa los espacios internos de tag.
+func normalizeTag(tag string) string {
+	return strings.Join(strings.Fields(strings.ToLower(tag)), " ")
+}
+
+// tag retorna la forma normalizada de t con su alias aplicado.
+func (n tagNormalizer) tag(t string) string {
+	t = normalizeTag(t)
+	if canonical, ok := n[t]; ok {
+		return canonical
+	}
+	return t
+}
+
+// tags normaliza cada etiqueta de ts, descarta las vacías y las repetidas; nil se
+// conserva como nil.
+func (n tagNormalizer) tags(ts []string) []string {
+	if ts == nil {
+		return nil
+	}
+	out := make([]string, 0, len(ts))
+	for _, t := range ts {
+		if t = n.tag(t); t != "" && !containsString(out, t) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// replaceTags retorna tags con cada etiqueta de from reemplazada por to, sin duplicar
+// (to queda en la posición de su primera aparición); changed indica si hubo cambios.
+func replaceTags(tags, from []string, to string) (_ []string, changed bool) {
+	out := make([]string, 0, len(tags))
+	for _, t := range tags {
+		if containsString(from, t) {
+			t, changed = to, true
+		}
+		if containsString(out, t) {
+			changed = true
+			continue
+		}
+		out = append(out, t)
+	}
+	return out, changed
+}
+
+// equalTags indica si a y b tienen las mismas etiquetas en el mismo orden.
+func equalTags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
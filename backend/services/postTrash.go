@@ -0,0 +1,146 @@
+// services/postTrash.go
+//
+// Paquete services: papelera de posts (restaurar, borrado definitivo y purga).
+//
+// Convenciones:
+//   - DeletePostByID sólo marca deletedAt/deletedBy; el borrado físico ocurre aquí,
+//     por acción de un administrador (PurgePostByID) o al vencer la retención (PurgeTrash).
+//   - La purga es idempotente (DeleteMany por fecha): varias instancias pueden
+//     ejecutarla a la vez sin coordinarse.
+//   - Al purgar un post también se borran su historial de revisiones, sus transiciones
+//     y sus comentarios (mientras está en la papelera los comentarios quedan ocultos).
+//   - GET /api/trash usa ListPosts con ListPostsParams.Deleted=true.
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var (
+	// errPostDeleted es la causa cuando el post existe pero está en la papelera.
+	errPostDeleted = errors.New("post is in trash")
+	// errPostNotDeleted es la causa cuando se espera un post en la papelera y no lo está.
+	errPostNotDeleted = errors.New("post is not in trash")
+)
+
+// RestorePostByID saca un Post de la papelera.
+//
+// Parámetros:
+//   - ctx: contexto de cancelación/timeout.
+//   - idHex: ObjectID en hex.
+//   - ifVersions: versiones aceptadas (If-Match); nil = sin precondición.
+//
+// Retornos:
+//   - Post restaurado (sin deletedAt/deletedBy, version incrementada).
+//   - error con sentinelas: ErrInvalidID, ErrNotFound, ErrConflict (no estaba en la
+//     papelera o la versión no es aceptada), ErrDB.
+func (s *PostService) RestorePostByID(ctx context.Context, idHex string, ifVersions []int64) (_ models.Post, err error) {
+	ctx, span := startSpan(ctx, "PostService.RestorePostByID", attribute.String("post.id", idHex))
+	defer endSpan(span, &err)
+
+	current, err := s.getIncludingDeleted(ctx, idHex)
+	if err != nil {
+		return models.Post{}, err
+	}
+	if current.DeletedAt == nil {
+		return models.Post{}, Wrap(errPostNotDeleted, ErrConflict, "restore post")
+	}
+	if err := checkVersion(current, ifVersions); err != nil {
+		return models.Post{}, err
+	}
+	restored, err := s.repo.SetDeleted(ctx, current.ID, nil, "", current.Version)
+	if err != nil {
+		return models.Post{}, logDBError(ctx, "restore post", err)
+	}
+	s.refreshTagCounts(ctx, current, restored)
+	return restored, nil
+}
+
+// PurgePostByID elimina definitivamente un Post que está en la papelera.
+//
+// Retornos:
+//   - error con sentinelas: ErrInvalidID, ErrNotFound, ErrConflict si el post no
+//     está en la papelera (primero debe eliminarse), ErrDB.
+func (s *PostService) PurgePostByID(ctx context.Context, idHex string) (err error) {
+	ctx, span := startSpan(ctx, "PostService.PurgePostByID", attribute.String("post.id", idHex))
+	defer endSpan(span, &err)
+
+	current, err := s.getIncludingDeleted(ctx, idHex)
+	if err != nil {
+		return err
+	}
+	if current.DeletedAt == nil {
+		return Wrap(errPostNotDeleted, ErrConflict, "purge post")
+	}
+	if err := s.repo.Delete(ctx, current.ID, current.Version); err != nil {
+		return logDBError(ctx, "purge post", err)
+	}
+	s.deleteHistory(ctx, []primitive.ObjectID{current.ID})
+	return nil
+}
+
+// PurgeTrash elimina definitivamente los posts que llevan en la papelera más de retention.
+//
+// Retornos:
+//   - cantidad de posts eliminados.
+//   - error con sentinel ErrDB si falla el almacenamiento.
+func (s *PostService) PurgeTrash(ctx context.Context, retention time.Duration) (_ int64, err error) {
+	ctx, span := startSpan(ctx, "PostService.PurgeTrash")
+	defer endSpan(span, &err)
+
+	ids, err := s.repo.PurgeDeleted(ctx, time.Now().UTC().Add(-retention))
+	if err != nil {
+		return 0, logDBError(ctx, "purge trash", err)
+	}
+	span.SetAttributes(attribute.Int("trash.purged", len(ids)))
+	s.deleteHistory(ctx, ids)
+	return int64(len(ids)), nil
+}
+
+// deleteHistory borra las revisiones, transiciones, comentarios y reacciones de posts ya
+// purgados; un error sólo se registra (los registros huérfanos no son visibles porque el
+// post ya no existe).
+func (s *PostService) deleteHistory(ctx context.Context, ids []primitive.ObjectID) {
+	if _, err := s.revisions.DeleteByPosts(ctx, ids); err != nil {
+		slog.WarnContext(ctx, "no se pudo borrar el historial de posts purgados",
+			slog.Int("posts", len(ids)), slog.String("error", err.Error()))
+	}
+	if _, err := s.transitions.DeleteByPosts(ctx, ids); err != nil {
+		slog.WarnContext(ctx, "no se pudieron borrar las transiciones de posts purgados",
+			slog.Int("posts", len(ids)), slog.String("error", err.Error()))
+	}
+	if _, err := s.comments.DeleteByPosts(ctx, ids); err != nil {
+		slog.WarnContext(ctx, "no se pudieron borrar los comentarios de posts purgados",
+			slog.Int("posts", len(ids)), slog.String("error", err.Error()))
+	}
+	if _, err := s.reactions.DeleteByPosts(ctx, ids); err != nil {
+		slog.WarnContext(ctx, "no se pudieron borrar las reacciones de posts purgados",
+			slog.Int("posts", len(ids)), slog.String("error", err.Error()))
+	}
+}
+
+// RunTrashPurge ejecuta PurgeTrash al iniciar y luego cada interval, hasta que ctx
+// se cancele. Pensado para correr como worker de fondo; los errores sólo se registran.
+func (s *PostService) RunTrashPurge(ctx context.Context, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := s.PurgeTrash(ctx, retention)
+		if err == nil && n > 0 {
+			slog.InfoContext(ctx, "papelera purgada", slog.Int64("posts", n), slog.String("retention", retention.String()))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
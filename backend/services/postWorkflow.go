@@ -0,0 +1,215 @@
+// services/postWorkflow.go
+//
+// Paquete services: flujo editorial de posts (draft → in_review → approved → published
+// → archived).
+//
+// Convenciones:
+//   - Las transiciones permitidas están en allowedTransitions; cualquier otra responde
+//     ErrConflict (el estado actual no admite el cambio pedido).
+//   - Una transición es una actualización normal (If-Match, versión, updatedAt y
+//     revisión) que además queda registrada como PostTransition.
+//   - Published se deriva del estado: sólo "published" publica. Por compatibilidad, un
+//     PUT/PATCH que cambia published también mueve el estado (true → "published",
+//     false → "draft") y el scheduler publica o archiva; ambos cambios pasan por
+//     allowedTransitions igual que POST /transitions (ErrConflict si no se permiten) y
+//     se registran. Crear un post no es una transición (ver CreatePost).
+//   - Registrar la transición nunca hace fallar el cambio de estado: los errores se
+//     registran en el log, igual que las revisiones.
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"blog-api/models"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// allowedTransitions define, por estado de origen, los estados de destino válidos.
+var allowedTransitions = map[string][]string{
+	models.StatusDraft:     {models.StatusInReview},
+	models.StatusInReview:  {models.StatusDraft, models.StatusApproved},
+	models.StatusApproved:  {models.StatusDraft, models.StatusInReview, models.StatusPublished},
+	models.StatusPublished: {models.StatusDraft, models.StatusArchived},
+	models.StatusArchived:  {models.StatusDraft, models.StatusPublished},
+}
+
+var (
+	// errUnknownStatus es la causa de un estado que no pertenece al flujo.
+	errUnknownStatus = errors.New("unknown status")
+	// errIllegalTransition es la causa de un cambio de estado no permitido.
+	errIllegalTransition = errors.New("transition not allowed")
+)
+
+// ValidStatus indica si status es uno de los estados del flujo editorial.
+func ValidStatus(status string) bool {
+	_, ok := allowedTransitions[status]
+	return ok
+}
+
+// statusFromPublished es el estado equivalente al flag published (compatibilidad).
+func statusFromPublished(published bool) string {
+	if published {
+		return models.StatusPublished
+	}
+	return models.StatusDraft
+}
+
+// postStatus retorna el estado de post; los anteriores al flujo lo derivan de Published.
+func postStatus(post models.Post) string {
+	if post.Status == "" {
+		return statusFromPublished(post.Published)
+	}
+	return post.Status
+}
+
+// canPublish indica si un post en status puede pasar a "published" (lo que necesita
+// un publishAt pendiente para que el scheduler lo aplique).
+func canPublish(status string) bool {
+	return containsString(allowedTransitions[status], models.StatusPublished)
+}
+
+// checkTransition retorna ErrConflict si from no puede recorrer path en orden; los
+// pasos que no cambian el estado se ignoran.
+func checkTransition(from string, path ...string) error {
+	for _, to := range path {
+		if to == from {
+			continue
+		}
+		if !containsString(allowedTransitions[from], to) {
+			return Wrap(errIllegalTransition, ErrConflict, from+" -> "+to)
+		}
+		from = to
+	}
+	return nil
+}
+
+// nextStatus decide y valida el estado que save persiste: el recorrido de la
+// programación vencida (schedule, ver applyDueSchedule), el explícito de p
+// (transición) o el que corresponde al cambio de published.
+//
+// Errores:
+//   - ErrConflict: el cambio no está en allowedTransitions.
+func nextStatus(current, p models.Post, schedule []string) (string, error) {
+	from := postStatus(current)
+	path := schedule
+	switch {
+	case len(path) > 0:
+	case p.Status != "" && p.Status != from:
+		path = []string{p.Status}
+	case p.Published != current.Published:
+		path = []string{statusFromPublished(p.Published)}
+	default:
+		return from, nil
+	}
+	if err := checkTransition(from, path...); err != nil {
+		return "", err
+	}
+	return path[len(path)-1], nil
+}
+
+// TransitionPost cambia el estado editorial de un Post.
+//
+// Reglas:
+//   - to debe ser alcanzable desde el estado actual (ver allowedTransitions).
+//   - Pasar a "published" publica el post (PublishedAt=now, como un PUT con
+//     published=true) y cancela un publishAt pendiente, igual que pasar a un estado
+//     desde el que no se puede publicar (draft, in_review); salir de "published" lo
+//     despublica y cancela un unpublishAt pendiente.
+//   - Guarda la revisión del estado anterior y registra la transición con editor y reason.
+//
+// Parámetros:
+//   - ctx: contexto de cancelación/timeout.
+//   - idHex: ObjectID en hex del post.
+//   - to: estado de destino.
+//   - reason: motivo del cambio (opcional).
+//   - editor: quién hace el cambio.
+//   - ifVersions: versiones aceptadas (If-Match); nil = sin precondición.
+//
+// Retornos:
+//   - Post actualizado (estado After).
+//   - error con sentinelas: ErrInvalidID, ErrInvalidInput (estado desconocido),
+//     ErrNotFound, ErrConflict (transición no permitida o versión no aceptada), ErrDB.
+func (s *PostService) TransitionPost(ctx context.Context, idHex, to, reason, editor string, ifVersions []int64) (_ models.Post, err error) {
+	ctx, span := startSpan(ctx, "PostService.TransitionPost",
+		attribute.String("post.id", idHex), attribute.String("post.status", to))
+	defer endSpan(span, &err)
+
+	if !ValidStatus(to) {
+		return models.Post{}, Wrap(errUnknownStatus, ErrInvalidInput, "to")
+	}
+	current, err := s.getVisible(ctx, idHex)
+	if err != nil {
+		return models.Post{}, err
+	}
+	from := postStatus(current)
+	if !containsString(allowedTransitions[from], to) {
+		return models.Post{}, Wrap(errIllegalTransition, ErrConflict, from+" -> "+to)
+	}
+
+	p := models.Post{
+		Title:       current.Title,
+		Slug:        current.Slug,
+		Author:      current.Author,
+		AuthorID:    current.AuthorID,
+		Content:     current.Content,
+		Tags:        current.Tags,
+		Status:      to,
+		Published:   to == models.StatusPublished,
+		PublishAt:   current.PublishAt,
+		UnpublishAt: current.UnpublishAt,
+	}
+	if p.Published || !canPublish(to) {
+		p.PublishAt = nil
+	}
+	if from == models.StatusPublished {
+		p.UnpublishAt = nil
+	}
+	return s.save(ctx, current, p, editor, reason, ifVersions)
+}
+
+// ListTransitions retorna los cambios de estado de un Post, del más nuevo al más viejo.
+//
+// Retornos:
+//   - transiciones (vacío si el post nunca cambió de estado).
+//   - error con sentinelas: ErrInvalidID, ErrNotFound (post inexistente o en la
+//     papelera), ErrDB.
+func (s *PostService) ListTransitions(ctx context.Context, idHex string) (_ []models.PostTransition, err error) {
+	ctx, span := startSpan(ctx, "PostService.ListTransitions", attribute.String("post.id", idHex))
+	defer endSpan(span, &err)
+
+	post, err := s.getVisible(ctx, idHex)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.transitions.List(ctx, post.ID)
+	if err != nil {
+		return nil, logDBError(ctx, "list transitions", err)
+	}
+	return items, nil
+}
+
+// recordTransition registra el paso de current a updated si cambió el estado.
+// Se llama después de persistir la actualización.
+func (s *PostService) recordTransition(ctx context.Context, current, updated models.Post, editor, reason string) {
+	from, to := postStatus(current), postStatus(updated)
+	if from == to {
+		return
+	}
+	t := models.PostTransition{
+		PostID:    updated.ID,
+		From:      from,
+		To:        to,
+		Version:   updated.Version,
+		Reason:    reason,
+		CreatedAt: time.Now().UTC(),
+		CreatedBy: editor,
+	}
+	if err := s.transitions.Create(ctx, t); err != nil {
+		slog.WarnContext(ctx, "no se pudo registrar la transición",
+			slog.String("postId", updated.ID.Hex()), slog.String("from", from), slog.String("to", to),
+			slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"blog-api/models"
+)
+
+// approve lleva el post id de draft a approved por el flujo editorial.
+func (tp *testPosts) approve(t *testing.T, id string) {
+	t.Helper()
+	for _, to := range []string{models.StatusInReview, models.StatusApproved} {
+		if _, err := tp.svc.TransitionPost(context.Background(), id, to, "", "ana", nil); err != nil {
+			t.Fatalf("transition to %s: %v", to, err)
+		}
+	}
+}
+
+func TestPublishingDraftByUpdateIsIllegalTransition(t *testing.T) {
+	tp := newTestPosts(t)
+	id := tp.create(t, "Primer post", false)
+	published := true
+
+	_, err := tp.svc.PatchPostByID(context.Background(), id, PostPatch{Published: &published}, "ana", []int64{1})
+	if !errors.Is(err, ErrConflict) || errors.Is(err, ErrVersionMismatch) {
+		t.Fatalf("draft con published=true: %v, want ErrConflict", err)
+	}
+	_, err = tp.svc.UpdatePostByID(context.Background(), id, models.Post{
+		Title: "Primer post", Content: "contenido", AuthorID: tp.author.ID, Published: true,
+	}, "ana", nil)
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("PUT draft con published=true: %v, want ErrConflict", err)
+	}
+	got, err := tp.svc.GetPostByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Status != models.StatusDraft || got.Published || got.Version != 1 {
+		t.Fatalf("post tras el rechazo: status %q published %v version %d", got.Status, got.Published, got.Version)
+	}
+}
+
+func TestPublishingApprovedByUpdateRecordsTransition(t *testing.T) {
+	tp := newTestPosts(t)
+	id := tp.create(t, "Primer post", false)
+	tp.approve(t, id)
+	published := true
+
+	got, err := tp.svc.PatchPostByID(context.Background(), id, PostPatch{Published: &published}, "ana", nil)
+	if err != nil {
+		t.Fatalf("approved con published=true: %v", err)
+	}
+	if got.Status != models.StatusPublished || got.PublishedAt == nil {
+		t.Fatalf("publicado: status %q publishedAt %v", got.Status, got.PublishedAt)
+	}
+	items, err := tp.svc.ListTransitions(context.Background(), id)
+	if err != nil {
+		t.Fatalf("transitions: %v", err)
+	}
+	if len(items) != 3 || items[0].From != models.StatusApproved || items[0].To != models.StatusPublished || items[0].CreatedBy != "ana" {
+		t.Fatalf("transiciones: %+v", items)
+	}
+}
+
+func TestScheduleRequiresPublishableStatus(t *testing.T) {
+	tp := newTestPosts(t)
+	id := tp.create(t, "Primer post", false)
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+	at := &future
+
+	if _, err := tp.svc.PatchPostByID(context.Background(), id, PostPatch{PublishAt: &at}, "ana", nil); !errors.Is(err, ErrConflict) {
+		t.Fatalf("draft con publishAt futuro: %v, want ErrConflict", err)
+	}
+	at = &past
+	if _, err := tp.svc.PatchPostByID(context.Background(), id, PostPatch{PublishAt: &at}, "ana", nil); !errors.Is(err, ErrConflict) {
+		t.Fatalf("draft con publishAt vencido: %v, want ErrConflict", err)
+	}
+
+	tp.approve(t, id)
+	at = &future
+	got, err := tp.svc.PatchPostByID(context.Background(), id, PostPatch{PublishAt: &at}, "ana", nil)
+	if err != nil {
+		t.Fatalf("approved con publishAt: %v", err)
+	}
+	if got.Status != models.StatusApproved || got.PublishAt == nil {
+		t.Fatalf("programado: status %q publishAt %v", got.Status, got.PublishAt)
+	}
+
+	got, err = tp.svc.TransitionPost(context.Background(), id, models.StatusDraft, "", "ana", nil)
+	if err != nil {
+		t.Fatalf("transition to draft: %v", err)
+	}
+	if got.PublishAt != nil {
+		t.Fatalf("volver a draft no canceló publishAt: %v", got.PublishAt)
+	}
+}
+
+func TestCreateScheduledPostIsApproved(t *testing.T) {
+	tp := newTestPosts(t)
+	future := time.Now().Add(time.Hour)
+	oid, err := tp.svc.CreatePost(context.Background(), models.Post{
+		Title: "Programado", Content: "contenido", AuthorID: tp.author.ID, PublishAt: &future,
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	got, err := tp.svc.GetPostByID(context.Background(), oid.Hex())
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Status != models.StatusApproved || got.Published {
+		t.Fatalf("creado con publishAt: status %q published %v", got.Status, got.Published)
+	}
+}
+
+func TestApplySchedulesValidatesTransitions(t *testing.T) {
+	tp := newTestPosts(t)
+	ctx := context.Background()
+	past := time.Now().Add(-time.Minute).UTC()
+
+	// Un borrador con publishAt vencido (programado antes de validar el flujo).
+	draftID, err := tp.repo.Create(ctx, models.Post{
+		Title: "Borrador programado", Slug: "borrador-programado", Author: tp.author.Name, AuthorID: tp.author.ID,
+		Content: "contenido", Status: models.StatusDraft, PublishAt: &past, CreatedAt: past, Version: 1,
+	})
+	if err != nil {
+		t.Fatalf("create draft: %v", err)
+	}
+	id := tp.create(t, "Aprobado", false)
+	tp.approve(t, id)
+	// Programado a futuro y luego vencido: se fuerza el vencimiento en el repositorio.
+	current, err := tp.svc.GetPostByID(ctx, id)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	current.PublishAt = &past
+	if _, err := tp.repo.Update(ctx, current.ID, current, current.Version); err != nil {
+		t.Fatalf("schedule approved: %v", err)
+	}
+
+	res, err := tp.svc.ApplySchedules(ctx)
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if res.Published != 1 || res.Canceled != 1 || res.Skipped != 0 {
+		t.Fatalf("resultado: %+v", res)
+	}
+
+	draft, err := tp.svc.GetPostByID(ctx, draftID.Hex())
+	if err != nil {
+		t.Fatalf("get draft: %v", err)
+	}
+	if draft.Status != models.StatusDraft || draft.Published || draft.PublishAt != nil {
+		t.Fatalf("borrador: status %q published %v publishAt %v", draft.Status, draft.Published, draft.PublishAt)
+	}
+	items, err := tp.svc.ListTransitions(ctx, draftID.Hex())
+	if err != nil {
+		t.Fatalf("transitions: %v", err)
+	}
+	if len(items) != 0 {
+		t.Fatalf("transiciones del borrador: %+v", items)
+	}
+
+	approved, err := tp.svc.GetPostByID(ctx, id)
+	if err != nil {
+		t.Fatalf("get approved: %v", err)
+	}
+	if approved.Status != models.StatusPublished || !approved.Published {
+		t.Fatalf("aprobado: status %q published %v", approved.Status, approved.Published)
+	}
+}
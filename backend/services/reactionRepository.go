@@ -0,0 +1,47 @@
+// services/reactionRepository.go
+//
+// Paquete services: contrato de persistencia de reacciones a posts.
+//
+// Convenciones:
+//   - Igual que CommentRepository: ReactionService depende de la interfaz y las reglas
+//     (tipos permitidos, visibilidad del post) viven en el servicio.
+//   - Add y Remove son idempotentes por (post, tipo, cliente): el contador sólo se
+//     incrementa (o decrementa) cuando la marca del cliente se crea (o se borra), con
+//     una operación atómica ($inc en Mongo, UPDATE count = count ± 1 en SQLite). Si
+//     Add o Remove fallan, reintentarlos deja marca y contador consistentes.
+package services
+
+import (
+	"context"
+	"time"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReactionRepository define las operaciones de almacenamiento de reacciones.
+//
+// Implementaciones:
+//   - MongoReactionRepository: colecciones "reactions" y "reaction_counts" en MongoDB.
+//   - MemoryReactionRepository: mapas en memoria.
+//   - SQLiteReactionRepository: tablas reactions y reaction_counts.
+//
+// Errores esperados:
+//   - ErrDB ante fallas del almacenamiento.
+type ReactionRepository interface {
+	// Add registra que clientID reaccionó con kind a postID y suma 1 al contador; si la
+	// reacción ya existía no cambia nada y retorna false.
+	Add(ctx context.Context, postID primitive.ObjectID, kind, clientID string, at time.Time) (bool, error)
+	// Remove borra la reacción kind de clientID en postID y resta 1 al contador; si no
+	// existía no cambia nada y retorna false.
+	Remove(ctx context.Context, postID primitive.ObjectID, kind, clientID string) (bool, error)
+	// Counts retorna las reacciones por tipo de cada post de postIDs (los posts sin
+	// reacciones no aparecen en el mapa).
+	Counts(ctx context.Context, postIDs []primitive.ObjectID) (map[primitive.ObjectID]map[string]int64, error)
+	// Top retorna los contadores con más reacciones de kind (de Total si kind es ""),
+	// de mayor a menor y luego por id de post, saltando skip y hasta limit; los posts
+	// sin reacciones de kind no aparecen.
+	Top(ctx context.Context, kind string, skip, limit int) ([]models.ReactionCount, error)
+	// DeleteByPosts borra las reacciones y los contadores de los posts indicados.
+	DeleteByPosts(ctx context.Context, postIDs []primitive.ObjectID) (int64, error)
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestReactionRepository(t *testing.T) {
+	forEachStore(t, func(t *testing.T, f *repoFixture) {
+		ctx := context.Background()
+		a := f.create(t, f.post("Primer post", "primer-post", "uno", true))
+		b := f.create(t, f.post("Segundo post", "segundo-post", "dos", true))
+
+		add := func(post primitive.ObjectID, kind, client string, want bool) {
+			t.Helper()
+			changed, err := f.store.Reactions.Add(ctx, post, kind, client, f.now)
+			if err != nil || changed != want {
+				t.Fatalf("add %s %s: changed %v, want %v: %v", kind, client, changed, want, err)
+			}
+		}
+		add(a, "like", "c1", true)
+		add(a, "like", "c1", false)
+		add(a, "like", "c2", true)
+		add(a, "love", "c1", true)
+		add(b, "like", "c1", true)
+
+		counts, err := f.store.Reactions.Counts(ctx, []primitive.ObjectID{a, b, primitive.NewObjectID()})
+		if err != nil || counts[a]["like"] != 2 || counts[a]["love"] != 1 || counts[b]["like"] != 1 || len(counts) != 2 {
+			t.Fatalf("counts: %v %v", counts, err)
+		}
+
+		top, err := f.store.Reactions.Top(ctx, "", 0, 10)
+		if err != nil || len(top) != 2 || top[0].PostID != a || top[0].Total != 3 {
+			t.Fatalf("top total: %+v %v", top, err)
+		}
+		top, err = f.store.Reactions.Top(ctx, "love", 0, 10)
+		if err != nil || len(top) != 1 || top[0].PostID != a {
+			t.Fatalf("top love: %+v %v", top, err)
+		}
+
+		for _, c := range []struct {
+			client string
+			want   bool
+		}{{"c1", true}, {"c1", false}, {"c9", false}} {
+			changed, err := f.store.Reactions.Remove(ctx, a, "like", c.client)
+			if err != nil || changed != c.want {
+				t.Fatalf("remove %s: changed %v, want %v: %v", c.client, changed, c.want, err)
+			}
+		}
+		if counts, _ := f.store.Reactions.Counts(ctx, []primitive.ObjectID{a}); counts[a]["like"] != 1 {
+			t.Fatalf("counts tras remove: %v", counts)
+		}
+
+		if _, err := f.store.Reactions.DeleteByPosts(ctx, []primitive.ObjectID{a}); err != nil {
+			t.Fatalf("delete by posts: %v", err)
+		}
+		if counts, _ := f.store.Reactions.Counts(ctx, []primitive.ObjectID{a, b}); len(counts) != 1 {
+			t.Fatalf("counts tras delete: %v", counts)
+		}
+		add(a, "like", "c2", true)
+	})
+}
+
+func TestReactionRepositoryConcurrentAdd(t *testing.T) {
+	forEachStore(t, func(t *testing.T, f *repoFixture) {
+		testConcurrentReactions(t, f.store.Reactions, f.create(t, f.post("Primer post", "primer-post", "uno", true)))
+	})
+}
+
+// testConcurrentReactions lanza varios Add y luego varios Remove simultáneos de la misma
+// reacción: sólo uno de cada grupo reporta el cambio y el contador termina en 1 y en 0.
+func testConcurrentReactions(t *testing.T, r ReactionRepository, postID primitive.ObjectID) {
+	t.Helper()
+	ctx := context.Background()
+	const writers = 8
+
+	run := func(op func() (bool, error)) int {
+		t.Helper()
+		var wg sync.WaitGroup
+		changed := make(chan bool, writers)
+		errs := make(chan error, writers)
+		for i := 0; i < writers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ok, err := op()
+				if err != nil {
+					errs <- err
+				}
+				changed <- ok
+			}()
+		}
+		wg.Wait()
+		close(changed)
+		close(errs)
+		for err := range errs {
+			t.Fatalf("op concurrente: %v", err)
+		}
+		n := 0
+		for ok := range changed {
+			if ok {
+				n++
+			}
+		}
+		return n
+	}
+	count := func() int64 {
+		t.Helper()
+		counts, err := r.Counts(ctx, []primitive.ObjectID{postID})
+		if err != nil {
+			t.Fatalf("counts: %v", err)
+		}
+		return counts[postID]["like"]
+	}
+
+	if n := run(func() (bool, error) { return r.Add(ctx, postID, "like", "c1", time.Now().UTC()) }); n != 1 {
+		t.Fatalf("add concurrente: %d reportan el cambio, want 1", n)
+	}
+	if n := count(); n != 1 {
+		t.Fatalf("count tras add concurrente: %d, want 1", n)
+	}
+	if n := run(func() (bool, error) { return r.Remove(ctx, postID, "like", "c1") }); n != 1 {
+		t.Fatalf("remove concurrente: %d reportan el cambio, want 1", n)
+	}
+	if n := count(); n != 0 {
+		t.Fatalf("count tras remove concurrente: %d, want 0", n)
+	}
+}
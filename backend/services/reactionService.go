@@ -0,0 +1,232 @@
+// services/reactionService.go
+//
+// Paquete services: lógica de negocio para reacciones de lectores a posts.
+//
+// Convenciones:
+//   - Mismas reglas que CommentService: repositorios por constructor, errores envueltos
+//     con sentinelas, ErrDB registrados con logDBError y un span por método.
+//   - Sólo se aceptan los tipos configurados (reactions.kinds) y sólo se reacciona a
+//     posts publicados; quitar una reacción se permite aunque el post ya no lo esté.
+//   - Reaccionar y quitar son idempotentes por cliente: repetirlos no cambia los
+//     contadores (Changed=false).
+//   - Un post en la papelera oculta sus reacciones (404) y las recupera al restaurarlo;
+//     al purgarlo se borran (ver PostService.deleteHistory).
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Límites del ranking de reacciones.
+const (
+	defaultReactionRankLimit = 10
+	maxReactionRankLimit     = 50
+)
+
+var (
+	// errReactionsClosed es la causa al reaccionar a un post que no está publicado.
+	errReactionsClosed = errors.New("post is not published")
+	// errUnknownReaction es la causa de un tipo de reacción no configurado.
+	errUnknownReaction = errors.New("unknown reaction kind")
+)
+
+// ReactionService implementa los casos de uso de reacciones sobre un
+// ReactionRepository; valida los posts a través de un PostRepository.
+type ReactionService struct {
+	reactions ReactionRepository
+	posts     PostRepository
+	kinds     []string
+}
+
+// NewReactionService crea el servicio de reacciones usando reactions como
+// almacenamiento, posts para validar el post y kinds como tipos permitidos (en el orden
+// en que se muestran).
+func NewReactionService(reactions ReactionRepository, posts PostRepository, kinds []string) *ReactionService {
+	return &ReactionService{reactions: reactions, posts: posts, kinds: kinds}
+}
+
+// ReactionResult es el estado de una reacción después de POST o DELETE.
+//
+// Campos:
+//   - PostID / Kind: reacción afectada.
+//   - Reacted: si el cliente tiene ahora esa reacción.
+//   - Changed: si el request cambió algo (false al repetirlo).
+//   - Reactions: reacciones del post por tipo configurado, ya actualizadas.
+type ReactionResult struct {
+	PostID    primitive.ObjectID `json:"postId"`
+	Kind      string             `json:"kind"`
+	Reacted   bool               `json:"reacted"`
+	Changed   bool               `json:"changed"`
+	Reactions map[string]int64   `json:"reactions"`
+}
+
+// ReactionRank es una posición del ranking de reacciones.
+//
+// Campos:
+//   - PostID / Title / Slug: post publicado.
+//   - Count: reacciones del tipo pedido (o de todos los tipos).
+//   - Reactions: reacciones del post por tipo configurado.
+type ReactionRank struct {
+	PostID    primitive.ObjectID `json:"postId"`
+	Title     string             `json:"title"`
+	Slug      string             `json:"slug,omitempty"`
+	Count     int64              `json:"count"`
+	Reactions map[string]int64   `json:"reactions"`
+}
+
+// React registra la reacción kind del cliente clientID al post postIDHex.
+//
+// Errores:
+//   - ErrInvalidID: id de post inválido.
+//   - ErrInvalidInput: kind no configurado.
+//   - ErrNotFound: el post no existe o está en la papelera.
+//   - ErrConflict: el post no está publicado.
+//   - ErrDB: error del driver.
+func (s *ReactionService) React(ctx context.Context, postIDHex, kind, clientID string) (_ ReactionResult, err error) {
+	ctx, span := startSpan(ctx, "ReactionService.React",
+		attribute.String("post.id", postIDHex), attribute.String("reaction.kind", kind))
+	defer endSpan(span, &err)
+
+	post, err := s.reactablePost(ctx, postIDHex, kind)
+	if err != nil {
+		return ReactionResult{}, err
+	}
+	if postStatus(post) != models.StatusPublished {
+		return ReactionResult{}, Wrap(errReactionsClosed, ErrConflict, "react")
+	}
+	changed, err := s.reactions.Add(ctx, post.ID, kind, clientID, time.Now().UTC())
+	if err != nil {
+		return ReactionResult{}, logDBError(ctx, "add reaction", err)
+	}
+	span.SetAttributes(attribute.Bool("reaction.changed", changed))
+	return s.result(ctx, post.ID, kind, true, changed)
+}
+
+// Unreact quita la reacción kind del cliente clientID al post postIDHex; si no existía
+// no es error.
+//
+// Errores:
+//   - ErrInvalidID: id de post inválido.
+//   - ErrInvalidInput: kind no configurado.
+//   - ErrNotFound: el post no existe o está en la papelera.
+//   - ErrDB: error del driver.
+func (s *ReactionService) Unreact(ctx context.Context, postIDHex, kind, clientID string) (_ ReactionResult, err error) {
+	ctx, span := startSpan(ctx, "ReactionService.Unreact",
+		attribute.String("post.id", postIDHex), attribute.String("reaction.kind", kind))
+	defer endSpan(span, &err)
+
+	post, err := s.reactablePost(ctx, postIDHex, kind)
+	if err != nil {
+		return ReactionResult{}, err
+	}
+	changed, err := s.reactions.Remove(ctx, post.ID, kind, clientID)
+	if err != nil {
+		return ReactionResult{}, logDBError(ctx, "remove reaction", err)
+	}
+	span.SetAttributes(attribute.Bool("reaction.changed", changed))
+	return s.result(ctx, post.ID, kind, false, changed)
+}
+
+// Leaderboard retorna los posts publicados con más reacciones de kind (de todos los
+// tipos si kind es ""), de mayor a menor.
+//
+// Parámetros:
+//   - limit: posiciones (si <=0 se usa 10; si >50 se trunca a 50).
+//
+// Notas:
+//   - Los contadores se recorren en tandas de limit y se descartan los posts que no
+//     están publicados o están en la papelera, hasta completar limit.
+//
+// Errores:
+//   - ErrInvalidInput: kind no configurado.
+//   - ErrDB: error del driver.
+func (s *ReactionService) Leaderboard(ctx context.Context, kind string, limit int) (_ []ReactionRank, err error) {
+	ctx, span := startSpan(ctx, "ReactionService.Leaderboard", attribute.String("reaction.kind", kind))
+	defer endSpan(span, &err)
+
+	if kind != "" && !containsString(s.kinds, kind) {
+		return nil, Wrap(errUnknownReaction, ErrInvalidInput, "kind")
+	}
+	if limit <= 0 {
+		limit = defaultReactionRankLimit
+	}
+	if limit > maxReactionRankLimit {
+		limit = maxReactionRankLimit
+	}
+
+	out := []ReactionRank{}
+	for skip := 0; len(out) < limit; skip += limit {
+		batch, err := s.reactions.Top(ctx, kind, skip, limit)
+		if err != nil {
+			return nil, logDBError(ctx, "rank reactions", err)
+		}
+		for _, rc := range batch {
+			post, err := s.posts.Get(ctx, rc.PostID)
+			if errors.Is(err, ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, logDBError(ctx, "get post", err)
+			}
+			if post.DeletedAt != nil || postStatus(post) != models.StatusPublished {
+				continue
+			}
+			out = append(out, ReactionRank{
+				PostID:    post.ID,
+				Title:     post.Title,
+				Slug:      post.Slug,
+				Count:     reactionScore(rc, kind),
+				Reactions: reactionsByKind(s.kinds, rc.Counts),
+			})
+			if len(out) == limit {
+				break
+			}
+		}
+		if len(batch) < limit {
+			break
+		}
+	}
+	span.SetAttributes(attribute.Int("reaction.ranked", len(out)))
+	return out, nil
+}
+
+// reactablePost valida kind y recupera el post idHex; los de la papelera cuentan como
+// inexistentes.
+func (s *ReactionService) reactablePost(ctx context.Context, idHex, kind string) (models.Post, error) {
+	oid, err := primitive.ObjectIDFromHex(idHex)
+	if err != nil {
+		return models.Post{}, Wrap(err, ErrInvalidID, "parse objectid")
+	}
+	if !containsString(s.kinds, kind) {
+		return models.Post{}, Wrap(errUnknownReaction, ErrInvalidInput, "kind")
+	}
+	post, err := s.posts.Get(ctx, oid)
+	if err != nil {
+		return models.Post{}, logDBError(ctx, "get post", err)
+	}
+	if post.DeletedAt != nil {
+		return models.Post{}, Wrap(errPostDeleted, ErrNotFound, "post not found")
+	}
+	return post, nil
+}
+
+// result arma el ReactionResult de postID con sus contadores actuales.
+func (s *ReactionService) result(ctx context.Context, postID primitive.ObjectID, kind string, reacted, changed bool) (ReactionResult, error) {
+	counts, err := s.reactions.Counts(ctx, []primitive.ObjectID{postID})
+	if err != nil {
+		return ReactionResult{}, logDBError(ctx, "count reactions", err)
+	}
+	return ReactionResult{
+		PostID:    postID,
+		Kind:      kind,
+		Reacted:   reacted,
+		Changed:   changed,
+		Reactions: reactionsByKind(s.kinds, counts[postID]),
+	}, nil
+}
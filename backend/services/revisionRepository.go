@@ -0,0 +1,42 @@
+// services/revisionRepository.go
+//
+// Paquete services: contrato de persistencia del historial de revisiones de posts.
+//
+// Convenciones:
+//   - Igual que PostRepository: PostService depende de la interfaz, las implementaciones
+//     envuelven sus errores con sentinelas y las reglas (qué guardar, retención) viven
+//     en PostService.
+//   - (postId, revision) es único; guardar dos veces la misma revisión no es un error.
+package services
+
+import (
+	"context"
+	"time"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RevisionRepository define las operaciones de almacenamiento de revisiones.
+//
+// Implementaciones:
+//   - MongoRevisionRepository: colección "post_revisions" en MongoDB.
+//   - MemoryRevisionRepository: mapa en memoria.
+//   - SQLiteRevisionRepository: tabla post_revisions.
+//
+// Errores esperados:
+//   - ErrNotFound si la revisión no existe (Get).
+//   - ErrDB ante fallas del almacenamiento.
+type RevisionRepository interface {
+	// Create guarda rev; si ya existe (mismo postId y revision) no hace nada.
+	Create(ctx context.Context, rev models.PostRevision) error
+	// List retorna las revisiones de postID, de la más nueva a la más vieja.
+	List(ctx context.Context, postID primitive.ObjectID) ([]models.PostRevision, error)
+	// Get recupera una revisión de postID por número.
+	Get(ctx context.Context, postID primitive.ObjectID, revision int64) (models.PostRevision, error)
+	// Prune conserva las keep revisiones más nuevas de postID (keep <= 0 = todas) y borra
+	// las creadas antes de before (cero = sin límite de edad); retorna cuántas borró.
+	Prune(ctx context.Context, postID primitive.ObjectID, keep int, before time.Time) (int64, error)
+	// DeleteByPosts borra el historial completo de los posts indicados.
+	DeleteByPosts(ctx context.Context, postIDs []primitive.ObjectID) (int64, error)
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRevisionRepository(t *testing.T) {
+	forEachStore(t, func(t *testing.T, f *repoFixture) {
+		ctx := context.Background()
+		postID := f.create(t, f.post("Primer post", "primer-post", "uno", false))
+		for rev := int64(1); rev <= 4; rev++ {
+			r := models.PostRevision{
+				PostID: postID, Revision: rev, Title: "Primer post", Author: f.author.Name, AuthorID: f.author.ID,
+				Content: "versión", Tags: []string{"go"}, CreatedAt: f.now.Add(time.Duration(rev) * time.Hour), CreatedBy: "ana",
+			}
+			if err := f.store.Revisions.Create(ctx, r); err != nil {
+				t.Fatalf("create %d: %v", rev, err)
+			}
+		}
+		// Repetir una revisión no la duplica.
+		if err := f.store.Revisions.Create(ctx, models.PostRevision{PostID: postID, Revision: 4, CreatedAt: f.now}); err != nil {
+			t.Fatalf("create repetida: %v", err)
+		}
+
+		items, err := f.store.Revisions.List(ctx, postID)
+		if err != nil || len(items) != 4 || items[0].Revision != 4 || items[3].Revision != 1 {
+			t.Fatalf("list: %d revisiones: %v", len(items), err)
+		}
+		got, err := f.store.Revisions.Get(ctx, postID, 2)
+		if err != nil || got.Content != "versión" || len(got.Tags) != 1 || got.CreatedBy != "ana" {
+			t.Fatalf("get: %+v %v", got, err)
+		}
+		if _, err := f.store.Revisions.Get(ctx, postID, 9); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("get inexistente: %v, want ErrNotFound", err)
+		}
+
+		n, err := f.store.Revisions.Prune(ctx, postID, 3, time.Time{})
+		if err != nil || n != 1 {
+			t.Fatalf("prune por cantidad: %d %v", n, err)
+		}
+		n, err = f.store.Revisions.Prune(ctx, postID, 0, f.now.Add(3*time.Hour))
+		if err != nil || n != 1 {
+			t.Fatalf("prune por edad: %d %v", n, err)
+		}
+		if items, _ := f.store.Revisions.List(ctx, postID); len(items) != 2 || items[1].Revision != 3 {
+			t.Fatalf("tras prune: %+v", items)
+		}
+
+		n, err = f.store.Revisions.DeleteByPosts(ctx, []primitive.ObjectID{postID})
+		if err != nil || n != 2 {
+			t.Fatalf("delete by posts: %d %v", n, err)
+		}
+	})
+}
@@ -0,0 +1,147 @@
+// services/slug.go
+//
+// Paquete services: generación y validación de slugs de posts.
+//
+// Convenciones:
+//   - Un slug es ASCII en minúsculas: palabras [a-z0-9] separadas por un guion
+//     ("introduccion-a-mongodb-con-go").
+//   - Los acentos se transliteran (NFD + remoción de marcas: "ó" → "o", "ñ" → "n");
+//     unas pocas letras sin descomposición tienen su equivalente en slugLetters.
+//   - Los slugs son únicos entre posts, incluidos los de la papelera y los anteriores
+//     (historial de redirección); ante una colisión se agrega "-2", "-3", etc.
+package services
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"blog-api/models"
+	"golang.org/x/text/unicode/norm"
+)
+
+// maxSlugLen es el largo máximo de un slug (generado o enviado por el cliente).
+const maxSlugLen = 100
+
+// maxSlugAttempts limita los sufijos probados al generar un slug único.
+const maxSlugAttempts = 50
+
+// fallbackSlug se usa cuando el título no tiene letras ni dígitos transliterables.
+const fallbackSlug = "post"
+
+// slugPattern valida un slug enviado por el cliente.
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+var (
+	// errSlugTaken es la causa cuando el slug pertenece a otro post.
+	errSlugTaken = errors.New("slug already in use")
+	// errSlugFormat es la causa de un slug con formato inválido.
+	errSlugFormat = errors.New("slug must be lowercase words [a-z0-9] separated by single hyphens")
+)
+
+// slugLetters translitera letras latinas que NFD no descompone.
+var slugLetters = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'đ': "d", 'ð': "d", 'ł': "l", 'þ': "th", 'ı': "i",
+}
+
+// slugify genera un slug a partir de title.
+func slugify(title string) string {
+	return truncateSlug(slugWords(title), maxSlugLen)
+}
+
+// slugWords translitera s a palabras [a-z0-9] separadas por guiones, sin largo máximo
+// ni fallback ("" si s no tiene letras ni dígitos transliterables).
+func slugWords(s string) string {
+	var sb strings.Builder
+	pendingDash := false
+	for _, r := range norm.NFD.String(strings.ToLower(s)) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		out, ok := slugLetters[r]
+		if !ok && (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			pendingDash = sb.Len() > 0
+			continue
+		}
+		if !ok {
+			out = string(r)
+		}
+		if pendingDash {
+			sb.WriteByte('-')
+			pendingDash = false
+		}
+		sb.WriteString(out)
+	}
+	return sb.String()
+}
+
+// truncateSlug recorta s a max bytes sin dejar media palabra ni un guion final.
+func truncateSlug(s string, max int) string {
+	if s == "" {
+		return fallbackSlug
+	}
+	if len(s) <= max {
+		return s
+	}
+	s = s[:max]
+	if i := strings.LastIndexByte(s, '-'); i > 0 {
+		s = s[:i]
+	}
+	return strings.TrimSuffix(s, "-")
+}
+
+// slugCandidate retorna el intento n (1-based) para base: base, base-2, base-3...
+// Recorta base para que el sufijo no exceda maxSlugLen.
+func slugCandidate(base string, n int) string {
+	if n <= 1 {
+		return base
+	}
+	suffix := "-" + strconv.Itoa(n)
+	return truncateSlug(base, maxSlugLen-len(suffix)) + suffix
+}
+
+// backfillSlugs asigna un slug único a los posts de items que no tienen, en orden, y
+// retorna los índices modificados. taken debe contener los slugs vigentes y anteriores
+// de todos los posts; se actualiza con los asignados.
+func backfillSlugs(items []models.Post, taken map[string]bool) []int {
+	var changed []int
+	for i := range items {
+		if items[i].Slug != "" {
+			continue
+		}
+		base := slugify(items[i].Title)
+		for n := 1; ; n++ {
+			if candidate := slugCandidate(base, n); !taken[candidate] {
+				items[i].Slug = candidate
+				taken[candidate] = true
+				break
+			}
+		}
+		changed = append(changed, i)
+	}
+	return changed
+}
+
+// takenSlugs arma el conjunto de slugs vigentes y anteriores de items.
+func takenSlugs(items []models.Post) map[string]bool {
+	taken := make(map[string]bool, len(items))
+	for _, p := range items {
+		if p.Slug != "" {
+			taken[p.Slug] = true
+		}
+		for _, s := range p.OldSlugs {
+			taken[s] = true
+		}
+	}
+	return taken
+}
+
+// validateSlug verifica el formato de un slug enviado por el cliente.
+func validateSlug(slug string) error {
+	if len(slug) > maxSlugLen || !slugPattern.MatchString(slug) {
+		return Wrap(errSlugFormat, ErrInvalidInput, "slug")
+	}
+	return nil
+}
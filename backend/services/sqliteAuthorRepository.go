@@ -0,0 +1,209 @@
+// services/sqliteAuthorRepository.go
+//
+// Paquete services: implementación de AuthorRepository sobre SQLite.
+//
+// Convenciones:
+//   - Tabla authors con slug UNIQUE; posts.author_id la referencia (foreign_keys
+//     activo), así que un autor con posts no puede borrarse ni siquiera por fuera del
+//     servicio.
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// sqliteAuthorColumns es la proyección usada por scanSQLiteAuthor.
+const sqliteAuthorColumns = `id, name, slug, bio, avatar_url, created_at, updated_at`
+
+// SQLiteAuthorRepository persiste autores en la tabla authors.
+type SQLiteAuthorRepository struct {
+	db      *sql.DB
+	timeout time.Duration
+}
+
+// NewSQLiteAuthorRepository crea un repositorio sobre db (ver OpenSQLite).
+// timeout limita cada operación (<=0 usa defaultTimeout).
+func NewSQLiteAuthorRepository(db *sql.DB, timeout time.Duration) *SQLiteAuthorRepository {
+	return &SQLiteAuthorRepository{db: db, timeout: orDefaultTimeout(timeout)}
+}
+
+// Create inserta a; asigna un ObjectID nuevo si no trae uno.
+//
+// Errores:
+//   - ErrConflict: slug en uso.
+//   - ErrDB: error del driver.
+func (r *SQLiteAuthorRepository) Create(ctx context.Context, a models.Author) (primitive.ObjectID, error) {
+	if a.ID.IsZero() {
+		a.ID = primitive.NewObjectID()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	if err := insertSQLiteAuthor(ctx, r.db, a); err != nil {
+		return primitive.NilObjectID, err
+	}
+	return a.ID, nil
+}
+
+// Get recupera un autor por ObjectID.
+//
+// Errores:
+//   - ErrNotFound si no existe; ErrDB si falla el driver.
+func (r *SQLiteAuthorRepository) Get(ctx context.Context, id primitive.ObjectID) (models.Author, error) {
+	return r.getWhere(ctx, `id = ?`, id.Hex())
+}
+
+// GetBySlug recupera un autor por slug.
+//
+// Errores:
+//   - ErrNotFound si no existe; ErrDB si falla el driver.
+func (r *SQLiteAuthorRepository) GetBySlug(ctx context.Context, slug string) (models.Author, error) {
+	return r.getWhere(ctx, `slug = ?`, slug)
+}
+
+// getWhere lee el autor que cumple cond.
+func (r *SQLiteAuthorRepository) getWhere(ctx context.Context, cond string, arg any) (models.Author, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	a, err := scanSQLiteAuthor(r.db.QueryRowContext(ctx, `SELECT `+sqliteAuthorColumns+` FROM authors WHERE `+cond, arg))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Author{}, Wrap(err, ErrNotFound, "author not found")
+	}
+	return a, err
+}
+
+// List retorna todos los autores ordenados por nombre (a igual nombre, por id).
+//
+// Errores:
+//   - ErrDB: error del driver.
+func (r *SQLiteAuthorRepository) List(ctx context.Context) ([]models.Author, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `SELECT `+sqliteAuthorColumns+` FROM authors ORDER BY name, id`)
+	if err != nil {
+		return nil, Wrap(err, ErrDB, "find authors")
+	}
+	defer rows.Close()
+
+	out := []models.Author{}
+	for rows.Next() {
+		a, err := scanSQLiteAuthor(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, Wrap(err, ErrDB, "find authors")
+	}
+	return out, nil
+}
+
+// Update aplica los campos editables de a y retorna el autor actualizado.
+//
+// Errores:
+//   - ErrNotFound si no existe; ErrConflict si el slug está en uso; ErrDB si falla el driver.
+func (r *SQLiteAuthorRepository) Update(ctx context.Context, id primitive.ObjectID, a models.Author) (models.Author, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE authors SET name = ?, slug = ?, bio = ?, avatar_url = ?, updated_at = ? WHERE id = ?`,
+		a.Name, a.Slug, sqliteNullString(a.Bio), sqliteNullString(a.AvatarURL), sqliteTimePtr(a.UpdatedAt), id.Hex())
+	if err != nil {
+		if isSQLiteUniqueViolation(err) {
+			return models.Author{}, Wrap(err, ErrConflict, "update author")
+		}
+		return models.Author{}, Wrap(err, ErrDB, "update author")
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return models.Author{}, Wrap(err, ErrDB, "update author")
+	} else if n == 0 {
+		return models.Author{}, Wrap(sql.ErrNoRows, ErrNotFound, "author not found")
+	}
+	a, err = scanSQLiteAuthor(r.db.QueryRowContext(ctx, `SELECT `+sqliteAuthorColumns+` FROM authors WHERE id = ?`, id.Hex()))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Author{}, Wrap(err, ErrNotFound, "author not found")
+	}
+	return a, err
+}
+
+// Delete elimina un autor por id.
+//
+// Errores:
+//   - ErrNotFound si no existía; ErrConflict si algún post lo referencia; ErrDB si
+//     falla el driver.
+func (r *SQLiteAuthorRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(ctx, `DELETE FROM authors WHERE id = ?`, id.Hex())
+	if err != nil {
+		if isSQLiteForeignKeyViolation(err) {
+			return Wrap(err, ErrConflict, "delete author")
+		}
+		return Wrap(err, ErrDB, "delete author")
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return Wrap(err, ErrDB, "delete author")
+	}
+	if n == 0 {
+		return Wrap(sql.ErrNoRows, ErrNotFound, "author not found")
+	}
+	return nil
+}
+
+// insertSQLiteAuthor inserta a usando q (base o transacción; la usa el backfill).
+func insertSQLiteAuthor(ctx context.Context, q sqliteQuerier, a models.Author) error {
+	_, err := q.ExecContext(ctx,
+		`INSERT INTO authors (`+sqliteAuthorColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		a.ID.Hex(), a.Name, a.Slug, sqliteNullString(a.Bio), sqliteNullString(a.AvatarURL),
+		sqliteTime(a.CreatedAt), sqliteTimePtr(a.UpdatedAt))
+	if err != nil {
+		if isSQLiteUniqueViolation(err) {
+			return Wrap(err, ErrConflict, "insert author")
+		}
+		return Wrap(err, ErrDB, "insert author")
+	}
+	return nil
+}
+
+// scanSQLiteAuthor decodifica una fila con las columnas de sqliteAuthorColumns.
+// sql.ErrNoRows se retorna sin envolver para que el llamador decida el sentinel.
+func scanSQLiteAuthor(s sqliteScanner) (models.Author, error) {
+	var (
+		a                    models.Author
+		id, createdAt        string
+		bio, avatar, updated sql.NullString
+	)
+	if err := s.Scan(&id, &a.Name, &a.Slug, &bio, &avatar, &createdAt, &updated); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Author{}, err
+		}
+		return models.Author{}, Wrap(err, ErrDB, "decode author")
+	}
+
+	var err error
+	if a.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+		return models.Author{}, Wrap(err, ErrDB, "decode author id")
+	}
+	if a.CreatedAt, err = time.Parse(sqliteTimeLayout, createdAt); err != nil {
+		return models.Author{}, Wrap(err, ErrDB, "decode createdAt")
+	}
+	if a.UpdatedAt, err = parseSQLiteTime(updated); err != nil {
+		return models.Author{}, Wrap(err, ErrDB, "decode updatedAt")
+	}
+	a.Bio = bio.String
+	a.AvatarURL = avatar.String
+	return a, nil
+}
@@ -0,0 +1,307 @@
+// services/sqliteCommentRepository.go
+//
+// Paquete services: implementación de CommentRepository sobre SQLite.
+//
+// Convenciones:
+//   - Tabla comments con índices (post_id, status, id), (root_id, id) y (status, id).
+//   - Los ids son ObjectID en hex: su orden lexicográfico es el de creación, así el
+//     cursor se resuelve con id > ?.
+//   - Los comentarios se borran en cascada al eliminar el post (foreign_keys activo);
+//     DeleteByPosts existe por simetría con los demás drivers.
+package services
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// sqliteCommentColumns es la proyección usada por scanSQLiteComments.
+const sqliteCommentColumns = `id, post_id, parent_id, root_id, author, content, status, created_at, moderated_at, moderated_by`
+
+// SQLiteCommentRepository persiste comentarios en la tabla comments.
+type SQLiteCommentRepository struct {
+	db      *sql.DB
+	timeout time.Duration
+}
+
+// NewSQLiteCommentRepository crea un repositorio sobre db (ver OpenSQLite).
+// timeout limita cada operación (<=0 usa defaultTimeout).
+func NewSQLiteCommentRepository(db *sql.DB, timeout time.Duration) *SQLiteCommentRepository {
+	return &SQLiteCommentRepository{db: db, timeout: orDefaultTimeout(timeout)}
+}
+
+// Create inserta c; asigna un ObjectID nuevo si no trae uno.
+//
+// Errores:
+//   - ErrNotFound si el post ya no existe (FOREIGN KEY).
+//   - ErrDB: error del driver.
+func (r *SQLiteCommentRepository) Create(ctx context.Context, c models.Comment) (primitive.ObjectID, error) {
+	if c.ID.IsZero() {
+		c.ID = primitive.NewObjectID()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO comments (`+sqliteCommentColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		c.ID.Hex(), c.PostID.Hex(), sqliteObjectIDPtr(c.ParentID), sqliteObjectIDPtr(c.RootID),
+		c.Author, c.Content, c.Status, sqliteTime(c.CreatedAt), sqliteTimePtr(c.ModeratedAt),
+		sqliteNullString(c.ModeratedBy))
+	if err != nil {
+		if isSQLiteForeignKeyViolation(err) {
+			return primitive.NilObjectID, Wrap(err, ErrNotFound, "post not found")
+		}
+		return primitive.NilObjectID, Wrap(err, ErrDB, "insert comment")
+	}
+	return c.ID, nil
+}
+
+// Get recupera un comentario por id.
+//
+// Errores:
+//   - ErrNotFound si no existe; ErrDB si falla el driver.
+func (r *SQLiteCommentRepository) Get(ctx context.Context, id primitive.ObjectID) (models.Comment, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `SELECT `+sqliteCommentColumns+` FROM comments WHERE id = ?`, id.Hex())
+	if err != nil {
+		return models.Comment{}, Wrap(err, ErrDB, "find comment")
+	}
+	out, err := scanSQLiteComments(rows)
+	if err != nil {
+		return models.Comment{}, err
+	}
+	if len(out) == 0 {
+		return models.Comment{}, Wrap(errNoComment, ErrNotFound, "comment not found")
+	}
+	return out[0], nil
+}
+
+// List retorna los comentarios que cumplen q ordenados por id.
+//
+// Errores:
+//   - ErrDB: error del driver.
+func (r *SQLiteCommentRepository) List(ctx context.Context, q CommentQuery) ([]models.Comment, error) {
+	var (
+		where []string
+		args  []any
+	)
+	if !q.PostID.IsZero() {
+		where = append(where, `post_id = ?`)
+		args = append(args, q.PostID.Hex())
+	}
+	if q.Roots {
+		where = append(where, `root_id IS NULL`)
+	}
+	if q.RootIDs != nil {
+		if len(q.RootIDs) == 0 {
+			return []models.Comment{}, nil
+		}
+		where = append(where, `root_id IN (`+sqlitePlaceholders(len(q.RootIDs))+`)`)
+		for _, id := range q.RootIDs {
+			args = append(args, id.Hex())
+		}
+	}
+	if q.Status != "" {
+		where = append(where, `status = ?`)
+		args = append(args, q.Status)
+	}
+	if !q.After.IsZero() {
+		where = append(where, `id > ?`)
+		args = append(args, q.After.Hex())
+	}
+	query := `SELECT ` + sqliteCommentColumns + ` FROM comments`
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, ` AND `)
+	}
+	query += ` ORDER BY id`
+	if q.Limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, q.Limit)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, Wrap(err, ErrDB, "find comments")
+	}
+	return scanSQLiteComments(rows)
+}
+
+// SetStatus modera los comentarios de ids que tengan otro estado.
+//
+// Errores:
+//   - ErrDB: error del driver.
+func (r *SQLiteCommentRepository) SetStatus(ctx context.Context, ids []primitive.ObjectID, status, moderatedBy string, moderatedAt time.Time) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	args := []any{status, sqliteTime(moderatedAt), sqliteNullString(moderatedBy)}
+	for _, id := range ids {
+		args = append(args, id.Hex())
+	}
+	args = append(args, status)
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE comments SET status = ?, moderated_at = ?, moderated_by = ?
+		 WHERE id IN (`+sqlitePlaceholders(len(ids))+`) AND status <> ?`, args...)
+	if err != nil {
+		return 0, Wrap(err, ErrDB, "moderate comments")
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, Wrap(err, ErrDB, "moderate comments")
+	}
+	return n, nil
+}
+
+// CountByPosts agrupa por post_id los comentarios con status de postIDs.
+//
+// Errores:
+//   - ErrDB: error del driver.
+func (r *SQLiteCommentRepository) CountByPosts(ctx context.Context, postIDs []primitive.ObjectID, status string) (map[primitive.ObjectID]int64, error) {
+	out := map[primitive.ObjectID]int64{}
+	if len(postIDs) == 0 {
+		return out, nil
+	}
+	args := make([]any, 0, len(postIDs)+1)
+	for _, id := range postIDs {
+		args = append(args, id.Hex())
+	}
+	args = append(args, status)
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT post_id, COUNT(*) FROM comments
+		 WHERE post_id IN (`+sqlitePlaceholders(len(postIDs))+`) AND status = ?
+		 GROUP BY post_id`, args...)
+	if err != nil {
+		return nil, Wrap(err, ErrDB, "count comments")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			hex string
+			n   int64
+		)
+		if err := rows.Scan(&hex, &n); err != nil {
+			return nil, Wrap(err, ErrDB, "decode comment count")
+		}
+		id, err := primitive.ObjectIDFromHex(hex)
+		if err != nil {
+			return nil, Wrap(err, ErrDB, "decode comment post id")
+		}
+		out[id] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, Wrap(err, ErrDB, "count comments")
+	}
+	return out, nil
+}
+
+// DeleteByPosts borra los comentarios de postIDs.
+//
+// Errores:
+//   - ErrDB: error del driver.
+func (r *SQLiteCommentRepository) DeleteByPosts(ctx context.Context, postIDs []primitive.ObjectID) (int64, error) {
+	if len(postIDs) == 0 {
+		return 0, nil
+	}
+	args := make([]any, len(postIDs))
+	for i, id := range postIDs {
+		args[i] = id.Hex()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(ctx,
+		`DELETE FROM comments WHERE post_id IN (`+sqlitePlaceholders(len(args))+`)`, args...)
+	if err != nil {
+		return 0, Wrap(err, ErrDB, "delete comments")
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, Wrap(err, ErrDB, "delete comments")
+	}
+	return n, nil
+}
+
+// scanSQLiteComments decodifica filas de sqliteCommentColumns y cierra rows.
+func scanSQLiteComments(rows *sql.Rows) ([]models.Comment, error) {
+	defer rows.Close()
+
+	out := []models.Comment{}
+	for rows.Next() {
+		var (
+			c                      models.Comment
+			id, post, created      string
+			parent, root           sql.NullString
+			moderatedAt, moderator sql.NullString
+		)
+		if err := rows.Scan(&id, &post, &parent, &root, &c.Author, &c.Content, &c.Status,
+			&created, &moderatedAt, &moderator); err != nil {
+			return nil, Wrap(err, ErrDB, "decode comment")
+		}
+		var err error
+		if c.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+			return nil, Wrap(err, ErrDB, "decode comment id")
+		}
+		if c.PostID, err = primitive.ObjectIDFromHex(post); err != nil {
+			return nil, Wrap(err, ErrDB, "decode comment post id")
+		}
+		if c.ParentID, err = parseSQLiteObjectID(parent); err != nil {
+			return nil, Wrap(err, ErrDB, "decode comment parent id")
+		}
+		if c.RootID, err = parseSQLiteObjectID(root); err != nil {
+			return nil, Wrap(err, ErrDB, "decode comment root id")
+		}
+		if c.CreatedAt, err = time.Parse(sqliteTimeLayout, created); err != nil {
+			return nil, Wrap(err, ErrDB, "decode comment createdAt")
+		}
+		if c.ModeratedAt, err = parseSQLiteTime(moderatedAt); err != nil {
+			return nil, Wrap(err, ErrDB, "decode comment moderatedAt")
+		}
+		c.ModeratedBy = moderator.String
+		out = append(out, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, Wrap(err, ErrDB, "find comments")
+	}
+	return out, nil
+}
+
+// sqliteObjectIDPtr convierte un *ObjectID en hex; nil → NULL.
+func sqliteObjectIDPtr(id *primitive.ObjectID) any {
+	if id == nil {
+		return nil
+	}
+	return id.Hex()
+}
+
+// parseSQLiteObjectID decodifica una columna de ObjectID opcional.
+func parseSQLiteObjectID(s sql.NullString) (*primitive.ObjectID, error) {
+	if !s.Valid {
+		return nil, nil
+	}
+	id, err := primitive.ObjectIDFromHex(s.String)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
@@ -0,0 +1,871 @@
+// services/sqlitePostRepository.go
+//
+// Paquete services: implementación de PostRepository sobre SQLite.
+//
+// Convenciones:
+//   - Todas las operaciones aplican el timeout configurado, igual que Mongo.
+//   - Las escrituras que tocan posts y post_tags se hacen en una transacción.
+//   - sql.ErrNoRows → ErrNotFound; cualquier otra falla → ErrDB.
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// sqliteTimeLayout es un RFC3339 de ancho fijo (nanosegundos completos, siempre UTC).
+const sqliteTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
+// sqlitePostColumns es la proyección usada por scanSQLitePost (sin tags).
+const sqlitePostColumns = `p.id, p.title, p.author, p.content, p.published, p.published_at, p.created_at, p.updated_at, p.version, p.deleted_at, p.deleted_by, p.slug, p.publish_at, p.unpublish_at, p.status, p.author_id`
+
+// SQLitePostRepository persiste posts en las tablas posts/post_tags.
+type SQLitePostRepository struct {
+	db      *sql.DB
+	timeout time.Duration
+}
+
+// NewSQLitePostRepository crea un repositorio sobre db (ver OpenSQLite).
+// timeout limita cada operación (<=0 usa defaultTimeout).
+func NewSQLitePostRepository(db *sql.DB, timeout time.Duration) *SQLitePostRepository {
+	return &SQLitePostRepository{db: db, timeout: orDefaultTimeout(timeout)}
+}
+
+// Create inserta p y sus tags; asigna un ObjectID nuevo si no trae uno.
+//
+// Errores:
+//   - ErrConflict: slug en uso (índice único idx_posts_slug).
+//   - ErrDB: error del driver.
+func (r *SQLitePostRepository) Create(ctx context.Context, p models.Post) (primitive.ObjectID, error) {
+	if p.ID.IsZero() {
+		p.ID = primitive.NewObjectID()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return primitive.NilObjectID, Wrap(err, ErrDB, "begin tx")
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO posts (id, title, slug, author, author_id, content, published, status, published_at, publish_at, unpublish_at, created_at, updated_at, version)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		p.ID.Hex(), p.Title, sqliteNullString(p.Slug), p.Author, sqliteObjectID(p.AuthorID), p.Content, p.Published, sqliteNullString(p.Status),
+		sqliteTimePtr(p.PublishedAt), sqliteTimePtr(p.PublishAt), sqliteTimePtr(p.UnpublishAt),
+		sqliteTime(p.CreatedAt), sqliteTimePtr(p.UpdatedAt), p.Version)
+	if err != nil {
+		if isSQLiteUniqueViolation(err) {
+			return primitive.NilObjectID, Wrap(err, ErrConflict, "insert post")
+		}
+		return primitive.NilObjectID, Wrap(err, ErrDB, "insert post")
+	}
+	if err := replaceSQLiteTags(ctx, tx, p.ID, p.Tags); err != nil {
+		return primitive.NilObjectID, err
+	}
+	if err := replaceSQLiteOldSlugs(ctx, tx, p.ID, p.OldSlugs); err != nil {
+		return primitive.NilObjectID, err
+	}
+	if err := tx.Commit(); err != nil {
+		return primitive.NilObjectID, Wrap(err, ErrDB, "commit insert post")
+	}
+	return p.ID, nil
+}
+
+// Get recupera un post por ObjectID junto con sus tags.
+//
+// Errores:
+//   - ErrNotFound si no existe; ErrDB si falla el driver.
+func (r *SQLitePostRepository) Get(ctx context.Context, id primitive.ObjectID) (models.Post, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	return getSQLitePost(ctx, r.db, id)
+}
+
+// GetBySlug busca por slug vigente y, si no hay, en post_old_slugs.
+//
+// Errores:
+//   - ErrNotFound si ningún post tiene ni tuvo ese slug; ErrDB si falla el driver.
+func (r *SQLitePostRepository) GetBySlug(ctx context.Context, slug string) (models.Post, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var hex string
+	err := r.db.QueryRowContext(ctx,
+		`SELECT id FROM posts WHERE slug = ?
+		 UNION ALL
+		 SELECT post_id FROM post_old_slugs WHERE slug = ?
+		 LIMIT 1`, slug, slug).Scan(&hex)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Post{}, Wrap(err, ErrNotFound, "post not found")
+		}
+		return models.Post{}, Wrap(err, ErrDB, "find post by slug")
+	}
+	id, err := primitive.ObjectIDFromHex(hex)
+	if err != nil {
+		return models.Post{}, Wrap(err, ErrDB, "decode post id")
+	}
+	return getSQLitePost(ctx, r.db, id)
+}
+
+// Update aplica los campos editables de p, incrementa version y reemplaza sus tags
+// en una transacción. Con expectedVersion > 0 el UPDATE se condiciona a la versión.
+//
+// Errores:
+//   - ErrNotFound si el post no existe; ErrConflict si la versión cambió o el slug está
+//     en uso; ErrDB si falla el driver.
+func (r *SQLitePostRepository) Update(ctx context.Context, id primitive.ObjectID, p models.Post, expectedVersion int64) (models.Post, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Post{}, Wrap(err, ErrDB, "begin tx")
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx,
+		`UPDATE posts
+		    SET title = ?, slug = ?, author = ?, author_id = ?, content = ?, published = ?, status = COALESCE(?, status),
+		        updated_at = ?, published_at = COALESCE(?, published_at), publish_at = ?, unpublish_at = ?,
+		        version = version + 1
+		  WHERE id = ? AND (? = 0 OR version = ?)`,
+		p.Title, sqliteNullString(p.Slug), p.Author, sqliteObjectID(p.AuthorID), p.Content, p.Published,
+		sqliteNullString(p.Status), sqliteTimePtr(p.UpdatedAt),
+		sqliteTimePtr(p.PublishedAt), sqliteTimePtr(p.PublishAt), sqliteTimePtr(p.UnpublishAt),
+		id.Hex(), expectedVersion, expectedVersion)
+	if err != nil {
+		if isSQLiteUniqueViolation(err) {
+			return models.Post{}, Wrap(err, ErrConflict, "update post")
+		}
+		return models.Post{}, Wrap(err, ErrDB, "update post")
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return models.Post{}, Wrap(err, ErrDB, "update post")
+	} else if n == 0 {
+		return models.Post{}, sqliteMissOrConflict(ctx, tx, id, "post not found after update")
+	}
+	if err := replaceSQLiteTags(ctx, tx, id, p.Tags); err != nil {
+		return models.Post{}, err
+	}
+	if err := replaceSQLiteOldSlugs(ctx, tx, id, p.OldSlugs); err != nil {
+		return models.Post{}, err
+	}
+
+	updated, err := getSQLitePost(ctx, tx, id)
+	if err != nil {
+		return models.Post{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return models.Post{}, Wrap(err, ErrDB, "commit update post")
+	}
+	return updated, nil
+}
+
+// UpdateTags aplica change sobre post_tags dentro de la misma transacción que
+// incrementa version, por lo que dos cambios concurrentes se serializan.
+//
+// Errores:
+//   - ErrNotFound si el post no existe; ErrConflict si la versión cambió; ErrDB si falla el driver.
+func (r *SQLitePostRepository) UpdateTags(ctx context.Context, id primitive.ObjectID, change TagChange, updatedAt time.Time, expectedVersion int64) (models.Post, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Post{}, Wrap(err, ErrDB, "begin tx")
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx,
+		`UPDATE posts SET updated_at = ?, version = version + 1 WHERE id = ? AND (? = 0 OR version = ?)`,
+		sqliteTime(updatedAt), id.Hex(), expectedVersion, expectedVersion)
+	if err != nil {
+		return models.Post{}, Wrap(err, ErrDB, "update post tags")
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return models.Post{}, Wrap(err, ErrDB, "update post tags")
+	} else if n == 0 {
+		return models.Post{}, sqliteMissOrConflict(ctx, tx, id, "post not found after update")
+	}
+
+	updated, err := getSQLitePost(ctx, tx, id)
+	if err != nil {
+		return models.Post{}, err
+	}
+	updated.Tags = change.apply(updated.Tags)
+	if err := replaceSQLiteTags(ctx, tx, id, updated.Tags); err != nil {
+		return models.Post{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return models.Post{}, Wrap(err, ErrDB, "commit update post tags")
+	}
+	return updated, nil
+}
+
+// SetDeleted fija o limpia deleted_at/deleted_by e incrementa version en una transacción.
+//
+// Errores:
+//   - ErrNotFound si el post no existe; ErrConflict si la versión cambió; ErrDB si falla el driver.
+func (r *SQLitePostRepository) SetDeleted(ctx context.Context, id primitive.ObjectID, deletedAt *time.Time, deletedBy string, expectedVersion int64) (models.Post, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Post{}, Wrap(err, ErrDB, "begin tx")
+	}
+	defer tx.Rollback()
+
+	var by any
+	if deletedAt != nil {
+		by = deletedBy
+	}
+	res, err := tx.ExecContext(ctx,
+		`UPDATE posts SET deleted_at = ?, deleted_by = ?, version = version + 1
+		  WHERE id = ? AND (? = 0 OR version = ?)`,
+		sqliteTimePtr(deletedAt), by, id.Hex(), expectedVersion, expectedVersion)
+	if err != nil {
+		return models.Post{}, Wrap(err, ErrDB, "update post deleted")
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return models.Post{}, Wrap(err, ErrDB, "update post deleted")
+	} else if n == 0 {
+		return models.Post{}, sqliteMissOrConflict(ctx, tx, id, "post not found after update")
+	}
+
+	updated, err := getSQLitePost(ctx, tx, id)
+	if err != nil {
+		return models.Post{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return models.Post{}, Wrap(err, ErrDB, "commit update post deleted")
+	}
+	return updated, nil
+}
+
+// PurgeDeleted borra los posts con deleted_at anterior a before (post_tags en cascada)
+// y retorna sus ids.
+//
+// Errores:
+//   - ErrDB si falla el driver.
+func (r *SQLitePostRepository) PurgeDeleted(ctx context.Context, before time.Time) ([]primitive.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx,
+		`DELETE FROM posts WHERE deleted_at IS NOT NULL AND deleted_at < ? RETURNING id`, sqliteTime(before))
+	if err != nil {
+		return nil, Wrap(err, ErrDB, "purge deleted posts")
+	}
+	defer rows.Close()
+
+	var ids []primitive.ObjectID
+	for rows.Next() {
+		var hex string
+		if err := rows.Scan(&hex); err != nil {
+			return nil, Wrap(err, ErrDB, "scan purged post")
+		}
+		id, err := primitive.ObjectIDFromHex(hex)
+		if err != nil {
+			return nil, Wrap(err, ErrDB, "decode purged post id")
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, Wrap(err, ErrDB, "purge deleted posts")
+	}
+	return ids, nil
+}
+
+// Delete elimina un post (post_tags se borra en cascada); con expectedVersion > 0
+// sólo si la versión coincide.
+//
+// Errores:
+//   - ErrNotFound si no existía; ErrConflict si la versión cambió; ErrDB si falla el driver.
+func (r *SQLitePostRepository) Delete(ctx context.Context, id primitive.ObjectID, expectedVersion int64) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(ctx, `DELETE FROM posts WHERE id = ? AND (? = 0 OR version = ?)`,
+		id.Hex(), expectedVersion, expectedVersion)
+	if err != nil {
+		return Wrap(err, ErrDB, "delete post")
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return Wrap(err, ErrDB, "delete post")
+	} else if n == 0 {
+		return sqliteMissOrConflict(ctx, r.db, id, "post not found")
+	}
+	return nil
+}
+
+// sqliteMissOrConflict distingue, tras una escritura condicionada que no afectó filas,
+// si el post no existe (ErrNotFound) o si cambió de versión (ErrVersionMismatch).
+func sqliteMissOrConflict(ctx context.Context, q sqliteQuerier, id primitive.ObjectID, msg string) error {
+	var n int
+	if err := q.QueryRowContext(ctx, `SELECT COUNT(*) FROM posts WHERE id = ?`, id.Hex()).Scan(&n); err != nil {
+		return Wrap(err, ErrDB, "count posts")
+	}
+	if n == 0 {
+		return Wrap(sql.ErrNoRows, ErrNotFound, msg)
+	}
+	return Wrap(errVersionMismatch, ErrVersionMismatch, msg)
+}
+
+// List traduce ListPostsParams a SQL y retorna la página y el total.
+//
+// Notas:
+//   - Q se resuelve con FTS5 (ver sqliteFTSQuery); Tag con EXISTS sobre post_tags.
+//   - SQLite ordena NULL primero en ASC y último en DESC, igual que Mongo.
+func (r *SQLitePostRepository) List(ctx context.Context, p ListPostsParams) ([]models.Post, int64, error) {
+	where, args := sqliteListFilter(p)
+
+	order := "p.published_at DESC, p.id"
+	switch {
+	case p.Deleted:
+		order = "p.deleted_at DESC, p.id"
+	case p.Scheduled:
+		// min() con varios argumentos retorna NULL si alguno lo es.
+		order = "min(COALESCE(p.publish_at, p.unpublish_at), COALESCE(p.unpublish_at, p.publish_at)), p.id"
+	case p.SortField == "publishedAt":
+		order = "p.published_at ASC, p.id"
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM posts p`+where, args...).Scan(&total); err != nil {
+		return nil, 0, Wrap(err, ErrDB, "count posts")
+	}
+
+	query := `SELECT ` + sqlitePostColumns + ` FROM posts p` + where +
+		` ORDER BY ` + order + ` LIMIT ? OFFSET ?`
+	rows, err := r.db.QueryContext(ctx, query, append(args, p.Limit, (p.Page-1)*p.Limit)...)
+	if err != nil {
+		return nil, 0, Wrap(err, ErrDB, "find posts")
+	}
+	items := make([]models.Post, 0, p.Limit)
+	for rows.Next() {
+		post, err := scanSQLitePost(rows)
+		if err != nil {
+			rows.Close()
+			return nil, 0, err
+		}
+		items = append(items, post)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, 0, Wrap(err, ErrDB, "cursor error")
+	}
+
+	if err := loadSQLiteTags(ctx, r.db, items); err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// AggregateByTag agrupa post_tags por etiqueta y retorna el top-N.
+// Como $unwind, cada aparición de la etiqueta en un post cuenta una vez.
+//
+// Errores:
+//   - ErrDB ante errores de la consulta.
+func (r *SQLitePostRepository) AggregateByTag(ctx context.Context, limit int, onlyPublished *bool, status string) ([]TagMetric, error) {
+	query := `SELECT t.tag, COUNT(*) FROM post_tags t JOIN posts p ON p.id = t.post_id
+	           WHERE t.tag <> '' AND p.deleted_at IS NULL`
+	var args []any
+	if onlyPublished != nil {
+		query += ` AND p.published = ?`
+		args = append(args, *onlyPublished)
+	}
+	if status != "" {
+		query += ` AND p.status = ?`
+		args = append(args, status)
+	}
+	query += ` GROUP BY t.tag ORDER BY COUNT(*) DESC, t.tag LIMIT ?`
+	args = append(args, limit)
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, Wrap(err, ErrDB, "aggregate by-tag")
+	}
+	defer rows.Close()
+
+	out := make([]TagMetric, 0, limit)
+	for rows.Next() {
+		var m TagMetric
+		if err := rows.Scan(&m.Tag, &m.Count); err != nil {
+			return nil, Wrap(err, ErrDB, "decode metric row")
+		}
+		out = append(out, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, Wrap(err, ErrDB, "cursor error")
+	}
+	return out, nil
+}
+
+// CountByAuthor cuenta los posts de authorID (idx_posts_author_id), incluidos los de la
+// papelera.
+//
+// Errores:
+//   - ErrDB si falla el driver.
+func (r *SQLitePostRepository) CountByAuthor(ctx context.Context, authorID primitive.ObjectID) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var n int64
+	if err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM posts WHERE author_id = ?`, authorID.Hex()).Scan(&n); err != nil {
+		return 0, Wrap(err, ErrDB, "count posts by author")
+	}
+	return n, nil
+}
+
+// SetAuthorName copia name en los posts de authorID que tienen otro nombre.
+//
+// Errores:
+//   - ErrDB si falla el driver.
+func (r *SQLitePostRepository) SetAuthorName(ctx context.Context, authorID primitive.ObjectID, name string, updatedAt time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE posts SET author = ?, updated_at = ?, version = version + 1 WHERE author_id = ? AND author <> ?`,
+		name, sqliteTime(updatedAt), authorID.Hex(), name)
+	if err != nil {
+		return 0, Wrap(err, ErrDB, "update author name")
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, Wrap(err, ErrDB, "update author name")
+	}
+	return n, nil
+}
+
+// ReplaceTags reescribe, en una transacción, las etiquetas de los posts que tienen
+// alguna de from (idx_post_tags_tag).
+//
+// Errores:
+//   - ErrDB si falla el driver.
+func (r *SQLitePostRepository) ReplaceTags(ctx context.Context, from []string, to string, updatedAt time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, Wrap(err, ErrDB, "begin tx")
+	}
+	defer tx.Rollback()
+
+	args := make([]any, 0, len(from))
+	for _, tag := range from {
+		args = append(args, tag)
+	}
+	ids, lists, err := sqliteTagLists(ctx, tx,
+		`SELECT post_id, tag FROM post_tags WHERE post_id IN (
+			SELECT post_id FROM post_tags WHERE tag IN (`+sqlitePlaceholders(len(args))+`)
+		) ORDER BY post_id, position`, args...)
+	if err != nil {
+		return 0, Wrap(err, ErrDB, "find post tags")
+	}
+
+	var n int64
+	for _, id := range ids {
+		tags, changed := replaceTags(lists[id], from, to)
+		if !changed {
+			continue
+		}
+		if err := replaceSQLiteTags(ctx, tx, id, tags); err != nil {
+			return 0, err
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE posts SET updated_at = ?, version = version + 1 WHERE id = ?`,
+			sqliteTime(updatedAt), id.Hex()); err != nil {
+			return 0, Wrap(err, ErrDB, "update post tags")
+		}
+		n++
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, Wrap(err, ErrDB, "commit replace tags")
+	}
+	return n, nil
+}
+
+// CountTags agrupa post_tags por etiqueta y estado (idx_post_tags_tag si tags no es nil).
+//
+// Errores:
+//   - ErrDB si falla el driver.
+func (r *SQLitePostRepository) CountTags(ctx context.Context, tags []string) ([]models.TagCount, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `SELECT t.tag, p.status, COUNT(*) FROM post_tags t JOIN posts p ON p.id = t.post_id
+		WHERE p.deleted_at IS NULL`
+	args := make([]any, 0, len(tags))
+	if tags != nil {
+		for _, tag := range tags {
+			args = append(args, tag)
+		}
+		query += ` AND t.tag IN (` + sqlitePlaceholders(len(args)) + `)`
+	}
+	rows, err := r.db.QueryContext(ctx, query+` GROUP BY t.tag, p.status ORDER BY t.tag`, args...)
+	if err != nil {
+		return nil, Wrap(err, ErrDB, "count tags")
+	}
+	defer rows.Close()
+
+	var set tagCountSet
+	for rows.Next() {
+		var tag, status string
+		var n int64
+		if err := rows.Scan(&tag, &status, &n); err != nil {
+			return nil, Wrap(err, ErrDB, "decode tag count")
+		}
+		set.add(tag, status, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, Wrap(err, ErrDB, "cursor error")
+	}
+	return set.counts(), nil
+}
+
+// sqliteTagLists ejecuta query (filas post_id, tag ordenadas por post y posición) y
+// retorna los ids en el orden leído y las etiquetas de cada uno.
+func sqliteTagLists(ctx context.Context, q sqliteQuerier, query string, args ...any) ([]primitive.ObjectID, map[primitive.ObjectID][]string, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var ids []primitive.ObjectID
+	lists := map[primitive.ObjectID][]string{}
+	for rows.Next() {
+		var postID, tag string
+		if err := rows.Scan(&postID, &tag); err != nil {
+			return nil, nil, err
+		}
+		id, err := primitive.ObjectIDFromHex(postID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, ok := lists[id]; !ok {
+			ids = append(ids, id)
+		}
+		lists[id] = append(lists[id], tag)
+	}
+	return ids, lists, rows.Err()
+}
+
+// sqliteQuerier abstrae *sql.DB y *sql.Tx para las consultas compartidas.
+type sqliteQuerier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// sqliteScanner abstrae *sql.Row y *sql.Rows.
+type sqliteScanner interface {
+	Scan(dest ...any) error
+}
+
+// getSQLitePost lee un post con sus tags usando q (base o transacción).
+func getSQLitePost(ctx context.Context, q sqliteQuerier, id primitive.ObjectID) (models.Post, error) {
+	row := q.QueryRowContext(ctx, `SELECT `+sqlitePostColumns+` FROM posts p WHERE p.id = ?`, id.Hex())
+	post, err := scanSQLitePost(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Post{}, Wrap(err, ErrNotFound, "post not found")
+		}
+		return models.Post{}, err
+	}
+
+	items := []models.Post{post}
+	if err := loadSQLiteTags(ctx, q, items); err != nil {
+		return models.Post{}, err
+	}
+	return items[0], nil
+}
+
+// scanSQLitePost decodifica una fila con las columnas de sqlitePostColumns.
+// sql.ErrNoRows se retorna sin envolver para que el llamador decida el sentinel.
+func scanSQLitePost(s sqliteScanner) (models.Post, error) {
+	var (
+		post                              models.Post
+		id, createdAt                     string
+		publishedAt, updatedAt, deletedAt sql.NullString
+		deletedBy, slug, status, authorID sql.NullString
+		publishAt, unpublishAt            sql.NullString
+	)
+	if err := s.Scan(&id, &post.Title, &post.Author, &post.Content, &post.Published,
+		&publishedAt, &createdAt, &updatedAt, &post.Version, &deletedAt, &deletedBy, &slug,
+		&publishAt, &unpublishAt, &status, &authorID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Post{}, err
+		}
+		return models.Post{}, Wrap(err, ErrDB, "decode post")
+	}
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.Post{}, Wrap(err, ErrDB, "decode post id")
+	}
+	post.ID = oid
+	if post.CreatedAt, err = time.Parse(sqliteTimeLayout, createdAt); err != nil {
+		return models.Post{}, Wrap(err, ErrDB, "decode createdAt")
+	}
+	if post.PublishedAt, err = parseSQLiteTime(publishedAt); err != nil {
+		return models.Post{}, Wrap(err, ErrDB, "decode publishedAt")
+	}
+	if post.UpdatedAt, err = parseSQLiteTime(updatedAt); err != nil {
+		return models.Post{}, Wrap(err, ErrDB, "decode updatedAt")
+	}
+	if post.DeletedAt, err = parseSQLiteTime(deletedAt); err != nil {
+		return models.Post{}, Wrap(err, ErrDB, "decode deletedAt")
+	}
+	if post.PublishAt, err = parseSQLiteTime(publishAt); err != nil {
+		return models.Post{}, Wrap(err, ErrDB, "decode publishAt")
+	}
+	if post.UnpublishAt, err = parseSQLiteTime(unpublishAt); err != nil {
+		return models.Post{}, Wrap(err, ErrDB, "decode unpublishAt")
+	}
+	if authorID.Valid {
+		if post.AuthorID, err = primitive.ObjectIDFromHex(authorID.String); err != nil {
+			return models.Post{}, Wrap(err, ErrDB, "decode post authorId")
+		}
+	}
+	post.DeletedBy = deletedBy.String
+	post.Slug = slug.String
+	post.Status = status.String
+	return post, nil
+}
+
+// loadSQLiteTags completa Tags y OldSlugs de cada post en items con una consulta por tabla.
+func loadSQLiteTags(ctx context.Context, q sqliteQuerier, items []models.Post) error {
+	if len(items) == 0 {
+		return nil
+	}
+	byID := make(map[string]int, len(items))
+	args := make([]any, 0, len(items))
+	for i, post := range items {
+		byID[post.ID.Hex()] = i
+		args = append(args, post.ID.Hex())
+	}
+	in := sqlitePlaceholders(len(args))
+
+	err := scanSQLitePostValues(ctx, q, byID,
+		`SELECT post_id, tag FROM post_tags WHERE post_id IN (`+in+`) ORDER BY post_id, position`, args,
+		func(i int, tag string) { items[i].Tags = append(items[i].Tags, tag) })
+	if err != nil {
+		return Wrap(err, ErrDB, "find post tags")
+	}
+	err = scanSQLitePostValues(ctx, q, byID,
+		`SELECT post_id, slug FROM post_old_slugs WHERE post_id IN (`+in+`) ORDER BY post_id, position`, args,
+		func(i int, slug string) { items[i].OldSlugs = append(items[i].OldSlugs, slug) })
+	if err != nil {
+		return Wrap(err, ErrDB, "find post old slugs")
+	}
+	return nil
+}
+
+// scanSQLitePostValues ejecuta query (filas post_id, valor) y llama a add con el índice
+// en items (byID) de cada fila.
+func scanSQLitePostValues(ctx context.Context, q sqliteQuerier, byID map[string]int, query string, args []any, add func(int, string)) error {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID, value string
+		if err := rows.Scan(&postID, &value); err != nil {
+			return err
+		}
+		add(byID[postID], value)
+	}
+	return rows.Err()
+}
+
+// replaceSQLiteOldSlugs reemplaza los slugs anteriores de un post preservando el orden.
+// La clave primaria de post_old_slugs hace que un slug anterior pertenezca a un solo post.
+func replaceSQLiteOldSlugs(ctx context.Context, tx *sql.Tx, id primitive.ObjectID, slugs []string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM post_old_slugs WHERE post_id = ?`, id.Hex()); err != nil {
+		return Wrap(err, ErrDB, "delete post old slugs")
+	}
+	for i, slug := range slugs {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO post_old_slugs (slug, post_id, position) VALUES (?, ?, ?)`, slug, id.Hex(), i); err != nil {
+			if isSQLiteUniqueViolation(err) {
+				return Wrap(err, ErrConflict, "insert post old slug")
+			}
+			return Wrap(err, ErrDB, "insert post old slug")
+		}
+	}
+	return nil
+}
+
+// isSQLiteUniqueViolation detecta una violación de UNIQUE o PRIMARY KEY.
+func isSQLiteUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	code := sqliteErr.Code()
+	return code == sqlite3.SQLITE_CONSTRAINT_UNIQUE || code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+}
+
+// isSQLiteForeignKeyViolation detecta una violación de FOREIGN KEY.
+func isSQLiteForeignKeyViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY
+}
+
+// sqliteObjectID convierte un ObjectID en hex; el cero → NULL (referencias opcionales).
+func sqliteObjectID(id primitive.ObjectID) any {
+	if id.IsZero() {
+		return nil
+	}
+	return id.Hex()
+}
+
+// sqliteNullString convierte "" en NULL (columnas únicas opcionales como slug).
+func sqliteNullString(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// replaceSQLiteTags reemplaza las etiquetas de un post preservando el orden.
+func replaceSQLiteTags(ctx context.Context, tx *sql.Tx, id primitive.ObjectID, tags []string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM post_tags WHERE post_id = ?`, id.Hex()); err != nil {
+		return Wrap(err, ErrDB, "delete post tags")
+	}
+	for i, tag := range tags {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO post_tags (post_id, position, tag) VALUES (?, ?, ?)`, id.Hex(), i, tag); err != nil {
+			return Wrap(err, ErrDB, "insert post tag")
+		}
+	}
+	return nil
+}
+
+// sqliteListFilter construye la cláusula WHERE (con espacio inicial) y sus argumentos.
+func sqliteListFilter(p ListPostsParams) (string, []any) {
+	conds := []string{`p.deleted_at IS NULL`}
+	if p.Deleted {
+		conds[0] = `p.deleted_at IS NOT NULL`
+	}
+	if p.Scheduled {
+		conds = append(conds, `(p.publish_at IS NOT NULL OR p.unpublish_at IS NOT NULL)`)
+	}
+	var args []any
+	if p.Q != "" {
+		if match, ok := sqliteFTSQuery(parseTextQuery(p.Q)); ok {
+			conds = append(conds, `p.rowid IN (SELECT rowid FROM posts_fts WHERE posts_fts MATCH ?)`)
+			args = append(args, match)
+		} else {
+			conds = append(conds, `0`)
+		}
+	}
+	if p.Tag != "" {
+		conds = append(conds, `EXISTS (SELECT 1 FROM post_tags t WHERE t.post_id = p.id AND t.tag = ?)`)
+		args = append(args, p.Tag)
+	}
+	if p.Published != nil {
+		conds = append(conds, `p.published = ?`)
+		args = append(args, *p.Published)
+	}
+	if p.Status != "" {
+		conds = append(conds, `p.status = ?`)
+		args = append(args, p.Status)
+	}
+	if !p.AuthorID.IsZero() {
+		conds = append(conds, `p.author_id = ?`)
+		args = append(args, p.AuthorID.Hex())
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// sqliteFTSQuery traduce una consulta estilo $text a sintaxis FTS5.
+//
+// Reglas (las mismas que textQuery.matches):
+//   - Frases: todas obligatorias ("a b" AND "c d").
+//   - Sin frases: cualquier término ("a" OR "b").
+//   - Términos negados: ... NOT "x".
+//   - Sin términos positivos retorna ok=false: no hay coincidencias posibles
+//     (como $text con sólo negaciones) y FTS5 no admite una expresión vacía.
+func sqliteFTSQuery(tq textQuery) (match string, ok bool) {
+	quote := func(s string) string { return `"` + strings.ReplaceAll(s, `"`, `""`) + `"` }
+
+	var positive string
+	switch {
+	case len(tq.phrases) > 0:
+		parts := make([]string, 0, len(tq.phrases))
+		for _, ph := range tq.phrases {
+			parts = append(parts, quote(ph))
+		}
+		positive = strings.Join(parts, " AND ")
+	case len(tq.terms) > 0:
+		parts := make([]string, 0, len(tq.terms))
+		for _, t := range tq.terms {
+			parts = append(parts, quote(t))
+		}
+		positive = strings.Join(parts, " OR ")
+	default:
+		return "", false
+	}
+
+	out := "(" + positive + ")"
+	for _, n := range tq.negated {
+		out += " NOT " + quote(n)
+	}
+	return out, true
+}
+
+// sqlitePlaceholders retorna "?, ?, ..." con n marcadores.
+func sqlitePlaceholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// sqliteTime formatea t en UTC con sqliteTimeLayout.
+func sqliteTime(t time.Time) string {
+	return t.UTC().Format(sqliteTimeLayout)
+}
+
+// sqliteTimePtr formatea un *time.Time; nil → NULL.
+func sqliteTimePtr(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return sqliteTime(*t)
+}
+
+// parseSQLiteTime decodifica una columna de fecha opcional.
+func parseSQLiteTime(s sql.NullString) (*time.Time, error) {
+	if !s.Valid {
+		return nil, nil
+	}
+	t, err := time.Parse(sqliteTimeLayout, s.String)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
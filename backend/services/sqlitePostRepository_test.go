@@ -0,0 +1,38 @@
+package services
+
+import "testing"
+
+func TestSQLiteFTSQuery(t *testing.T) {
+	tests := []struct {
+		q      string
+		want   string
+		wantOK bool
+	}{
+		{"mongodb", `("mongodb")`, true},
+		{"Introducción  Mongo", `("introduccion" OR "mongo")`, true},
+		{`"indices y consultas"`, `("indices y consultas")`, true},
+		{`"hola mundo" "se fue" editor`, `("hola mundo" AND "se fue")`, true},
+		{"consultas -mongo -sqlite", `("consultas") NOT "mongo" NOT "sqlite"`, true},
+		{`"hola mundo" -adios`, `("hola mundo") NOT "adios"`, true},
+		{"-mongo", "", false},
+		{"-mongo -sqlite", "", false},
+		{`""`, "", false},
+		{"", "", false},
+		{`"hola`, `("hola")`, true},
+		{`hola"mundo`, `("hola" OR "mundo")`, true},
+		{"AND OR NOT", `("and" OR "or" OR "not")`, true},
+		{"fts5*", `("fts5")`, true},
+	}
+	for _, tt := range tests {
+		got, ok := sqliteFTSQuery(parseTextQuery(tt.q))
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("q=%s: %q %v, want %q %v", tt.q, got, ok, tt.want, tt.wantOK)
+		}
+	}
+
+	// Las comillas dentro de un término se duplican (parseTextQuery ya no las deja pasar).
+	got, ok := sqliteFTSQuery(textQuery{terms: []string{`a"b`}, negated: []string{`"c`}})
+	if want := `("a""b") NOT """c"`; got != want || !ok {
+		t.Fatalf("comillas: %q %v, want %q", got, ok, want)
+	}
+}
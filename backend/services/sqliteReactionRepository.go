@@ -0,0 +1,269 @@
+// services/sqliteReactionRepository.go
+//
+// Paquete services: implementación de ReactionRepository sobre SQLite.
+//
+// Convenciones:
+//   - Tabla reactions con clave primaria (post_id, kind, client_id): INSERT OR IGNORE
+//     hace idempotente reaccionar.
+//   - Tabla reaction_counts con una fila por (post, tipo); la marca y el contador se
+//     escriben en la misma transacción (count = count ± 1), así que nunca se desfasan.
+//     El total de un post es la suma de sus filas.
+//   - Ambas tablas se borran en cascada al eliminar el post (foreign_keys activo);
+//     DeleteByPosts existe por simetría con los demás drivers.
+package services
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SQLiteReactionRepository persiste reacciones en las tablas reactions y
+// reaction_counts.
+type SQLiteReactionRepository struct {
+	db      *sql.DB
+	timeout time.Duration
+}
+
+// NewSQLiteReactionRepository crea un repositorio sobre db (ver OpenSQLite).
+// timeout limita cada operación (<=0 usa defaultTimeout).
+func NewSQLiteReactionRepository(db *sql.DB, timeout time.Duration) *SQLiteReactionRepository {
+	return &SQLiteReactionRepository{db: db, timeout: orDefaultTimeout(timeout)}
+}
+
+// Add inserta la marca y, si no existía, suma 1 al contador en la misma transacción.
+//
+// Errores:
+//   - ErrNotFound si el post ya no existe (FOREIGN KEY).
+//   - ErrDB: error del driver.
+func (r *SQLiteReactionRepository) Add(ctx context.Context, postID primitive.ObjectID, kind, clientID string, at time.Time) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, Wrap(err, ErrDB, "begin tx")
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx,
+		`INSERT OR IGNORE INTO reactions (post_id, kind, client_id, created_at) VALUES (?, ?, ?, ?)`,
+		postID.Hex(), kind, clientID, sqliteTime(at))
+	if err != nil {
+		if isSQLiteForeignKeyViolation(err) {
+			return false, Wrap(err, ErrNotFound, "post not found")
+		}
+		return false, Wrap(err, ErrDB, "insert reaction")
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, Wrap(err, ErrDB, "insert reaction")
+	}
+	if n == 0 {
+		return false, nil
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO reaction_counts (post_id, kind, count) VALUES (?, ?, 1)
+		ON CONFLICT (post_id, kind) DO UPDATE SET count = count + 1`,
+		postID.Hex(), kind); err != nil {
+		return false, Wrap(err, ErrDB, "update reaction count")
+	}
+	if err := tx.Commit(); err != nil {
+		return false, Wrap(err, ErrDB, "commit add reaction")
+	}
+	return true, nil
+}
+
+// Remove borra la marca y, si existía, resta 1 al contador en la misma transacción;
+// la fila del contador se borra al llegar a 0.
+//
+// Errores:
+//   - ErrDB: error del driver.
+func (r *SQLiteReactionRepository) Remove(ctx context.Context, postID primitive.ObjectID, kind, clientID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, Wrap(err, ErrDB, "begin tx")
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx,
+		`DELETE FROM reactions WHERE post_id = ? AND kind = ? AND client_id = ?`, postID.Hex(), kind, clientID)
+	if err != nil {
+		return false, Wrap(err, ErrDB, "delete reaction")
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, Wrap(err, ErrDB, "delete reaction")
+	}
+	if n == 0 {
+		return false, nil
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE reaction_counts SET count = count - 1 WHERE post_id = ? AND kind = ?`, postID.Hex(), kind); err != nil {
+		return false, Wrap(err, ErrDB, "update reaction count")
+	}
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM reaction_counts WHERE post_id = ? AND kind = ? AND count <= 0`, postID.Hex(), kind); err != nil {
+		return false, Wrap(err, ErrDB, "delete reaction count")
+	}
+	if err := tx.Commit(); err != nil {
+		return false, Wrap(err, ErrDB, "commit remove reaction")
+	}
+	return true, nil
+}
+
+// Counts lee las filas de reaction_counts de postIDs.
+//
+// Errores:
+//   - ErrDB: error del driver.
+func (r *SQLiteReactionRepository) Counts(ctx context.Context, postIDs []primitive.ObjectID) (map[primitive.ObjectID]map[string]int64, error) {
+	out := map[primitive.ObjectID]map[string]int64{}
+	if len(postIDs) == 0 {
+		return out, nil
+	}
+	args := make([]any, len(postIDs))
+	for i, id := range postIDs {
+		args[i] = id.Hex()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `SELECT post_id, kind, count FROM reaction_counts
+		WHERE count > 0 AND post_id IN (`+sqlitePlaceholders(len(args))+`)`, args...)
+	if err != nil {
+		return nil, Wrap(err, ErrDB, "find reaction counts")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			idHex, kind string
+			n           int64
+		)
+		if err := rows.Scan(&idHex, &kind, &n); err != nil {
+			return nil, Wrap(err, ErrDB, "decode reaction count")
+		}
+		id, err := primitive.ObjectIDFromHex(idHex)
+		if err != nil {
+			return nil, Wrap(err, ErrDB, "decode reaction count")
+		}
+		if out[id] == nil {
+			out[id] = map[string]int64{}
+		}
+		out[id][kind] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, Wrap(err, ErrDB, "cursor error")
+	}
+	return out, nil
+}
+
+// Top elige los posts de la ventana pedida con una consulta ordenada (por la fila de
+// kind o por la suma de filas) y luego lee sus contadores completos con Counts.
+//
+// Errores:
+//   - ErrDB: error del driver.
+func (r *SQLiteReactionRepository) Top(ctx context.Context, kind string, skip, limit int) ([]models.ReactionCount, error) {
+	if limit <= 0 {
+		limit = -1 // sin límite en SQLite
+	}
+	query := `SELECT post_id FROM reaction_counts GROUP BY post_id HAVING SUM(count) > 0
+		ORDER BY SUM(count) DESC, post_id LIMIT ? OFFSET ?`
+	args := []any{limit, skip}
+	if kind != "" {
+		query = `SELECT post_id FROM reaction_counts WHERE kind = ? AND count > 0
+			ORDER BY count DESC, post_id LIMIT ? OFFSET ?`
+		args = append([]any{kind}, args...)
+	}
+
+	ids, err := r.topIDs(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := r.Counts(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]models.ReactionCount, 0, len(ids))
+	for _, id := range ids {
+		rc := models.ReactionCount{PostID: id, Counts: counts[id]}
+		for _, n := range rc.Counts {
+			rc.Total += n
+		}
+		out = append(out, rc)
+	}
+	return out, nil
+}
+
+// topIDs ejecuta la consulta de Top y decodifica los ids de post en orden.
+func (r *SQLiteReactionRepository) topIDs(ctx context.Context, query string, args []any) ([]primitive.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, Wrap(err, ErrDB, "rank reactions")
+	}
+	defer rows.Close()
+
+	ids := []primitive.ObjectID{}
+	for rows.Next() {
+		var idHex string
+		if err := rows.Scan(&idHex); err != nil {
+			return nil, Wrap(err, ErrDB, "decode reaction rank")
+		}
+		id, err := primitive.ObjectIDFromHex(idHex)
+		if err != nil {
+			return nil, Wrap(err, ErrDB, "decode reaction rank")
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, Wrap(err, ErrDB, "cursor error")
+	}
+	return ids, nil
+}
+
+// DeleteByPosts borra las reacciones y los contadores de postIDs en una transacción.
+//
+// Errores:
+//   - ErrDB: error del driver.
+func (r *SQLiteReactionRepository) DeleteByPosts(ctx context.Context, postIDs []primitive.ObjectID) (int64, error) {
+	if len(postIDs) == 0 {
+		return 0, nil
+	}
+	args := make([]any, len(postIDs))
+	for i, id := range postIDs {
+		args[i] = id.Hex()
+	}
+	in := `(` + sqlitePlaceholders(len(args)) + `)`
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, Wrap(err, ErrDB, "begin tx")
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM reactions WHERE post_id IN `+in, args...)
+	if err != nil {
+		return 0, Wrap(err, ErrDB, "delete reactions")
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM reaction_counts WHERE post_id IN `+in, args...); err != nil {
+		return 0, Wrap(err, ErrDB, "delete reaction counts")
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, Wrap(err, ErrDB, "commit delete reactions")
+	}
+	n, _ := res.RowsAffected()
+	return n, nil
+}
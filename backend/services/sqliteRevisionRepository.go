@@ -0,0 +1,222 @@
+// services/sqliteRevisionRepository.go
+//
+// Paquete services: implementación de RevisionRepository sobre SQLite.
+//
+// Convenciones:
+//   - Tabla post_revisions con UNIQUE (post_id, revision); las tags se guardan como
+//     arreglo JSON.
+//   - El historial se borra en cascada al eliminar el post (foreign_keys activo);
+//     DeleteByPosts existe por simetría con los demás drivers.
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// sqliteRevisionColumns es la proyección usada por scanSQLiteRevision.
+const sqliteRevisionColumns = `id, post_id, revision, title, author, content, tags, published, published_at, created_at, created_by, author_id`
+
+// SQLiteRevisionRepository persiste revisiones en la tabla post_revisions.
+type SQLiteRevisionRepository struct {
+	db      *sql.DB
+	timeout time.Duration
+}
+
+// NewSQLiteRevisionRepository crea un repositorio sobre db (ver OpenSQLite).
+// timeout limita cada operación (<=0 usa defaultTimeout).
+func NewSQLiteRevisionRepository(db *sql.DB, timeout time.Duration) *SQLiteRevisionRepository {
+	return &SQLiteRevisionRepository{db: db, timeout: orDefaultTimeout(timeout)}
+}
+
+// Create inserta rev; una revisión ya existente se ignora (INSERT OR IGNORE).
+//
+// Errores:
+//   - ErrDB: error del driver.
+func (r *SQLiteRevisionRepository) Create(ctx context.Context, rev models.PostRevision) error {
+	if rev.ID.IsZero() {
+		rev.ID = primitive.NewObjectID()
+	}
+	tags := rev.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	rawTags, err := json.Marshal(tags)
+	if err != nil {
+		return Wrap(err, ErrDB, "encode revision tags")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	_, err = r.db.ExecContext(ctx,
+		`INSERT OR IGNORE INTO post_revisions (`+sqliteRevisionColumns+`)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		rev.ID.Hex(), rev.PostID.Hex(), rev.Revision, rev.Title, rev.Author, rev.Content, string(rawTags),
+		rev.Published, sqliteTimePtr(rev.PublishedAt), sqliteTime(rev.CreatedAt), rev.CreatedBy,
+		sqliteObjectID(rev.AuthorID))
+	if err != nil {
+		return Wrap(err, ErrDB, "insert revision")
+	}
+	return nil
+}
+
+// List retorna las revisiones de postID ordenadas por revision descendente.
+//
+// Errores:
+//   - ErrDB: error del driver.
+func (r *SQLiteRevisionRepository) List(ctx context.Context, postID primitive.ObjectID) ([]models.PostRevision, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+sqliteRevisionColumns+` FROM post_revisions WHERE post_id = ? ORDER BY revision DESC`, postID.Hex())
+	if err != nil {
+		return nil, Wrap(err, ErrDB, "find revisions")
+	}
+	defer rows.Close()
+
+	out := []models.PostRevision{}
+	for rows.Next() {
+		rev, err := scanSQLiteRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, rev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, Wrap(err, ErrDB, "find revisions")
+	}
+	return out, nil
+}
+
+// Get recupera la revisión indicada.
+//
+// Errores:
+//   - ErrNotFound si no existe; ErrDB si falla el driver.
+func (r *SQLiteRevisionRepository) Get(ctx context.Context, postID primitive.ObjectID, revision int64) (models.PostRevision, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	row := r.db.QueryRowContext(ctx,
+		`SELECT `+sqliteRevisionColumns+` FROM post_revisions WHERE post_id = ? AND revision = ?`, postID.Hex(), revision)
+	rev, err := scanSQLiteRevision(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.PostRevision{}, Wrap(err, ErrNotFound, "revision not found")
+	}
+	return rev, err
+}
+
+// Prune borra las revisiones que exceden keep (por número, de la más nueva a la más
+// vieja) o que son anteriores a before.
+//
+// Errores:
+//   - ErrDB: error del driver.
+func (r *SQLiteRevisionRepository) Prune(ctx context.Context, postID primitive.ObjectID, keep int, before time.Time) (int64, error) {
+	if keep <= 0 && before.IsZero() {
+		return 0, nil
+	}
+	// LIMIT -1 = sin límite; con keep <= 0 el OFFSET cubre todas las filas y no borra por cantidad.
+	offset := int64(keep)
+	if keep <= 0 {
+		offset = 1<<63 - 1
+	}
+	var cutoff any
+	if !before.IsZero() {
+		cutoff = sqliteTime(before)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(ctx,
+		`DELETE FROM post_revisions WHERE post_id = ? AND (
+			revision IN (SELECT revision FROM post_revisions WHERE post_id = ? ORDER BY revision DESC LIMIT -1 OFFSET ?)
+			OR created_at < ?)`,
+		postID.Hex(), postID.Hex(), offset, cutoff)
+	if err != nil {
+		return 0, Wrap(err, ErrDB, "prune revisions")
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, Wrap(err, ErrDB, "prune revisions")
+	}
+	return n, nil
+}
+
+// DeleteByPosts borra las revisiones de postIDs.
+//
+// Errores:
+//   - ErrDB: error del driver.
+func (r *SQLiteRevisionRepository) DeleteByPosts(ctx context.Context, postIDs []primitive.ObjectID) (int64, error) {
+	if len(postIDs) == 0 {
+		return 0, nil
+	}
+	args := make([]any, len(postIDs))
+	for i, id := range postIDs {
+		args[i] = id.Hex()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(ctx,
+		`DELETE FROM post_revisions WHERE post_id IN (`+sqlitePlaceholders(len(args))+`)`, args...)
+	if err != nil {
+		return 0, Wrap(err, ErrDB, "delete revisions")
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, Wrap(err, ErrDB, "delete revisions")
+	}
+	return n, nil
+}
+
+// scanSQLiteRevision decodifica una fila con las columnas de sqliteRevisionColumns.
+// sql.ErrNoRows se retorna sin envolver para que el llamador decida el sentinel.
+func scanSQLiteRevision(s sqliteScanner) (models.PostRevision, error) {
+	var (
+		rev                  models.PostRevision
+		id, postID, tags     string
+		createdAt            string
+		publishedAt, creator sql.NullString
+		authorID             sql.NullString
+	)
+	if err := s.Scan(&id, &postID, &rev.Revision, &rev.Title, &rev.Author, &rev.Content, &tags,
+		&rev.Published, &publishedAt, &createdAt, &creator, &authorID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.PostRevision{}, err
+		}
+		return models.PostRevision{}, Wrap(err, ErrDB, "decode revision")
+	}
+
+	var err error
+	if rev.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+		return models.PostRevision{}, Wrap(err, ErrDB, "decode revision id")
+	}
+	if rev.PostID, err = primitive.ObjectIDFromHex(postID); err != nil {
+		return models.PostRevision{}, Wrap(err, ErrDB, "decode revision post id")
+	}
+	if err := json.Unmarshal([]byte(tags), &rev.Tags); err != nil {
+		return models.PostRevision{}, Wrap(err, ErrDB, "decode revision tags")
+	}
+	if rev.CreatedAt, err = time.Parse(sqliteTimeLayout, createdAt); err != nil {
+		return models.PostRevision{}, Wrap(err, ErrDB, "decode revision createdAt")
+	}
+	if rev.PublishedAt, err = parseSQLiteTime(publishedAt); err != nil {
+		return models.PostRevision{}, Wrap(err, ErrDB, "decode revision publishedAt")
+	}
+	if authorID.Valid {
+		if rev.AuthorID, err = primitive.ObjectIDFromHex(authorID.String); err != nil {
+			return models.PostRevision{}, Wrap(err, ErrDB, "decode revision authorId")
+		}
+	}
+	rev.CreatedBy = creator.String
+	return rev, nil
+}
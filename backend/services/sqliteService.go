@@ -0,0 +1,461 @@
+// services/sqliteService.go
+//
+// Paquete services: apertura de la base SQLite y creación automática del esquema.
+//
+// Convenciones:
+//   - Se usa modernc.org/sqlite (Go puro), compatible con el build CGO_ENABLED=0.
+//   - Al abrir la base se aplica ensureSQLiteSchema, equivalente a ensureIndexes en Mongo.
+//   - FTS5 (tabla posts_fts) reemplaza al índice text_title_content.
+//   - Las etiquetas viven en la tabla de unión post_tags (orden preservado con position).
+//   - Las columnas agregadas después de la primera versión del esquema se declaran en
+//     sqliteColumns: ensureSQLiteSchema las agrega (ALTER TABLE) si la base es anterior.
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	_ "modernc.org/sqlite"
+)
+
+// sqliteSchema define tablas, índices, FTS5 y triggers de sincronización.
+//
+// Notas:
+//   - Las fechas se guardan como TEXT en UTC con ancho fijo (sqliteTimeLayout),
+//     de modo que el orden lexicográfico coincide con el cronológico.
+//   - posts_fts es una tabla FTS5 de contenido externo (content='posts');
+//     los triggers posts_ai/posts_ad/posts_au la mantienen al día.
+//   - remove_diacritics 2 hace la búsqueda insensible a acentos, como $text.
+//   - post_revisions guarda las tags como arreglo JSON: sólo se leen con la revisión completa.
+//   - tag_counts guarda una fila por etiqueta y estado editorial (ver TagCountRepository).
+//   - comments se borra en cascada con su post, como el historial.
+//   - reactions guarda una fila por (post, tipo, cliente) y reaction_counts el agregado
+//     por (post, tipo); ambas se borran en cascada con su post.
+var sqliteSchema = []string{
+	`CREATE TABLE IF NOT EXISTS posts (
+		id           TEXT PRIMARY KEY,
+		title        TEXT NOT NULL,
+		author       TEXT NOT NULL,
+		content      TEXT NOT NULL,
+		published    INTEGER NOT NULL DEFAULT 0,
+		published_at TEXT,
+		created_at   TEXT NOT NULL,
+		updated_at   TEXT
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_published_publishedAt ON posts (published, published_at DESC)`,
+	`CREATE TABLE IF NOT EXISTS post_tags (
+		post_id  TEXT NOT NULL REFERENCES posts (id) ON DELETE CASCADE,
+		position INTEGER NOT NULL,
+		tag      TEXT NOT NULL,
+		PRIMARY KEY (post_id, position)
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_post_tags_tag ON post_tags (tag)`,
+	`CREATE VIRTUAL TABLE IF NOT EXISTS posts_fts USING fts5 (
+		title, content,
+		content = 'posts', content_rowid = 'rowid',
+		tokenize = 'unicode61 remove_diacritics 2'
+	)`,
+	`CREATE TRIGGER IF NOT EXISTS posts_ai AFTER INSERT ON posts BEGIN
+		INSERT INTO posts_fts (rowid, title, content) VALUES (new.rowid, new.title, new.content);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS posts_ad AFTER DELETE ON posts BEGIN
+		INSERT INTO posts_fts (posts_fts, rowid, title, content) VALUES ('delete', old.rowid, old.title, old.content);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS posts_au AFTER UPDATE ON posts BEGIN
+		INSERT INTO posts_fts (posts_fts, rowid, title, content) VALUES ('delete', old.rowid, old.title, old.content);
+		INSERT INTO posts_fts (rowid, title, content) VALUES (new.rowid, new.title, new.content);
+	END`,
+	`CREATE TABLE IF NOT EXISTS post_revisions (
+		id           TEXT PRIMARY KEY,
+		post_id      TEXT NOT NULL REFERENCES posts (id) ON DELETE CASCADE,
+		revision     INTEGER NOT NULL,
+		title        TEXT NOT NULL,
+		author       TEXT NOT NULL,
+		content      TEXT NOT NULL,
+		tags         TEXT NOT NULL DEFAULT '[]',
+		published    INTEGER NOT NULL DEFAULT 0,
+		published_at TEXT,
+		created_at   TEXT NOT NULL,
+		created_by   TEXT,
+		UNIQUE (post_id, revision)
+	)`,
+	`CREATE TABLE IF NOT EXISTS post_old_slugs (
+		slug     TEXT PRIMARY KEY,
+		post_id  TEXT NOT NULL REFERENCES posts (id) ON DELETE CASCADE,
+		position INTEGER NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_post_old_slugs_post ON post_old_slugs (post_id)`,
+	`CREATE TABLE IF NOT EXISTS post_transitions (
+		id          TEXT PRIMARY KEY,
+		post_id     TEXT NOT NULL REFERENCES posts (id) ON DELETE CASCADE,
+		from_status TEXT NOT NULL,
+		to_status   TEXT NOT NULL,
+		version     INTEGER NOT NULL,
+		reason      TEXT,
+		created_at  TEXT NOT NULL,
+		created_by  TEXT
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_post_transitions_post ON post_transitions (post_id, created_at DESC)`,
+	`CREATE TABLE IF NOT EXISTS authors (
+		id         TEXT PRIMARY KEY,
+		name       TEXT NOT NULL,
+		slug       TEXT NOT NULL UNIQUE,
+		bio        TEXT,
+		avatar_url TEXT,
+		created_at TEXT NOT NULL,
+		updated_at TEXT
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_authors_name ON authors (name)`,
+	`CREATE TABLE IF NOT EXISTS tag_counts (
+		tag    TEXT NOT NULL,
+		status TEXT NOT NULL,
+		folded TEXT NOT NULL,
+		count  INTEGER NOT NULL,
+		PRIMARY KEY (tag, status)
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_tag_counts_folded ON tag_counts (folded)`,
+	`CREATE TABLE IF NOT EXISTS comments (
+		id           TEXT PRIMARY KEY,
+		post_id      TEXT NOT NULL REFERENCES posts (id) ON DELETE CASCADE,
+		parent_id    TEXT,
+		root_id      TEXT,
+		author       TEXT NOT NULL,
+		content      TEXT NOT NULL,
+		status       TEXT NOT NULL,
+		created_at   TEXT NOT NULL,
+		moderated_at TEXT,
+		moderated_by TEXT
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_comments_post ON comments (post_id, status, id)`,
+	`CREATE INDEX IF NOT EXISTS idx_comments_root ON comments (root_id, id) WHERE root_id IS NOT NULL`,
+	`CREATE INDEX IF NOT EXISTS idx_comments_status ON comments (status, id)`,
+	`CREATE TABLE IF NOT EXISTS reactions (
+		post_id    TEXT NOT NULL REFERENCES posts (id) ON DELETE CASCADE,
+		kind       TEXT NOT NULL,
+		client_id  TEXT NOT NULL,
+		created_at TEXT NOT NULL,
+		PRIMARY KEY (post_id, kind, client_id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS reaction_counts (
+		post_id TEXT NOT NULL REFERENCES posts (id) ON DELETE CASCADE,
+		kind    TEXT NOT NULL,
+		count   INTEGER NOT NULL,
+		PRIMARY KEY (post_id, kind)
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_reaction_counts_kind ON reaction_counts (kind, count)`,
+}
+
+// sqliteColumn es una columna agregada a una tabla existente.
+type sqliteColumn struct {
+	table, name, definition string
+}
+
+// sqliteColumns se aplican en orden tras sqliteSchema; nunca se quitan ni reordenan.
+var sqliteColumns = []sqliteColumn{
+	{"posts", "version", "INTEGER NOT NULL DEFAULT 1"},
+	{"posts", "deleted_at", "TEXT"},
+	{"posts", "deleted_by", "TEXT"},
+	{"posts", "slug", "TEXT"},
+	{"posts", "publish_at", "TEXT"},
+	{"posts", "unpublish_at", "TEXT"},
+	{"posts", "status", "TEXT"},
+	{"posts", "author_id", "TEXT REFERENCES authors (id)"},
+	{"post_revisions", "author_id", "TEXT"},
+}
+
+// sqliteColumnIndexes se crean después de sqliteColumns (pueden usar columnas agregadas).
+var sqliteColumnIndexes = []string{
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_posts_slug ON posts (slug)`,
+	`CREATE INDEX IF NOT EXISTS idx_posts_publish_at ON posts (publish_at) WHERE publish_at IS NOT NULL`,
+	`CREATE INDEX IF NOT EXISTS idx_posts_unpublish_at ON posts (unpublish_at) WHERE unpublish_at IS NOT NULL`,
+	`CREATE INDEX IF NOT EXISTS idx_posts_status ON posts (status, published_at DESC)`,
+	`CREATE INDEX IF NOT EXISTS idx_posts_author_id ON posts (author_id)`,
+}
+
+// OpenSQLite abre (o crea) la base SQLite en path y asegura el esquema.
+//
+// Parámetros:
+//   - path: ruta del archivo .db (ej. "./blog.db").
+//   - tagAliases: alias → canónica (configuración tags.aliases) que se aplican al
+//     normalizar las etiquetas existentes.
+//
+// Comportamiento:
+//   - Activa foreign_keys (cascada de post_tags), WAL y busy_timeout de 5s.
+//   - Limita el pool a una conexión de escritura para evitar SQLITE_BUSY.
+//   - Llama a ensureSQLiteSchema con timeout de 10s.
+//
+// Errores:
+//   - ErrInvalidInput si path está vacío.
+//   - ErrDB si la apertura, el ping o la creación del esquema fallan.
+func OpenSQLite(path string, tagAliases map[string]string) (*sql.DB, error) {
+	if path == "" {
+		return nil, Wrap(errors.New("SQLITE_PATH vacío"), ErrInvalidInput, "sqlite config")
+	}
+
+	q := url.Values{}
+	q.Add("_pragma", "foreign_keys(1)")
+	q.Add("_pragma", "journal_mode(WAL)")
+	q.Add("_pragma", "busy_timeout(5000)")
+	db, err := sql.Open("sqlite", "file:"+path+"?"+q.Encode())
+	if err != nil {
+		return nil, Wrap(err, ErrDB, "open sqlite")
+	}
+	db.SetMaxOpenConns(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, Wrap(err, ErrDB, "ping sqlite")
+	}
+	if err := ensureSQLiteSchema(ctx, db, newTagNormalizer(tagAliases)); err != nil {
+		db.Close()
+		return nil, Wrap(err, ErrDB, "ensure sqlite schema")
+	}
+	slog.Info("conectado a SQLite", slog.String("path", path))
+	return db, nil
+}
+
+// ensureSQLiteSchema ejecuta sqliteSchema, agrega sqliteColumns faltantes, genera el
+// slug, el estado y el autor de los posts que no los tienen, normaliza sus etiquetas con
+// tags y crea sqliteColumnIndexes, todo dentro de una transacción. Todas las sentencias
+// son idempotentes.
+func ensureSQLiteSchema(ctx context.Context, db *sql.DB, tags tagNormalizer) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range sqliteSchema {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	for _, col := range sqliteColumns {
+		var n int
+		if err := tx.QueryRowContext(ctx,
+			`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, col.table, col.name).Scan(&n); err != nil {
+			return err
+		}
+		if n > 0 {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx,
+			`ALTER TABLE `+col.table+` ADD COLUMN `+col.name+` `+col.definition); err != nil {
+			return err
+		}
+	}
+	if err := backfillSQLiteSlugs(ctx, tx); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, sqliteStatusBackfill); err != nil {
+		return err
+	}
+	if err := backfillSQLiteAuthors(ctx, tx); err != nil {
+		return err
+	}
+	if err := normalizeSQLiteTags(ctx, tx, tags); err != nil {
+		return err
+	}
+	for _, stmt := range sqliteColumnIndexes {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// backfillSQLiteSlugs asigna slug a los posts creados antes de que existiera,
+// del más viejo al más nuevo (el primero conserva el slug sin sufijo).
+func backfillSQLiteSlugs(ctx context.Context, tx *sql.Tx) error {
+	var missing int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM posts WHERE slug IS NULL`).Scan(&missing); err != nil || missing == 0 {
+		return err
+	}
+
+	rows, err := tx.QueryContext(ctx,
+		`SELECT id, title, COALESCE(slug, '') FROM posts ORDER BY created_at, id`)
+	if err != nil {
+		return err
+	}
+	var (
+		items []models.Post
+		ids   []string
+	)
+	for rows.Next() {
+		var p models.Post
+		var id string
+		if err := rows.Scan(&id, &p.Title, &p.Slug); err != nil {
+			rows.Close()
+			return err
+		}
+		items = append(items, p)
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	taken := takenSlugs(items)
+	oldRows, err := tx.QueryContext(ctx, `SELECT slug FROM post_old_slugs`)
+	if err != nil {
+		return err
+	}
+	for oldRows.Next() {
+		var slug string
+		if err := oldRows.Scan(&slug); err != nil {
+			oldRows.Close()
+			return err
+		}
+		taken[slug] = true
+	}
+	oldRows.Close()
+
+	for _, i := range backfillSlugs(items, taken) {
+		if _, err := tx.ExecContext(ctx, `UPDATE posts SET slug = ? WHERE id = ?`, items[i].Slug, ids[i]); err != nil {
+			return err
+		}
+	}
+	slog.Info("slugs generados para posts existentes", slog.Int("posts", missing))
+	return nil
+}
+
+// sqliteStatusBackfill deriva el estado editorial de published en los posts creados
+// antes de que existiera la columna status.
+const sqliteStatusBackfill = `UPDATE posts
+	SET status = CASE WHEN published THEN 'published' ELSE 'draft' END
+	WHERE status IS NULL`
+
+// backfillSQLiteAuthors crea los autores de los posts anteriores a la tabla authors
+// y los referencia (ver backfillAuthors).
+func backfillSQLiteAuthors(ctx context.Context, tx *sql.Tx) error {
+	var missing int
+	if err := tx.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM posts WHERE author_id IS NULL AND trim(author) <> ''`).Scan(&missing); err != nil || missing == 0 {
+		return err
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT `+sqliteAuthorColumns+` FROM authors`)
+	if err != nil {
+		return err
+	}
+	var existing []models.Author
+	for rows.Next() {
+		a, err := scanSQLiteAuthor(rows)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		existing = append(existing, a)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = tx.QueryContext(ctx, `SELECT id, author, COALESCE(author_id, '') FROM posts ORDER BY created_at, id`)
+	if err != nil {
+		return err
+	}
+	var items []models.Post
+	for rows.Next() {
+		var p models.Post
+		var id, authorID string
+		if err := rows.Scan(&id, &p.Author, &authorID); err != nil {
+			rows.Close()
+			return err
+		}
+		p.ID, _ = primitive.ObjectIDFromHex(id)
+		p.AuthorID, _ = primitive.ObjectIDFromHex(authorID)
+		items = append(items, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	created, changed := backfillAuthors(existing, items, time.Now().UTC())
+	for _, a := range created {
+		if err := insertSQLiteAuthor(ctx, tx, a); err != nil {
+			return err
+		}
+	}
+	for _, i := range changed {
+		if _, err := tx.ExecContext(ctx, `UPDATE posts SET author_id = ?, author = ? WHERE id = ?`,
+			items[i].AuthorID.Hex(), items[i].Author, items[i].ID.Hex()); err != nil {
+			return err
+		}
+	}
+	slog.Info("autores asignados a posts existentes", slog.Int("posts", len(changed)), slog.Int("authors", len(created)))
+	return nil
+}
+
+// normalizeSQLiteTags normaliza con normalizer las etiquetas guardadas antes de que se
+// normalizaran al escribir o de que se configuraran sus alias; sólo reescribe los
+// posts cuyas etiquetas cambian.
+func normalizeSQLiteTags(ctx context.Context, tx *sql.Tx, normalizer tagNormalizer) error {
+	ids, lists, err := sqliteTagLists(ctx, tx, `SELECT post_id, tag FROM post_tags ORDER BY post_id, position`)
+	if err != nil {
+		return err
+	}
+	var n int
+	for _, id := range ids {
+		tags := normalizer.tags(lists[id])
+		if equalTags(tags, lists[id]) {
+			continue
+		}
+		if err := replaceSQLiteTags(ctx, tx, id, tags); err != nil {
+			return err
+		}
+		n++
+	}
+	if n > 0 {
+		slog.Info("etiquetas normalizadas en posts existentes", slog.Int("posts", n))
+	}
+	return nil
+}
+
+// sqliteRequiredTables son las tablas que ensureSQLiteSchema debe haber creado.
+var sqliteRequiredTables = []string{"posts", "post_tags", "posts_fts", "post_revisions", "post_old_slugs", "post_transitions", "authors", "tag_counts", "comments", "reactions", "reaction_counts"}
+
+// sqliteHealthChecks arma los chequeos del driver "sqlite".
+//
+// Chequeos:
+//   - sqlite (crítico): ping a la base.
+//   - sqlite_schema (crítico): existencia de sqliteRequiredTables.
+func sqliteHealthChecks(db *sql.DB) []HealthCheck {
+	return []HealthCheck{
+		{
+			Name:     "sqlite",
+			Critical: true,
+			Check:    db.PingContext,
+		},
+		{
+			Name:     "sqlite_schema",
+			Critical: true,
+			Check: func(ctx context.Context) error {
+				for _, table := range sqliteRequiredTables {
+					var n int
+					if err := db.QueryRowContext(ctx,
+						`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, table).Scan(&n); err != nil {
+						return err
+					}
+					if n == 0 {
+						return fmt.Errorf("falta la tabla %s", table)
+					}
+				}
+				return nil
+			},
+		},
+	}
+}
@@ -0,0 +1,142 @@
+// services/sqliteTagCountRepository.go
+//
+// Paquete services: implementación de TagCountRepository sobre SQLite.
+//
+// Convenciones:
+//   - Tabla tag_counts con una fila por (tag, status); folded se repite en cada fila y
+//     tiene índice propio (idx_tag_counts_folded) para la búsqueda por prefijo.
+//   - El prefijo se resuelve como rango [prefix, prefix+U+10FFFF), que usa el índice
+//     (LIKE no lo usaría por ser insensible a mayúsculas).
+package services
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"blog-api/models"
+)
+
+// sqliteTagCountColumns es la proyección usada por scanSQLiteTagCounts.
+const sqliteTagCountColumns = `tag, folded, status, count`
+
+// sqliteMaxRune cierra el rango de Suggest: ningún carácter válido es mayor.
+const sqliteMaxRune = "\U0010FFFF"
+
+// SQLiteTagCountRepository persiste contadores en la tabla tag_counts.
+type SQLiteTagCountRepository struct {
+	db      *sql.DB
+	timeout time.Duration
+}
+
+// NewSQLiteTagCountRepository crea un repositorio sobre db (ver OpenSQLite).
+// timeout limita cada operación (<=0 usa defaultTimeout).
+func NewSQLiteTagCountRepository(db *sql.DB, timeout time.Duration) *SQLiteTagCountRepository {
+	return &SQLiteTagCountRepository{db: db, timeout: orDefaultTimeout(timeout)}
+}
+
+// List retorna todos los contadores, agrupando las filas de cada etiqueta.
+//
+// Errores:
+//   - ErrDB si falla el driver.
+func (r *SQLiteTagCountRepository) List(ctx context.Context) ([]models.TagCount, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `SELECT `+sqliteTagCountColumns+` FROM tag_counts ORDER BY tag`)
+	if err != nil {
+		return nil, Wrap(err, ErrDB, "find tag counts")
+	}
+	return scanSQLiteTagCounts(rows)
+}
+
+// Suggest elige las etiquetas del rango de prefix por uso total y luego lee sus filas.
+//
+// Errores:
+//   - ErrDB si falla el driver.
+func (r *SQLiteTagCountRepository) Suggest(ctx context.Context, prefix string, limit int) ([]models.TagCount, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `SELECT `+sqliteTagCountColumns+` FROM tag_counts WHERE tag IN (
+			SELECT tag FROM tag_counts WHERE folded >= ? AND folded < ?
+			GROUP BY tag ORDER BY SUM(count) DESC, tag LIMIT ?
+		) ORDER BY tag`, prefix, prefix+sqliteMaxRune, limit)
+	if err != nil {
+		return nil, Wrap(err, ErrDB, "suggest tags")
+	}
+	out, err := scanSQLiteTagCounts(rows)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Total > out[j].Total })
+	return out, nil
+}
+
+// Replace borra las filas de tags (todas si tags es nil) e inserta counts en una
+// transacción.
+//
+// Errores:
+//   - ErrDB si falla el driver.
+func (r *SQLiteTagCountRepository) Replace(ctx context.Context, tags []string, counts []models.TagCount) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Wrap(err, ErrDB, "begin tx")
+	}
+	defer tx.Rollback()
+
+	if tags == nil {
+		_, err = tx.ExecContext(ctx, `DELETE FROM tag_counts`)
+	} else if len(tags) > 0 {
+		args := make([]any, 0, len(tags))
+		for _, tag := range tags {
+			args = append(args, tag)
+		}
+		_, err = tx.ExecContext(ctx, `DELETE FROM tag_counts WHERE tag IN (`+sqlitePlaceholders(len(args))+`)`, args...)
+	}
+	if err != nil {
+		return Wrap(err, ErrDB, "delete tag counts")
+	}
+	for _, tc := range counts {
+		for status, n := range tc.Statuses {
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO tag_counts (`+sqliteTagCountColumns+`) VALUES (?, ?, ?, ?)`,
+				tc.Tag, tc.Folded, status, n); err != nil {
+				return Wrap(err, ErrDB, "insert tag count")
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return Wrap(err, ErrDB, "commit replace tag counts")
+	}
+	return nil
+}
+
+// scanSQLiteTagCounts agrupa filas (tag, folded, status, count) ordenadas por tag en un
+// TagCount por etiqueta y cierra rows.
+func scanSQLiteTagCounts(rows *sql.Rows) ([]models.TagCount, error) {
+	defer rows.Close()
+
+	out := []models.TagCount{}
+	for rows.Next() {
+		var tag, folded, status string
+		var n int64
+		if err := rows.Scan(&tag, &folded, &status, &n); err != nil {
+			return nil, Wrap(err, ErrDB, "decode tag count")
+		}
+		if len(out) == 0 || out[len(out)-1].Tag != tag {
+			out = append(out, models.TagCount{Tag: tag, Folded: folded, Statuses: map[string]int64{}})
+		}
+		last := &out[len(out)-1]
+		last.Statuses[status] = n
+		last.Total += n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, Wrap(err, ErrDB, "cursor error")
+	}
+	return out, nil
+}
@@ -0,0 +1,129 @@
+// services/sqliteTransitionRepository.go
+//
+// Paquete services: implementación de TransitionRepository sobre SQLite.
+//
+// Convenciones:
+//   - Tabla post_transitions con índice (post_id, created_at).
+//   - El historial se borra en cascada al eliminar el post (foreign_keys activo);
+//     DeleteByPosts existe por simetría con los demás drivers.
+package services
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// sqliteTransitionColumns es la proyección usada por List.
+const sqliteTransitionColumns = `id, post_id, from_status, to_status, version, reason, created_at, created_by`
+
+// SQLiteTransitionRepository persiste transiciones en la tabla post_transitions.
+type SQLiteTransitionRepository struct {
+	db      *sql.DB
+	timeout time.Duration
+}
+
+// NewSQLiteTransitionRepository crea un repositorio sobre db (ver OpenSQLite).
+// timeout limita cada operación (<=0 usa defaultTimeout).
+func NewSQLiteTransitionRepository(db *sql.DB, timeout time.Duration) *SQLiteTransitionRepository {
+	return &SQLiteTransitionRepository{db: db, timeout: orDefaultTimeout(timeout)}
+}
+
+// Create inserta t.
+//
+// Errores:
+//   - ErrDB: error del driver.
+func (r *SQLiteTransitionRepository) Create(ctx context.Context, t models.PostTransition) error {
+	if t.ID.IsZero() {
+		t.ID = primitive.NewObjectID()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO post_transitions (`+sqliteTransitionColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		t.ID.Hex(), t.PostID.Hex(), t.From, t.To, t.Version, sqliteNullString(t.Reason),
+		sqliteTime(t.CreatedAt), sqliteNullString(t.CreatedBy))
+	if err != nil {
+		return Wrap(err, ErrDB, "insert transition")
+	}
+	return nil
+}
+
+// List retorna las transiciones de postID, de la más nueva a la más vieja (a igual
+// fecha, por id, que crece con el tiempo de inserción).
+//
+// Errores:
+//   - ErrDB: error del driver.
+func (r *SQLiteTransitionRepository) List(ctx context.Context, postID primitive.ObjectID) ([]models.PostTransition, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+sqliteTransitionColumns+` FROM post_transitions WHERE post_id = ?
+		 ORDER BY created_at DESC, id DESC`, postID.Hex())
+	if err != nil {
+		return nil, Wrap(err, ErrDB, "find transitions")
+	}
+	defer rows.Close()
+
+	out := []models.PostTransition{}
+	for rows.Next() {
+		var (
+			t                 models.PostTransition
+			id, post, created string
+			reason, creator   sql.NullString
+		)
+		if err := rows.Scan(&id, &post, &t.From, &t.To, &t.Version, &reason, &created, &creator); err != nil {
+			return nil, Wrap(err, ErrDB, "decode transition")
+		}
+		if t.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+			return nil, Wrap(err, ErrDB, "decode transition id")
+		}
+		if t.PostID, err = primitive.ObjectIDFromHex(post); err != nil {
+			return nil, Wrap(err, ErrDB, "decode transition post id")
+		}
+		if t.CreatedAt, err = time.Parse(sqliteTimeLayout, created); err != nil {
+			return nil, Wrap(err, ErrDB, "decode transition createdAt")
+		}
+		t.Reason = reason.String
+		t.CreatedBy = creator.String
+		out = append(out, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, Wrap(err, ErrDB, "find transitions")
+	}
+	return out, nil
+}
+
+// DeleteByPosts borra las transiciones de postIDs.
+//
+// Errores:
+//   - ErrDB: error del driver.
+func (r *SQLiteTransitionRepository) DeleteByPosts(ctx context.Context, postIDs []primitive.ObjectID) (int64, error) {
+	if len(postIDs) == 0 {
+		return 0, nil
+	}
+	args := make([]any, len(postIDs))
+	for i, id := range postIDs {
+		args[i] = id.Hex()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(ctx,
+		`DELETE FROM post_transitions WHERE post_id IN (`+sqlitePlaceholders(len(args))+`)`, args...)
+	if err != nil {
+		return 0, Wrap(err, ErrDB, "delete transitions")
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, Wrap(err, ErrDB, "delete transitions")
+	}
+	return n, nil
+}
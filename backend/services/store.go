@@ -0,0 +1,228 @@
+// services/store.go
+//
+// Paquete services: selección del driver de almacenamiento.
+//
+// Convenciones:
+//   - OpenStore es el único punto que conoce los drivers concretos ("mongo", "memory", "sqlite").
+//   - main obtiene de Store los repositorios y los inyecta en los servicios.
+//   - Store.Close libera los recursos del driver (desconexión, volcado de snapshot).
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Drivers de almacenamiento soportados.
+const (
+	DriverMongo  = "mongo"
+	DriverMemory = "memory"
+	DriverSQLite = "sqlite"
+)
+
+// StoreOptions agrupa la configuración necesaria para abrir un Store.
+//
+// Campos:
+//   - Driver: "mongo" (default si está vacío), "memory" o "sqlite".
+//   - MongoURI / MongoDB: conexión usada por el driver "mongo".
+//   - SnapshotPath: archivo JSON que el driver "memory" carga al abrir y
+//     vuelca al cerrar (opcional; vacío = sin persistencia).
+//   - SQLitePath: archivo de base de datos usado por el driver "sqlite".
+//   - Timeout: tiempo máximo por operación de los repositorios (<=0 usa defaultTimeout).
+//   - Observe: recibe la duración y el resultado de cada operación del driver "mongo"
+//     (métricas); nil = sin observación.
+//   - TagAliases: alias → canónica (configuración tags.aliases) que se aplican al
+//     normalizar las etiquetas de los posts existentes: migración 10 en Mongo, al
+//     abrir con "memory" y "sqlite".
+//   - AutoMigrate: si es true, el driver "mongo" aplica las migraciones pendientes
+//     (MongoMigrations) al abrir. El CLI `migrate` lo desactiva para controlarlas a mano.
+type StoreOptions struct {
+	Driver       string
+	MongoURI     string
+	MongoDB      string
+	SnapshotPath string
+	SQLitePath   string
+	Timeout      time.Duration
+	Observe      OpObserver
+	TagAliases   map[string]string
+	AutoMigrate  bool
+}
+
+// OpObserver recibe cada operación contra el almacenamiento.
+//
+// Parámetros:
+//   - op: nombre de la operación del driver ("insert", "find", "findOneAndUpdate",
+//     "delete", "aggregate", "count").
+//   - elapsed: duración de la operación.
+//   - err: error ya envuelto con sentinel (nil si tuvo éxito); ver SentinelName.
+type OpObserver func(op string, elapsed time.Duration, err error)
+
+// track informa a o la operación op iniciada en start; se usa con defer y un
+// error de retorno con nombre. Es seguro llamarlo con o == nil.
+func (o OpObserver) track(op string, start time.Time, err *error) {
+	if o != nil {
+		o(op, time.Since(start), *err)
+	}
+}
+
+// Store expone los repositorios del driver activo.
+//
+// Migrator sólo está disponible con el driver "mongo"; los drivers "memory" y
+// "sqlite" crean su esquema al abrir y no tienen migraciones versionadas.
+// TagCounts se reconstruye al abrir con "memory" y "sqlite" (migración 11 en Mongo).
+// HealthChecks son los chequeos de dependencias usados por /readyz.
+type Store struct {
+	Driver       string
+	Posts        PostRepository
+	Revisions    RevisionRepository
+	Transitions  TransitionRepository
+	Authors      AuthorRepository
+	TagCounts    TagCountRepository
+	Comments     CommentRepository
+	Reactions    ReactionRepository
+	Migrator     *Migrator
+	HealthChecks []HealthCheck
+
+	newLease func(name string, ttl time.Duration) Lease
+	close    func(ctx context.Context) error
+}
+
+// OpenStore inicializa el driver indicado en opts.
+//
+// Errores:
+//   - ErrInvalidInput si el driver no es soportado o falta configuración.
+//   - ErrDB si la conexión o la carga del snapshot fallan.
+func OpenStore(opts StoreOptions) (*Store, error) {
+	switch opts.Driver {
+	case "", DriverMongo:
+		db, err := ConnectMongo(opts.MongoURI, opts.MongoDB)
+		if err != nil {
+			return nil, err
+		}
+		closeMongo := func(ctx context.Context) error {
+			return Wrap(db.Client().Disconnect(ctx), ErrDB, "disconnect mongo")
+		}
+
+		migrator, err := NewMigrator(db, MongoMigrations(opts.TagAliases))
+		if err != nil {
+			_ = closeMongo(context.Background())
+			return nil, err
+		}
+		if opts.AutoMigrate {
+			applied, err := migrator.Up(context.Background(), 0)
+			if err != nil {
+				_ = closeMongo(context.Background())
+				return nil, err
+			}
+			if len(applied) > 0 {
+				slog.Info("migraciones aplicadas", slog.Any("versions", applied))
+			}
+		}
+
+		return &Store{
+			Driver:       DriverMongo,
+			Posts:        NewMongoPostRepository(db, opts.Timeout, opts.Observe),
+			Revisions:    NewMongoRevisionRepository(db, opts.Timeout, opts.Observe),
+			Transitions:  NewMongoTransitionRepository(db, opts.Timeout, opts.Observe),
+			Authors:      NewMongoAuthorRepository(db, opts.Timeout, opts.Observe),
+			TagCounts:    NewMongoTagCountRepository(db, opts.Timeout, opts.Observe),
+			Comments:     NewMongoCommentRepository(db, opts.Timeout, opts.Observe),
+			Reactions:    NewMongoReactionRepository(db, opts.Timeout, opts.Observe),
+			Migrator:     migrator,
+			HealthChecks: mongoHealthChecks(db, migrator),
+			newLease: func(name string, ttl time.Duration) Lease {
+				return newMongoLease(db, name, ttl)
+			},
+			close: closeMongo,
+		}, nil
+
+	case DriverMemory:
+		posts := NewMemoryPostRepository()
+		revisions := NewMemoryRevisionRepository()
+		transitions := NewMemoryTransitionRepository()
+		authors := NewMemoryAuthorRepository()
+		comments := NewMemoryCommentRepository()
+		reactions := NewMemoryReactionRepository()
+		if opts.SnapshotPath != "" {
+			if err := loadMemorySnapshot(opts.SnapshotPath, posts, revisions, transitions, authors, comments, reactions, newTagNormalizer(opts.TagAliases)); err != nil {
+				return nil, err
+			}
+			slog.Info("snapshot en memoria cargado", slog.String("path", opts.SnapshotPath))
+		}
+		tagCounts := NewMemoryTagCountRepository()
+		if err := RebuildTagCounts(context.Background(), posts, tagCounts); err != nil {
+			return nil, err
+		}
+		return &Store{
+			Driver:      DriverMemory,
+			Posts:       posts,
+			Revisions:   revisions,
+			Transitions: transitions,
+			Authors:     authors,
+			TagCounts:   tagCounts,
+			Comments:    comments,
+			Reactions:   reactions,
+			// Sin dependencias externas: siempre listo.
+			HealthChecks: []HealthCheck{{
+				Name:     "memory",
+				Critical: true,
+				Check:    func(context.Context) error { return nil },
+			}},
+			close: func(context.Context) error {
+				if opts.SnapshotPath == "" {
+					return nil
+				}
+				return saveMemorySnapshot(opts.SnapshotPath, posts, revisions, transitions, authors, comments, reactions)
+			},
+		}, nil
+
+	case DriverSQLite:
+		db, err := OpenSQLite(opts.SQLitePath, opts.TagAliases)
+		if err != nil {
+			return nil, err
+		}
+		posts := NewSQLitePostRepository(db, opts.Timeout)
+		tagCounts := NewSQLiteTagCountRepository(db, opts.Timeout)
+		if err := RebuildTagCounts(context.Background(), posts, tagCounts); err != nil {
+			_ = db.Close()
+			return nil, err
+		}
+		return &Store{
+			Driver:       DriverSQLite,
+			Posts:        posts,
+			Revisions:    NewSQLiteRevisionRepository(db, opts.Timeout),
+			Transitions:  NewSQLiteTransitionRepository(db, opts.Timeout),
+			Authors:      NewSQLiteAuthorRepository(db, opts.Timeout),
+			TagCounts:    tagCounts,
+			Comments:     NewSQLiteCommentRepository(db, opts.Timeout),
+			Reactions:    NewSQLiteReactionRepository(db, opts.Timeout),
+			HealthChecks: sqliteHealthChecks(db),
+			close: func(context.Context) error {
+				return Wrap(db.Close(), ErrDB, "close sqlite")
+			},
+		}, nil
+
+	default:
+		return nil, Wrap(fmt.Errorf("driver %q no soportado", opts.Driver), ErrInvalidInput, "storage driver")
+	}
+}
+
+// Lease retorna el lease name con vigencia ttl para coordinar un trabajo de fondo
+// entre instancias. Los drivers "memory" y "sqlite" sirven a un único proceso, por lo
+// que su lease siempre se concede (localLease).
+func (s *Store) Lease(name string, ttl time.Duration) Lease {
+	if s.newLease == nil {
+		return localLease{}
+	}
+	return s.newLease(name, ttl)
+}
+
+// Close libera los recursos del driver. Es seguro llamarlo sobre un Store nil.
+func (s *Store) Close(ctx context.Context) error {
+	if s == nil || s.close == nil {
+		return nil
+	}
+	return s.close(ctx)
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"blog-api/models"
+)
+
+func TestOpenStoreAppliesTagAliases(t *testing.T) {
+	for _, driver := range []string{DriverMemory, DriverSQLite} {
+		t.Run(driver, func(t *testing.T) {
+			ctx := context.Background()
+			opts := StoreOptions{Driver: driver}
+			if driver == DriverSQLite {
+				opts.SQLitePath = filepath.Join(t.TempDir(), "blog.db")
+			} else {
+				opts.SnapshotPath = filepath.Join(t.TempDir(), "snapshot.json")
+			}
+
+			store, err := OpenStore(opts)
+			if err != nil {
+				t.Fatalf("open: %v", err)
+			}
+			now := time.Now().UTC()
+			author := models.Author{Name: "Ana García", Slug: "ana-garcia", CreatedAt: now}
+			if author.ID, err = store.Authors.Create(ctx, author); err != nil {
+				t.Fatalf("create author: %v", err)
+			}
+			id, err := store.Posts.Create(ctx, models.Post{
+				Title: "Primer post", Slug: "primer-post", Author: author.Name, AuthorID: author.ID, Content: "contenido",
+				Tags: []string{"golang", "go", "mongo"}, Status: models.StatusDraft, CreatedAt: now, Version: 1,
+			})
+			if err != nil {
+				t.Fatalf("create post: %v", err)
+			}
+			if err := store.Close(ctx); err != nil {
+				t.Fatalf("close: %v", err)
+			}
+
+			// Al reabrir con alias, las etiquetas guardadas antes se normalizan con ellos.
+			opts.TagAliases = map[string]string{"Golang": "go"}
+			store, err = OpenStore(opts)
+			if err != nil {
+				t.Fatalf("reopen: %v", err)
+			}
+			defer store.Close(ctx)
+
+			got, err := store.Posts.Get(ctx, id)
+			if err != nil {
+				t.Fatalf("get: %v", err)
+			}
+			if !equalStrings(got.Tags, []string{"go", "mongo"}) {
+				t.Fatalf("tags tras reabrir: %v, want [go mongo]", got.Tags)
+			}
+			counts, err := store.TagCounts.List(ctx)
+			if err != nil {
+				t.Fatalf("tag counts: %v", err)
+			}
+			for _, c := range counts {
+				if c.Tag == "golang" {
+					t.Fatalf("tag counts con el alias: %+v", counts)
+				}
+			}
+		})
+	}
+}
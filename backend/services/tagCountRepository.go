@@ -0,0 +1,36 @@
+// services/tagCountRepository.go
+//
+// Paquete services: contrato de persistencia de los contadores de etiquetas.
+//
+// Convenciones:
+//   - Igual que PostRepository: los servicios dependen de la interfaz y las
+//     implementaciones envuelven sus errores con sentinelas.
+//   - El repositorio sólo guarda y consulta: el cálculo (PostRepository.CountTags), la
+//     forma sin acentos y el total los completa el servicio (ver tagCounts.go).
+package services
+
+import (
+	"context"
+
+	"blog-api/models"
+)
+
+// TagCountRepository define las operaciones sobre los contadores de etiquetas.
+//
+// Implementaciones:
+//   - MongoTagCountRepository: colección "tag_counts" (índice idx_folded).
+//   - MemoryTagCountRepository: mapa en memoria.
+//   - SQLiteTagCountRepository: tabla tag_counts (una fila por etiqueta y estado).
+//
+// Errores esperados:
+//   - ErrDB ante fallas del almacenamiento.
+type TagCountRepository interface {
+	// List retorna los contadores de todas las etiquetas, en cualquier orden.
+	List(ctx context.Context) ([]models.TagCount, error)
+	// Suggest retorna hasta limit contadores cuyo Folded empieza con prefix, por Total
+	// descendente y luego por Tag.
+	Suggest(ctx context.Context, prefix string, limit int) ([]models.TagCount, error)
+	// Replace reemplaza los contadores de tags por counts; las etiquetas de tags sin
+	// contador en counts se borran. tags nil reemplaza todos los contadores.
+	Replace(ctx context.Context, tags []string, counts []models.TagCount) error
+}
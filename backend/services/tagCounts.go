@@ -0,0 +1,129 @@
+// services/tagCounts.go
+//
+// Paquete services: mantenimiento de los contadores de etiquetas (TagCountRepository).
+//
+// Convenciones:
+//   - Después de cada escritura que cambia las etiquetas, el estado editorial o la
+//     papelera de un post, se recalculan sólo las etiquetas afectadas (antes y después)
+//     con PostRepository.CountTags, que usa el índice de tags; las lecturas de GET
+//     /api/tags no recorren los posts.
+//   - Como las revisiones, el recálculo nunca hace fallar la escritura: los errores se
+//     registran en el log. Un contador desfasado (error o escrituras concurrentes) se
+//     corrige en la siguiente escritura de esa etiqueta o al reconstruirlos (migración
+//     11 en Mongo; al abrir la base con memory y sqlite).
+//   - Folded es la etiqueta sin acentos (NFD sin marcas, más slugLetters): "Ñandú" y
+//     "nandu" comparten prefijo.
+package services
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"unicode"
+
+	"blog-api/models"
+	"golang.org/x/text/unicode/norm"
+)
+
+// foldTag retorna tag sin acentos ni marcas diacríticas, conservando el resto de
+// caracteres (espacios, guiones, dígitos).
+func foldTag(tag string) string {
+	var sb strings.Builder
+	for _, r := range norm.NFD.String(tag) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if out, ok := slugLetters[r]; ok {
+			sb.WriteString(out)
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// tagCountSet acumula posts por etiqueta y estado en el orden en que aparecen las
+// etiquetas; lo usan las implementaciones de PostRepository.CountTags.
+type tagCountSet struct {
+	order []string
+	byTag map[string]map[string]int64
+}
+
+// add suma n posts con estado status a tag.
+func (s *tagCountSet) add(tag, status string, n int64) {
+	if s.byTag == nil {
+		s.byTag = map[string]map[string]int64{}
+	}
+	statuses, ok := s.byTag[tag]
+	if !ok {
+		statuses = map[string]int64{}
+		s.byTag[tag] = statuses
+		s.order = append(s.order, tag)
+	}
+	statuses[status] += n
+}
+
+// counts retorna los contadores acumulados (sólo Tag y Statuses).
+func (s *tagCountSet) counts() []models.TagCount {
+	out := make([]models.TagCount, 0, len(s.order))
+	for _, tag := range s.order {
+		out = append(out, models.TagCount{Tag: tag, Statuses: s.byTag[tag]})
+	}
+	return out
+}
+
+// recountTags recalcula los contadores de tags (todos si tags es nil) a partir de
+// posts y los guarda en counts, completando Folded y Total.
+func recountTags(ctx context.Context, posts PostRepository, counts TagCountRepository, tags []string) error {
+	if tags != nil && len(tags) == 0 {
+		return nil
+	}
+	items, err := posts.CountTags(ctx, tags)
+	if err != nil {
+		return err
+	}
+	for i := range items {
+		items[i].Folded = foldTag(items[i].Tag)
+		items[i].Total = 0
+		for _, n := range items[i].Statuses {
+			items[i].Total += n
+		}
+	}
+	return counts.Replace(ctx, tags, items)
+}
+
+// RebuildTagCounts recalcula todos los contadores de etiquetas desde los posts; lo
+// usan la migración 11 y la apertura de los drivers memory y sqlite.
+//
+// Errores:
+//   - ErrDB si falla el almacenamiento.
+func RebuildTagCounts(ctx context.Context, posts PostRepository, counts TagCountRepository) error {
+	return recountTags(ctx, posts, counts, nil)
+}
+
+// refreshTagCounts recalcula las etiquetas de before y after si la escritura cambió
+// algo que cuentan (etiquetas, estado o papelera); los errores sólo se registran.
+func (s *PostService) refreshTagCounts(ctx context.Context, before, after models.Post) {
+	if equalTags(before.Tags, after.Tags) && countedStatus(before) == countedStatus(after) {
+		return
+	}
+	tags := append([]string{}, before.Tags...)
+	for _, t := range after.Tags {
+		if !containsString(tags, t) {
+			tags = append(tags, t)
+		}
+	}
+	if err := recountTags(ctx, s.repo, s.tagCounts, tags); err != nil {
+		slog.WarnContext(ctx, "no se pudieron actualizar los contadores de etiquetas",
+			slog.String("postId", after.ID.Hex()), slog.String("error", err.Error()))
+	}
+}
+
+// countedStatus es el estado con el que post cuenta en los contadores ("" si está en
+// la papelera y no cuenta).
+func countedStatus(post models.Post) string {
+	if post.DeletedAt != nil {
+		return ""
+	}
+	return postStatus(post)
+}
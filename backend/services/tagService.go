@@ -0,0 +1,220 @@
+// services/tagService.go
+//
+// Paquete services: administración de etiquetas de posts.
+//
+// Convenciones:
+//   - Mismas reglas que PostService: repositorios por constructor, errores envueltos
+//     con sentinelas, ErrDB registrados con logDBError y un span por método.
+//   - Las etiquetas de entrada se normalizan como al escribir un post (ver postTags.go);
+//     el destino además pasa por los alias, los orígenes no: así se pueden fusionar las
+//     etiquetas alias que quedaron en posts anteriores a la configuración.
+//   - Renombrar y fusionar reescriben todos los posts afectados, incluidos los de la
+//     papelera, sin registrar revisiones (la edición no es de contenido).
+//   - El catálogo y las sugerencias leen los contadores de TagCountRepository (ver
+//     tagCounts.go), nunca los posts.
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+	"time"
+
+	"blog-api/models"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var (
+	// errEmptyTag es la causa de una etiqueta que queda vacía al normalizarla.
+	errEmptyTag = errors.New("empty tag")
+	// errSameTag es la causa al renombrar o fusionar una etiqueta consigo misma.
+	errSameTag = errors.New("source and target tags are the same")
+)
+
+// Límites de SuggestTags.
+const (
+	defaultSuggestLimit = 10
+	maxSuggestLimit     = 50
+)
+
+// TagService implementa los casos de uso de etiquetas sobre un PostRepository y sus
+// contadores en un TagCountRepository.
+type TagService struct {
+	posts  PostRepository
+	counts TagCountRepository
+	tags   tagNormalizer
+}
+
+// NewTagService crea el servicio de etiquetas sobre posts y counts; aliases es el mismo
+// mapa alias → canónica que recibe PostService (PostServiceOptions.TagAliases).
+func NewTagService(posts PostRepository, counts TagCountRepository, aliases map[string]string) *TagService {
+	return &TagService{posts: posts, counts: counts, tags: newTagNormalizer(aliases)}
+}
+
+// ListTags retorna todas las etiquetas con la cantidad de posts (fuera de la papelera)
+// que las usan, ordenadas por cantidad descendente y luego por etiqueta.
+//
+// Parámetros:
+//   - onlyPublished: si no es nil, cuenta sólo posts publicados (true) o no publicados
+//     (false).
+//   - status: si no está vacío, cuenta sólo posts en ese estado; se combina con
+//     onlyPublished.
+//
+// Las etiquetas sin posts que cumplan el filtro no se incluyen.
+//
+// Errores:
+//   - ErrInvalidInput: status no es un estado del flujo.
+//   - ErrDB: error del driver.
+func (s *TagService) ListTags(ctx context.Context, onlyPublished *bool, status string) (_ []TagMetric, err error) {
+	ctx, span := startSpan(ctx, "TagService.ListTags")
+	defer endSpan(span, &err)
+
+	if status != "" && !ValidStatus(status) {
+		return nil, Wrap(errUnknownStatus, ErrInvalidInput, "status")
+	}
+	counts, err := s.counts.List(ctx)
+	if err != nil {
+		return nil, logDBError(ctx, "list tag counts", err)
+	}
+
+	out := []TagMetric{}
+	for _, tc := range counts {
+		var n int64
+		for st, c := range tc.Statuses {
+			if status != "" && st != status {
+				continue
+			}
+			if onlyPublished != nil && (st == models.StatusPublished) != *onlyPublished {
+				continue
+			}
+			n += c
+		}
+		if n > 0 {
+			out = append(out, TagMetric{Tag: tc.Tag, Count: n})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Tag < out[j].Tag
+	})
+	span.SetAttributes(attribute.Int("tag.count", len(out)))
+	return out, nil
+}
+
+// SuggestTags retorna hasta limit etiquetas que empiezan con prefix, ordenadas por
+// cantidad total de posts (cualquier estado) descendente y luego por etiqueta.
+//
+// Reglas:
+//   - prefix se normaliza como una etiqueta (sin alias) y la comparación ignora
+//     acentos: "progra" y "prógra" sugieren "programación".
+//   - limit <= 0 usa 10; mayor a 50 se trunca a 50.
+//
+// Errores:
+//   - ErrInvalidInput: prefix vacío tras normalizar.
+//   - ErrDB: error del driver.
+func (s *TagService) SuggestTags(ctx context.Context, prefix string, limit int) (_ []TagMetric, err error) {
+	ctx, span := startSpan(ctx, "TagService.SuggestTags", attribute.String("tag.prefix", prefix))
+	defer endSpan(span, &err)
+
+	folded := foldTag(normalizeTag(prefix))
+	if folded == "" {
+		return nil, Wrap(errEmptyTag, ErrInvalidInput, "prefix")
+	}
+	if limit <= 0 {
+		limit = defaultSuggestLimit
+	}
+	if limit > maxSuggestLimit {
+		limit = maxSuggestLimit
+	}
+	counts, err := s.counts.Suggest(ctx, folded, limit)
+	if err != nil {
+		return nil, logDBError(ctx, "suggest tags", err)
+	}
+	out := make([]TagMetric, 0, len(counts))
+	for _, tc := range counts {
+		out = append(out, TagMetric{Tag: tc.Tag, Count: tc.Total})
+	}
+	return out, nil
+}
+
+// TagRenameResult resume un renombrado o una fusión de etiquetas.
+//
+// Campos:
+//   - Target: etiqueta resultante (normalizada, alias aplicado).
+//   - Sources: etiquetas reemplazadas (normalizadas).
+//   - Posts: cantidad de posts reescritos.
+type TagRenameResult struct {
+	Target  string   `json:"target"`
+	Sources []string `json:"sources"`
+	Posts   int64    `json:"posts"`
+}
+
+// RenameTag reemplaza la etiqueta from por to en todos los posts.
+//
+// Reglas:
+//   - Si to ya existe en un post, from se quita sin duplicarla (equivale a fusionar).
+//   - updatedAt y version cambian sólo en los posts reescritos.
+//
+// Errores:
+//   - ErrInvalidInput: from o to vacías tras normalizar, o iguales.
+//   - ErrDB: error del driver.
+func (s *TagService) RenameTag(ctx context.Context, from, to string) (_ TagRenameResult, err error) {
+	ctx, span := startSpan(ctx, "TagService.RenameTag", attribute.String("tag.from", from))
+	defer endSpan(span, &err)
+
+	res, err := s.replace(ctx, []string{from}, to)
+	span.SetAttributes(attribute.Int64("tag.posts", res.Posts))
+	return res, err
+}
+
+// MergeTags reemplaza cada etiqueta de sources por target en todos los posts; target
+// queda en la posición de la primera etiqueta reemplazada (o de la que ya tenía).
+//
+// Reglas:
+//   - Las etiquetas de sources iguales a target se ignoran.
+//
+// Errores:
+//   - ErrInvalidInput: target o alguna de sources vacías tras normalizar, o ninguna
+//     etiqueta de sources distinta de target.
+//   - ErrDB: error del driver.
+func (s *TagService) MergeTags(ctx context.Context, sources []string, target string) (_ TagRenameResult, err error) {
+	ctx, span := startSpan(ctx, "TagService.MergeTags", attribute.Int("tag.sources", len(sources)))
+	defer endSpan(span, &err)
+
+	res, err := s.replace(ctx, sources, target)
+	span.SetAttributes(attribute.Int64("tag.posts", res.Posts))
+	return res, err
+}
+
+// replace normaliza from y to y delega en PostRepository.ReplaceTags.
+func (s *TagService) replace(ctx context.Context, from []string, to string) (TagRenameResult, error) {
+	target := s.tags.tag(to)
+	if target == "" {
+		return TagRenameResult{}, Wrap(errEmptyTag, ErrInvalidInput, "target tag")
+	}
+	sources := make([]string, 0, len(from))
+	for _, t := range from {
+		switch t = normalizeTag(t); {
+		case t == "":
+			return TagRenameResult{}, Wrap(errEmptyTag, ErrInvalidInput, "source tag")
+		case t != target && !containsString(sources, t):
+			sources = append(sources, t)
+		}
+	}
+	if len(sources) == 0 {
+		return TagRenameResult{}, Wrap(errSameTag, ErrInvalidInput, "source tags")
+	}
+
+	n, err := s.posts.ReplaceTags(ctx, sources, target, time.Now().UTC())
+	if err != nil {
+		return TagRenameResult{}, logDBError(ctx, "replace tags", err)
+	}
+	if err := recountTags(ctx, s.posts, s.counts, append([]string{target}, sources...)); err != nil {
+		slog.WarnContext(ctx, "no se pudieron actualizar los contadores de etiquetas",
+			slog.String("tag", target), slog.String("error", err.Error()))
+	}
+	return TagRenameResult{Target: target, Sources: sources, Posts: n}, nil
+}
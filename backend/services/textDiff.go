@@ -0,0 +1,381 @@
+// services/textDiff.go
+//
+// Paquete services: diff de secuencias de texto (algoritmo de Myers) y su formato unificado.
+//
+// Convenciones:
+//   - El diff opera sobre tokens (líneas o palabras); diffTokens no conoce la entidad Post.
+//   - La separación en tokens recorre runas, nunca bytes: un token siempre es UTF-8 válido
+//     y una letra acentuada ("ó", "ñ") forma parte de su palabra.
+//   - Los textos se comparan en NFC: "ó" precompuesta y "o" + acento combinante son iguales.
+//   - Las operaciones se nombran "equal", "delete" e "insert" en JSON y " ", "-", "+"
+//     en el formato unificado.
+//   - En los hunks por líneas, cada línea borrada que se reemplaza por otra lleva además
+//     el diff por palabras contra su reemplazo (addWordDiffs), para señalar qué cambió
+//     dentro de la línea; el formato unificado sigue siendo sólo por líneas.
+package services
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Operaciones de un diff.
+const (
+	DiffEqual  = "equal"
+	DiffDelete = "delete"
+	DiffInsert = "insert"
+)
+
+// diffContextLines es la cantidad de líneas sin cambios alrededor de cada hunk.
+const diffContextLines = 3
+
+// maxDiffCost limita las ediciones que busca diffTokens; por encima se reporta el
+// reemplazo completo para acotar tiempo y memoria (O(D²)).
+const maxDiffCost = 2000
+
+// DiffSegment es un tramo de texto con su operación.
+//
+// Campos:
+//   - Op: "equal" | "delete" | "insert".
+//   - Text: contenido del tramo (una línea sin "\n", o una o más palabras).
+//   - Words: en una línea borrada o insertada que reemplaza a otra, la línea por
+//     palabras: tramos equal y delete (borrada) o equal e insert (insertada) que
+//     concatenados dan Text. Vacío si la línea no tiene pareja o no comparte palabras.
+type DiffSegment struct {
+	Op    string        `json:"op"`
+	Text  string        `json:"text"`
+	Words []DiffSegment `json:"words,omitempty"`
+}
+
+// DiffHunk es un bloque de cambios con su contexto, como en un diff unificado.
+//
+// Campos:
+//   - OldStart, OldLines: primera línea (1-based) y cantidad de líneas en el origen.
+//   - NewStart, NewLines: lo mismo en el destino.
+//   - Lines: líneas del bloque en orden.
+type DiffHunk struct {
+	OldStart int           `json:"oldStart"`
+	OldLines int           `json:"oldLines"`
+	NewStart int           `json:"newStart"`
+	NewLines int           `json:"newLines"`
+	Lines    []DiffSegment `json:"lines"`
+}
+
+// diffTokens calcula el script de edición mínimo que transforma a en b.
+// Cada DiffSegment contiene un único token.
+func diffTokens(a, b []string) []DiffSegment {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	out := make([]DiffSegment, 0, len(a)+len(b)-prefix-suffix)
+	for _, t := range a[:prefix] {
+		out = append(out, DiffSegment{Op: DiffEqual, Text: t})
+	}
+	out = append(out, myersDiff(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, t := range a[len(a)-suffix:] {
+		out = append(out, DiffSegment{Op: DiffEqual, Text: t})
+	}
+	return out
+}
+
+// myersDiff aplica el algoritmo O(ND) de Myers guardando cada frontera para
+// reconstruir el camino (sólo las diagonales -d-1..d+1 de cada paso). Si se superan
+// maxDiffCost ediciones retorna a borrado e insertado completos.
+func myersDiff(a, b []string) []DiffSegment {
+	n, m := len(a), len(b)
+	max := n + m
+	if max == 0 {
+		return nil
+	}
+	offset := max + 1
+	v := make([]int, 2*max+3)
+	var trace [][]int
+
+	for d := 0; d <= max && d <= maxDiffCost; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return myersBacktrack(trace, a, b)
+			}
+		}
+	}
+	return replaceAll(a, b)
+}
+
+// myersBacktrack recorre trace desde el final y arma el script en orden.
+func myersBacktrack(trace [][]int, a, b []string) []DiffSegment {
+	var rev []DiffSegment
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d >= 0; d-- {
+		// trace[d] guarda las diagonales -d-1..d+1: la diagonal k está en v[k+offset].
+		v, offset := trace[d], d+1
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			rev = append(rev, DiffSegment{Op: DiffEqual, Text: a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				rev = append(rev, DiffSegment{Op: DiffInsert, Text: b[y-1]})
+			} else {
+				rev = append(rev, DiffSegment{Op: DiffDelete, Text: a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	out := make([]DiffSegment, len(rev))
+	for i, s := range rev {
+		out[len(rev)-1-i] = s
+	}
+	return out
+}
+
+// replaceAll es el script trivial: borrar todo a e insertar todo b.
+func replaceAll(a, b []string) []DiffSegment {
+	out := make([]DiffSegment, 0, len(a)+len(b))
+	for _, t := range a {
+		out = append(out, DiffSegment{Op: DiffDelete, Text: t})
+	}
+	for _, t := range b {
+		out = append(out, DiffSegment{Op: DiffInsert, Text: t})
+	}
+	return out
+}
+
+// splitLines separa s (normalizado a NFC) en líneas sin el "\n" final ("" = ninguna
+// línea); "\r\n" se trata como fin de línea.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.Split(strings.ReplaceAll(norm.NFC.String(s), "\r\n", "\n"), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// splitDiffWords separa s en palabras (letras, dígitos y marcas combinantes), tramos de
+// espacios y signos sueltos; concatenar los tokens reproduce s. A diferencia de
+// splitWords (búsqueda), conserva los separadores.
+func splitDiffWords(s string) []string {
+	s = norm.NFC.String(s)
+	var tokens []string
+	start, class := 0, -1
+	for i, r := range s {
+		c := runeClass(r)
+		if class != -1 && (c != class || c == 2) {
+			tokens = append(tokens, s[start:i])
+			start = i
+		}
+		class = c
+	}
+	if start < len(s) {
+		tokens = append(tokens, s[start:])
+	}
+	return tokens
+}
+
+// runeClass clasifica r en palabra (0), espacio (1) u otro signo (2).
+func runeClass(r rune) int {
+	switch {
+	case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r):
+		return 0
+	case unicode.IsSpace(r):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// mergeSegments une tramos consecutivos con la misma operación.
+func mergeSegments(segs []DiffSegment) []DiffSegment {
+	var out []DiffSegment
+	for _, s := range segs {
+		if n := len(out); n > 0 && out[n-1].Op == s.Op {
+			out[n-1].Text += s.Text
+			continue
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// buildHunks agrupa un script de líneas en hunks con context líneas de contexto.
+func buildHunks(script []DiffSegment, context int) []DiffHunk {
+	var hunks []DiffHunk
+	oldLine, newLine := 1, 1
+	// Posición en script, línea de origen y de destino de cada entrada.
+	type pos struct{ old, new int }
+	at := make([]pos, len(script))
+	for i, s := range script {
+		at[i] = pos{oldLine, newLine}
+		if s.Op != DiffInsert {
+			oldLine++
+		}
+		if s.Op != DiffDelete {
+			newLine++
+		}
+	}
+
+	for i := 0; i < len(script); {
+		if script[i].Op == DiffEqual {
+			i++
+			continue
+		}
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		for start < i && script[start].Op != DiffEqual {
+			start++
+		}
+		// Extiende mientras el próximo cambio quede a menos de 2*context líneas iguales.
+		end := i
+		for end < len(script) {
+			if script[end].Op != DiffEqual {
+				end++
+				continue
+			}
+			run := end
+			for run < len(script) && script[run].Op == DiffEqual {
+				run++
+			}
+			if run == len(script) || run-end > 2*context {
+				end += min(context, run-end)
+				break
+			}
+			end = run
+		}
+
+		h := DiffHunk{OldStart: at[start].old, NewStart: at[start].new, Lines: script[start:end]}
+		for _, s := range h.Lines {
+			if s.Op != DiffInsert {
+				h.OldLines++
+			}
+			if s.Op != DiffDelete {
+				h.NewLines++
+			}
+		}
+		hunks = append(hunks, h)
+		i = end
+	}
+	return hunks
+}
+
+// addWordDiffs completa Words en las líneas de hunks que reemplazan a otra: en cada
+// tramo de líneas borradas seguido de uno de insertadas, la i-ésima borrada se empareja
+// con la i-ésima insertada y ambas reciben su diff por palabras.
+func addWordDiffs(hunks []DiffHunk) {
+	for _, h := range hunks {
+		lines := h.Lines
+		for i := 0; i < len(lines); {
+			if lines[i].Op != DiffDelete {
+				i++
+				continue
+			}
+			del := i
+			for i < len(lines) && lines[i].Op == DiffDelete {
+				i++
+			}
+			ins := i
+			for i < len(lines) && lines[i].Op == DiffInsert {
+				i++
+			}
+			for p := 0; p < ins-del && ins+p < i; p++ {
+				lines[del+p].Words, lines[ins+p].Words = wordDiffPair(lines[del+p].Text, lines[ins+p].Text)
+			}
+		}
+	}
+}
+
+// wordDiffPair retorna el diff por palabras de una línea borrada (from) y la insertada
+// que la reemplaza (to), cada una con sus tramos; nil si no comparten ninguna palabra.
+func wordDiffPair(from, to string) (fromWords, toWords []DiffSegment) {
+	segs := mergeSegments(diffTokens(splitDiffWords(from), splitDiffWords(to)))
+	shared := false
+	for _, s := range segs {
+		if s.Op == DiffEqual && strings.TrimSpace(s.Text) != "" {
+			shared = true
+		}
+		if s.Op != DiffInsert {
+			fromWords = append(fromWords, s)
+		}
+		if s.Op != DiffDelete {
+			toWords = append(toWords, s)
+		}
+	}
+	if !shared {
+		return nil, nil
+	}
+	return mergeSegments(fromWords), mergeSegments(toWords)
+}
+
+// writeUnified escribe en sb el diff unificado de una "sección" (un campo del post)
+// con los encabezados ---/+++ indicados; no escribe nada si no hay cambios.
+func writeUnified(sb *strings.Builder, oldName, newName string, oldLines, newLines []string) {
+	hunks := buildHunks(diffTokens(oldLines, newLines), diffContextLines)
+	if len(hunks) == 0 {
+		return
+	}
+	fmt.Fprintf(sb, "--- %s\n+++ %s\n", oldName, newName)
+	for _, h := range hunks {
+		fmt.Fprintf(sb, "@@ -%s +%s @@\n", unifiedRange(h.OldStart, h.OldLines), unifiedRange(h.NewStart, h.NewLines))
+		for _, l := range h.Lines {
+			switch l.Op {
+			case DiffDelete:
+				sb.WriteByte('-')
+			case DiffInsert:
+				sb.WriteByte('+')
+			default:
+				sb.WriteByte(' ')
+			}
+			sb.WriteString(l.Text)
+			sb.WriteByte('\n')
+		}
+	}
+}
+
+// unifiedRange formatea "start,count" como GNU diff: count 1 se omite y un rango
+// vacío indica la línea anterior a la posición.
+func unifiedRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start-1)
+	case 1:
+		return fmt.Sprintf("%d", start)
+	default:
+		return fmt.Sprintf("%d,%d", start, count)
+	}
+}
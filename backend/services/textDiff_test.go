@@ -0,0 +1,221 @@
+package services
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// applyScript reconstruye el origen (equal + delete) y el destino (equal + insert) de script.
+func applyScript(script []DiffSegment) (from, to []string) {
+	for _, s := range script {
+		if s.Op != DiffInsert {
+			from = append(from, s.Text)
+		}
+		if s.Op != DiffDelete {
+			to = append(to, s.Text)
+		}
+	}
+	return from, to
+}
+
+// minEdits es la cantidad mínima de borrados e inserciones de a a b (por LCS).
+func minEdits(a, b []string) int {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	return len(a) + len(b) - 2*lcs[0][0]
+}
+
+func TestDiffTokensIsMinimal(t *testing.T) {
+	cases := [][2]string{
+		{"", ""},
+		{"abc", ""},
+		{"", "abc"},
+		{"abc", "abc"},
+		{"abcabba", "cbabac"},
+		{"abcdef", "azcdxf"},
+		{"aaaa", "aa"},
+		{"xaxbxc", "abc"},
+	}
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		var a, b strings.Builder
+		for j := rng.Intn(12); j > 0; j-- {
+			a.WriteByte("abc"[rng.Intn(3)])
+		}
+		for j := rng.Intn(12); j > 0; j-- {
+			b.WriteByte("abc"[rng.Intn(3)])
+		}
+		cases = append(cases, [2]string{a.String(), b.String()})
+	}
+
+	for _, c := range cases {
+		a, b := strings.Split(c[0], ""), strings.Split(c[1], "")
+		if c[0] == "" {
+			a = nil
+		}
+		if c[1] == "" {
+			b = nil
+		}
+		script := diffTokens(a, b)
+		from, to := applyScript(script)
+		if !reflect.DeepEqual(from, a) || !reflect.DeepEqual(to, b) {
+			t.Fatalf("%q → %q: el script no reproduce los textos (%v)", c[0], c[1], script)
+		}
+		edits := 0
+		for _, s := range script {
+			if s.Op != DiffEqual {
+				edits++
+			}
+		}
+		if want := minEdits(a, b); edits != want {
+			t.Fatalf("%q → %q: %d ediciones, want %d (%v)", c[0], c[1], edits, want, script)
+		}
+	}
+}
+
+func TestSplitDiffWordsIsRuneAware(t *testing.T) {
+	got := splitDiffWords("Introducción a MongoDB, año 2024")
+	want := []string{"Introducción", " ", "a", " ", "MongoDB", ",", " ", "año", " ", "2024"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("tokens %q, want %q", got, want)
+	}
+
+	// "ó" con acento combinante se normaliza a NFC y no parte la palabra.
+	if got := splitDiffWords("Introduccio\u0301n"); !reflect.DeepEqual(got, []string{"Introducción"}) {
+		t.Fatalf("NFD: %q", got)
+	}
+	segs := diffTokens(splitDiffWords("Introducción"), splitDiffWords("Introduccio\u0301n"))
+	if changedScript(segs) {
+		t.Fatalf("NFC y NFD difieren: %v", segs)
+	}
+}
+
+func TestSplitLines(t *testing.T) {
+	if got := splitLines(""); got != nil {
+		t.Fatalf("vacío: %q", got)
+	}
+	if got := splitLines("uno\r\ndos\n"); !reflect.DeepEqual(got, []string{"uno", "dos"}) {
+		t.Fatalf("líneas: %q", got)
+	}
+}
+
+func TestBuildHunksContext(t *testing.T) {
+	var a []string
+	for i := 1; i <= 20; i++ {
+		a = append(a, strings.Repeat("x", i))
+	}
+	b := append([]string(nil), a...)
+	b[1] = "cambio dos"
+	b[17] = "cambio dieciocho"
+
+	hunks := buildHunks(diffTokens(a, b), diffContextLines)
+	if len(hunks) != 2 {
+		t.Fatalf("hunks: %d, want 2", len(hunks))
+	}
+	first, second := hunks[0], hunks[1]
+	if first.OldStart != 1 || first.OldLines != 5 || first.NewStart != 1 || first.NewLines != 5 {
+		t.Fatalf("primer hunk: %+v", first)
+	}
+	if second.OldStart != 15 || second.OldLines != 6 || second.NewStart != 15 || second.NewLines != 6 {
+		t.Fatalf("segundo hunk: %+v", second)
+	}
+
+	// Cambios a menos de 2*context líneas se unen en un solo hunk.
+	b = append([]string(nil), a...)
+	b[5], b[10] = "cinco", "diez"
+	if hunks := buildHunks(diffTokens(a, b), diffContextLines); len(hunks) != 1 {
+		t.Fatalf("hunks cercanos: %d, want 1", len(hunks))
+	}
+}
+
+func TestAddWordDiffs(t *testing.T) {
+	a := splitLines("Título\nLa migración usa índices.\nFin")
+	b := splitLines("Título\nLa migración crea índices únicos.\nLínea nueva\nFin")
+	hunks := buildHunks(diffTokens(a, b), diffContextLines)
+	addWordDiffs(hunks)
+	if len(hunks) != 1 {
+		t.Fatalf("hunks: %d, want 1", len(hunks))
+	}
+
+	var del, ins, added *DiffSegment
+	for i := range hunks[0].Lines {
+		l := &hunks[0].Lines[i]
+		switch {
+		case l.Op == DiffDelete:
+			del = l
+		case l.Op == DiffInsert && ins == nil:
+			ins = l
+		case l.Op == DiffInsert:
+			added = l
+		}
+	}
+	if del == nil || ins == nil || added == nil {
+		t.Fatalf("líneas: %+v", hunks[0].Lines)
+	}
+	wantDel := []DiffSegment{
+		{Op: DiffEqual, Text: "La migración "}, {Op: DiffDelete, Text: "usa"}, {Op: DiffEqual, Text: " índices."},
+	}
+	wantIns := []DiffSegment{
+		{Op: DiffEqual, Text: "La migración "}, {Op: DiffInsert, Text: "crea"}, {Op: DiffEqual, Text: " índices"},
+		{Op: DiffInsert, Text: " únicos"}, {Op: DiffEqual, Text: "."},
+	}
+	if !reflect.DeepEqual(del.Words, wantDel) {
+		t.Fatalf("palabras borradas: %+v", del.Words)
+	}
+	if !reflect.DeepEqual(ins.Words, wantIns) {
+		t.Fatalf("palabras insertadas: %+v", ins.Words)
+	}
+	if added.Words != nil {
+		t.Fatalf("línea sin pareja con palabras: %+v", added.Words)
+	}
+
+	// Reemplazar una línea por otra sin palabras en común no agrega Words.
+	hunks = buildHunks(diffTokens([]string{"uno dos"}, []string{"tres cuatro"}), diffContextLines)
+	addWordDiffs(hunks)
+	for _, l := range hunks[0].Lines {
+		if l.Words != nil {
+			t.Fatalf("línea sin palabras en común: %+v", l)
+		}
+	}
+}
+
+func TestWriteUnified(t *testing.T) {
+	var sb strings.Builder
+	writeUnified(&sb, "content@v1", "content@v2",
+		splitLines("Introducción\nañadir índice\nFin"), splitLines("Introducción\nañadir índice único\nFin\nAnexo"))
+	want := "--- content@v1\n+++ content@v2\n" +
+		"@@ -1,3 +1,4 @@\n" +
+		" Introducción\n" +
+		"-añadir índice\n" +
+		"+añadir índice único\n" +
+		" Fin\n" +
+		"+Anexo\n"
+	if sb.String() != want {
+		t.Fatalf("unified:\n%s\nwant:\n%s", sb.String(), want)
+	}
+
+	sb.Reset()
+	writeUnified(&sb, "a", "b", []string{"igual"}, []string{"igual"})
+	if sb.Len() != 0 {
+		t.Fatalf("sin cambios: %q", sb.String())
+	}
+
+	sb.Reset()
+	writeUnified(&sb, "tags@v1", "tags@v2", nil, []string{"go"})
+	if want := "--- tags@v1\n+++ tags@v2\n@@ -0,0 +1 @@\n+go\n"; sb.String() != want {
+		t.Fatalf("desde vacío: %q, want %q", sb.String(), want)
+	}
+}
@@ -0,0 +1,113 @@
+// services/textSearch.go
+//
+// Paquete services: búsqueda de texto para los drivers que no cuentan con $text.
+//
+// Convenciones:
+//   - Emula la semántica de $text de MongoDB sobre title+content:
+//     términos sueltos en OR, "frases" obligatorias y -términos excluyentes.
+//   - La comparación ignora mayúsculas y diacríticos ("introducción" == "introduccion").
+//   - No aplica stemming ni stop words; es una aproximación suficiente para demos y tests.
+package services
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// textQuery es una consulta $search ya parseada y normalizada.
+type textQuery struct {
+	terms   []string
+	phrases []string
+	negated []string
+}
+
+// parseTextQuery separa q en términos, frases entre comillas y términos negados.
+func parseTextQuery(q string) textQuery {
+	var tq textQuery
+	rest := q
+	for {
+		start := strings.IndexByte(rest, '"')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(rest[start+1:], '"')
+		if end < 0 {
+			break
+		}
+		phrase := strings.Join(splitWords(foldText(rest[start+1:start+1+end])), " ")
+		if phrase != "" {
+			tq.phrases = append(tq.phrases, phrase)
+		}
+		rest = rest[:start] + " " + rest[start+1+end+1:]
+	}
+
+	for _, field := range strings.Fields(rest) {
+		negate := strings.HasPrefix(field, "-")
+		for _, w := range splitWords(foldText(field)) {
+			if negate {
+				tq.negated = append(tq.negated, w)
+			} else {
+				tq.terms = append(tq.terms, w)
+			}
+		}
+	}
+	return tq
+}
+
+// matches evalúa la consulta sobre un texto arbitrario (típicamente title + " " + content).
+//
+// Reglas:
+//   - Cualquier término negado presente descarta el documento.
+//   - Si hay frases, todas deben aparecer; los términos sueltos no son obligatorios.
+//   - Si no hay frases, basta con que aparezca cualquiera de los términos.
+func (tq textQuery) matches(text string) bool {
+	words := splitWords(foldText(text))
+	set := make(map[string]struct{}, len(words))
+	for _, w := range words {
+		set[w] = struct{}{}
+	}
+
+	for _, n := range tq.negated {
+		if _, ok := set[n]; ok {
+			return false
+		}
+	}
+
+	if len(tq.phrases) > 0 {
+		joined := " " + strings.Join(words, " ") + " "
+		for _, ph := range tq.phrases {
+			if !strings.Contains(joined, " "+ph+" ") {
+				return false
+			}
+		}
+		return true
+	}
+
+	for _, t := range tq.terms {
+		if _, ok := set[t]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// foldText pasa s a minúsculas y elimina diacríticos (NFD + remoción de marcas).
+func foldText(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	out, _, err := transform.String(t, s)
+	if err != nil {
+		return strings.ToLower(s)
+	}
+	return strings.ToLower(out)
+}
+
+// splitWords separa s en palabras (letras y dígitos).
+func splitWords(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
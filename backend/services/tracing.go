@@ -0,0 +1,41 @@
+// services/tracing.go
+//
+// Paquete services: spans de OpenTelemetry para los casos de uso.
+//
+// Convenciones:
+//   - Cada función pública de un servicio abre un span "<Servicio>.<Método>" con startSpan
+//     y lo cierra con endSpan usando un error de retorno con nombre.
+//   - Los comandos Mongo generan sus propios spans hijos (monitor otelmongo, ver mongoService.go).
+//   - El span registra el sentinel del error (atributo error.sentinel); sólo ErrDB y
+//     errores desconocidos marcan el span con status Error.
+package services
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer es el tracer de la capa de servicios (provider global, ver paquete tracing).
+var tracer = otel.Tracer("blog-api/services")
+
+// startSpan abre un span hijo del activo en ctx.
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan cierra span registrando *err. Se usa con defer.
+func endSpan(span trace.Span, err *error) {
+	if *err != nil {
+		span.SetAttributes(attribute.String("error.sentinel", SentinelName(*err)))
+		if errors.Is(*err, ErrDB) || SentinelName(*err) == "unknown" {
+			span.RecordError(*err)
+			span.SetStatus(codes.Error, (*err).Error())
+		}
+	}
+	span.End()
+}
@@ -0,0 +1,35 @@
+// services/transitionRepository.go
+//
+// Paquete services: contrato de persistencia del historial de estados de posts.
+//
+// Convenciones:
+//   - Igual que RevisionRepository: PostService depende de la interfaz, las
+//     implementaciones envuelven sus errores con sentinelas y las reglas (qué
+//     transiciones son válidas) viven en PostService.
+//   - El historial sólo crece: no hay edición ni borrado salvo al purgar el post.
+package services
+
+import (
+	"context"
+
+	"blog-api/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TransitionRepository define las operaciones de almacenamiento de transiciones.
+//
+// Implementaciones:
+//   - MongoTransitionRepository: colección "post_transitions" en MongoDB.
+//   - MemoryTransitionRepository: mapa en memoria.
+//   - SQLiteTransitionRepository: tabla post_transitions.
+//
+// Errores esperados:
+//   - ErrDB ante fallas del almacenamiento.
+type TransitionRepository interface {
+	// Create guarda t.
+	Create(ctx context.Context, t models.PostTransition) error
+	// List retorna las transiciones de postID, de la más nueva a la más vieja.
+	List(ctx context.Context, postID primitive.ObjectID) ([]models.PostTransition, error)
+	// DeleteByPosts borra el historial completo de los posts indicados.
+	DeleteByPosts(ctx context.Context, postIDs []primitive.ObjectID) (int64, error)
+}
@@ -0,0 +1,112 @@
+// tracing/tracing.go
+//
+// Paquete tracing: inicialización de OpenTelemetry (trazas distribuidas).
+//
+// Convenciones:
+//   - Setup instala el TracerProvider y el propagador globales (otel.SetTracerProvider,
+//     otel.SetTextMapPropagator); el resto del código obtiene tracers con otel.Tracer(...).
+//   - Los spans se crean en tres capas: request HTTP (otelgin), funciones de services
+//     y comandos Mongo (monitor de comandos del driver, otelmongo).
+//   - El contexto se propaga con W3C Trace Context (traceparent/tracestate) y Baggage.
+//   - Con el exporter "none" el provider es no-op: la instrumentación queda instalada
+//     pero no registra ni exporta nada.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// Exporters soportados.
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// Options agrupa la configuración de trazas.
+//
+// Campos:
+//   - Exporter: "none" (default), "stdout" (JSON en stderr) u "otlp" (OTLP/HTTP).
+//   - OTLPEndpoint: host:puerto del colector OTLP/HTTP (ej. "localhost:4318").
+//   - OTLPInsecure: usa HTTP plano hacia el colector (colector local).
+//   - ServiceName: atributo service.name de los spans.
+//   - SampleRatio: fracción de trazas raíz muestreadas (0..1); los hijos respetan al padre.
+type Options struct {
+	Exporter     string
+	OTLPEndpoint string
+	OTLPInsecure bool
+	ServiceName  string
+	SampleRatio  float64
+}
+
+// Setup configura el TracerProvider global según opts.
+//
+// Retornos:
+//   - shutdown: vacía los spans pendientes y cierra el exporter; debe llamarse al apagar.
+//   - error si el exporter no es soportado o no puede crearse.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	switch opts.Exporter {
+	case "", ExporterNone:
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(os.Stderr))
+		if err != nil {
+			return nil, fmt.Errorf("stdout exporter: %w", err)
+		}
+		exporter = exp
+	case ExporterOTLP:
+		httpOpts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(opts.OTLPEndpoint)}
+		if opts.OTLPInsecure {
+			httpOpts = append(httpOpts, otlptracehttp.WithInsecure())
+		}
+		exp, err := otlptracehttp.New(ctx, httpOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("otlp exporter: %w", err)
+		}
+		exporter = exp
+	default:
+		return nil, errors.New("tracing: exporter no soportado: " + opts.Exporter)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL, semconv.ServiceName(opts.ServiceName)))
+	if err != nil {
+		return nil, fmt.Errorf("tracing resource: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// SkipInfraRoutes es un filtro para otelgin que excluye probes y /metrics,
+// requests frecuentes que no aportan a las trazas.
+func SkipInfraRoutes(r *http.Request) bool {
+	switch r.URL.Path {
+	case "/healthz", "/livez", "/readyz", "/metrics":
+		return false
+	}
+	return true
+}